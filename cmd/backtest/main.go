@@ -0,0 +1,133 @@
+// Backtest replays recorded Polymarket market-channel data through the
+// Avellaneda-Stoikov Maker and prints PnL, fill rate, inventory, and
+// toxicity statistics. No orders are sent to the exchange.
+//
+// Usage:
+//
+//	go run ./cmd/backtest -condition <condition id> -from 2026-10-01 -to 2026-10-16 -yes <yes token> -no <no token>
+//	go run ./cmd/backtest -data events.jsonl.gz -yes <yes token> -no <no token>
+//
+// By default the feed recorder's output (store.recorder.enabled) is
+// replayed: every segment recorded for -condition under -data-dir between
+// -from and -to (UTC days, inclusive), in any compression the recorder
+// writes. -data replays a single capture of raw WS market messages instead,
+// one per line. Only "book", "price_change" and "last_trade_price" events
+// are replayed. Strategy and risk parameters come from the same YAML config
+// as the bot.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"polymarket-mm/internal/backtest"
+	"polymarket-mm/internal/config"
+	"polymarket-mm/pkg/types"
+)
+
+func main() {
+	cfgPath := flag.String("config", "configs/config.yaml", "path to bot config (strategy + risk sections are used)")
+	dataDir := flag.String("data-dir", "data", "bot data directory holding recordings/")
+	fromDay := flag.String("from", "", "first UTC day to replay, YYYY-MM-DD (default: earliest recorded)")
+	toDay := flag.String("to", "", "last UTC day to replay, YYYY-MM-DD (default: latest recorded)")
+	dataPath := flag.String("data", "", "replay this JSONL (optionally .gz) capture of market-channel messages instead of recordings")
+	yesToken := flag.String("yes", "", "YES token ID")
+	noToken := flag.String("no", "", "NO token ID")
+	conditionID := flag.String("condition", "backtest", "condition ID used to label the market")
+	tickSize := flag.String("tick", string(types.Tick001), "market tick size (0.1, 0.01, 0.001, 0.0001)")
+	minSize := flag.Float64("min-size", 5, "minimum order size in tokens")
+	csvPath := flag.String("csv", "", "optional path to write the inventory path as CSV")
+	logLevel := flag.String("log-level", "warn", "log level for strategy output")
+	flag.Parse()
+
+	if *yesToken == "" || *noToken == "" {
+		fmt.Fprintln(os.Stderr, "-yes and -no are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	from, err := parseDay(*fromDay)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "-from:", err)
+		os.Exit(2)
+	}
+	to, err := parseDay(*toDay)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "-to:", err)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*cfgPath)
+	if err != nil {
+		slog.Error("failed to load config", "error", err, "path", *cfgPath)
+		os.Exit(1)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: parseLogLevel(*logLevel)}))
+
+	var events []backtest.Event
+	if *dataPath != "" {
+		events, err = backtest.LoadFile(*dataPath)
+	} else {
+		events, err = backtest.LoadRecording(*dataDir, *conditionID, from, to)
+	}
+	if err != nil {
+		logger.Error("failed to load events", "error", err, "data", *dataPath, "data_dir", *dataDir, "market", *conditionID)
+		os.Exit(1)
+	}
+
+	info := types.MarketInfo{
+		ConditionID:  *conditionID,
+		Slug:         *conditionID,
+		YesTokenID:   *yesToken,
+		NoTokenID:    *noToken,
+		TickSize:     types.TickSize(*tickSize),
+		MinOrderSize: *minSize,
+	}
+
+	runner, err := backtest.NewRunner(*cfg, info, logger)
+	if err != nil {
+		logger.Error("failed to create backtest", "error", err)
+		os.Exit(1)
+	}
+
+	report := runner.Run(context.Background(), events)
+	report.WriteSummary(os.Stdout)
+
+	if *csvPath != "" {
+		f, err := os.Create(*csvPath)
+		if err != nil {
+			logger.Error("failed to create csv", "error", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if err := report.WriteInventoryCSV(f); err != nil {
+			logger.Error("failed to write csv", "error", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// parseDay parses a YYYY-MM-DD flag as a UTC day; empty means unbounded.
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, s)
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
@@ -25,6 +25,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -105,6 +106,18 @@ func main() {
 	sig := <-sigCh
 	logger.Info("received shutdown signal", "signal", sig.String())
 
+	// Wind markets down; a second signal skips straight to stopping
+	drainCtx, drainCancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-sigCh:
+			drainCancel()
+		case <-drainCtx.Done():
+		}
+	}()
+	eng.Drain(drainCtx)
+	drainCancel()
+
 	// Stop dashboard first
 	if apiServer != nil {
 		if err := apiServer.Stop(); err != nil {
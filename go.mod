@@ -7,6 +7,7 @@ require (
 	github.com/ethereum/go-ethereum v1.16.8
 	github.com/go-resty/resty/v2 v2.17.1
 	github.com/gorilla/websocket v1.5.3
+	github.com/klauspost/compress v1.16.0
 	github.com/shopspring/decimal v1.4.0
 	github.com/spf13/viper v1.21.0
 )
@@ -8,7 +8,7 @@ import (
 
 // DashboardEvent is the wrapper for all events sent to the dashboard
 type DashboardEvent struct {
-	Type      string      `json:"type"`      // "snapshot", "fill", "order", "position", "kill"
+	Type      string      `json:"type"`      // "snapshot", "fill", "order", "position", "kill", "reconcile"
 	Timestamp time.Time   `json:"timestamp"` // Event time
 	MarketID  string      `json:"market_id"` // Condition ID (empty for global events)
 	Data      interface{} `json:"data"`      // Event-specific payload
@@ -60,6 +60,21 @@ type KillEvent struct {
 	MarketID string    `json:"market_id,omitempty"`
 }
 
+// ReconcileEvent is emitted when local state was corrected against the exchange
+type ReconcileEvent struct {
+	MarketSlug    string   `json:"market_slug"`
+	Trigger       string   `json:"trigger"`        // "startup" or "periodic"
+	Adopted       int      `json:"adopted"`        // resting orders taken over
+	Cancelled     int      `json:"cancelled"`      // resting orders cancelled
+	MissedFills   int      `json:"missed_fills"`   // fills replayed into inventory
+	YesQty        float64  `json:"yes_qty"`        // after reconciliation
+	NoQty         float64  `json:"no_qty"`         // after reconciliation
+	AvgEntryYes   float64  `json:"avg_entry_yes"`  // after reconciliation
+	AvgEntryNo    float64  `json:"avg_entry_no"`   // after reconciliation
+	Seeded        int      `json:"seeded"`         // unexplained balances given an entry price
+	Discrepancies []string `json:"discrepancies"`
+}
+
 // QuoteEvent represents current bid/ask quotes
 type QuoteEvent struct {
 	MarketSlug       string   `json:"market_slug"`
@@ -134,3 +149,17 @@ func NewKillEvent(reason, details string, until time.Time, marketID string) Kill
 		MarketID: marketID,
 	}
 }
+
+// NewReconcileEvent creates a reconciliation event
+func NewReconcileEvent(marketSlug, trigger string, adopted, cancelled, missedFills int, yesQty, noQty float64, discrepancies []string) ReconcileEvent {
+	return ReconcileEvent{
+		MarketSlug:    marketSlug,
+		Trigger:       trigger,
+		Adopted:       adopted,
+		Cancelled:     cancelled,
+		MissedFills:   missedFills,
+		YesQty:        yesQty,
+		NoQty:         noQty,
+		Discrepancies: discrepancies,
+	}
+}
@@ -12,6 +12,12 @@ import (
 	"polymarket-mm/internal/config"
 )
 
+// MarketReleaser is implemented by providers that can let the scanner drop
+// a market still holding inventory.
+type MarketReleaser interface {
+	ReleaseMarket(conditionID string) error
+}
+
 // Handlers holds all HTTP handler dependencies
 type Handlers struct {
 	provider MarketSnapshotProvider
@@ -48,6 +54,39 @@ func (h *Handlers) HandleSnapshot(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleReleaseMarket releases the market given by the condition_id query
+// parameter, so the next scan that doesn't select it stops it even though it
+// holds inventory. POST only, from an allowed origin like the WebSocket.
+func (h *Handlers) HandleReleaseMarket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isOriginAllowed(r.Header.Get("Origin"), h.cfg.Dashboard, r.Host) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	conditionID := r.URL.Query().Get("condition_id")
+	if conditionID == "" {
+		http.Error(w, "condition_id is required", http.StatusBadRequest)
+		return
+	}
+	releaser, ok := h.provider.(MarketReleaser)
+	if !ok {
+		http.Error(w, "releasing markets is not supported", http.StatusNotImplemented)
+		return
+	}
+	if err := releaser.ReleaseMarket(conditionID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	h.logger.Info("market released from dashboard", "condition_id", conditionID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "released", "condition_id": conditionID})
+}
+
 // HandleWebSocket upgrades the connection and creates a new WebSocket client
 func (h *Handlers) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	upgrader := websocket.Upgrader{
@@ -1,6 +1,11 @@
 package api
 
 import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"polymarket-mm/internal/config"
@@ -70,3 +75,59 @@ func TestIsOriginAllowed(t *testing.T) {
 		})
 	}
 }
+
+type releaseProvider struct {
+	MarketSnapshotProvider
+	released []string
+}
+
+func (p *releaseProvider) ReleaseMarket(conditionID string) error {
+	if conditionID != "cond-1" {
+		return fmt.Errorf("market %s is not running", conditionID)
+	}
+	p.released = append(p.released, conditionID)
+	return nil
+}
+
+func TestHandleReleaseMarket(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	provider := &releaseProvider{}
+	h := NewHandlers(provider, config.Config{}, nil, logger)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		origin string
+		want   int
+	}{
+		{"releases a running market", http.MethodPost, "/api/markets/release?condition_id=cond-1", "", http.StatusOK},
+		{"unknown market", http.MethodPost, "/api/markets/release?condition_id=cond-2", "", http.StatusNotFound},
+		{"missing condition id", http.MethodPost, "/api/markets/release", "", http.StatusBadRequest},
+		{"GET is rejected", http.MethodGet, "/api/markets/release?condition_id=cond-1", "", http.StatusMethodNotAllowed},
+		{"foreign origin is rejected", http.MethodPost, "/api/markets/release?condition_id=cond-1", "https://evil.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		h.HandleReleaseMarket(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+	if len(provider.released) != 1 || provider.released[0] != "cond-1" {
+		t.Errorf("released %v, want [cond-1]", provider.released)
+	}
+
+	// Providers that can't release markets say so
+	h = NewHandlers(nil, config.Config{}, nil, logger)
+	rec := httptest.NewRecorder()
+	h.HandleReleaseMarket(rec, httptest.NewRequest(http.MethodPost, "/api/markets/release?condition_id=cond-1", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status %d without a releaser, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
@@ -36,6 +36,7 @@ func NewServer(
 	// API routes
 	mux.HandleFunc("/health", handlers.HandleHealth)
 	mux.HandleFunc("/api/snapshot", handlers.HandleSnapshot)
+	mux.HandleFunc("/api/markets/release", handlers.HandleReleaseMarket)
 	mux.HandleFunc("/ws", handlers.HandleWebSocket)
 
 	// Serve static files (web dashboard)
@@ -43,6 +43,12 @@ func BuildSnapshot(
 		MarketsSelected:  len(markets),
 	}
 
+	// Rate-limit stats are optional: only the live engine has a CLOB client
+	var rateLimits []RateLimitStatus
+	if rl, ok := provider.(interface{ RateLimitStats() []RateLimitStatus }); ok {
+		rateLimits = rl.RateLimitStats()
+	}
+
 	return DashboardSnapshot{
 		Timestamp:       time.Now(),
 		Markets:         markets,
@@ -52,6 +58,7 @@ func BuildSnapshot(
 		Risk:            convertRiskSnapshot(riskSnap),
 		Config:          NewConfigSummary(cfg),
 		Scanner:         scannerInfo,
+		RateLimits:      rateLimits,
 	}
 }
 
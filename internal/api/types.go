@@ -26,6 +26,19 @@ type DashboardSnapshot struct {
 
 	// Scanner info
 	Scanner ScannerInfo `json:"scanner"`
+
+	// CLOB client rate limiting, one entry per bucket
+	RateLimits []RateLimitStatus `json:"rate_limits,omitempty"`
+}
+
+// RateLimitStatus reports how much one rate-limit bucket has delayed requests
+type RateLimitStatus struct {
+	Category    string  `json:"category"`
+	Requests    int64   `json:"requests"`
+	Delayed     int64   `json:"delayed"`
+	TotalWaitMs float64 `json:"total_wait_ms"`
+	MaxWaitMs   float64 `json:"max_wait_ms"`
+	Throttled   int64   `json:"throttled"` // 429 responses
 }
 
 // MarketStatus represents per-market state
@@ -46,6 +59,11 @@ type MarketStatus struct {
 	// Position
 	Position PositionSnapshot `json:"position"`
 
+	// Volatility used for quoting (sigma prior until the estimator is warm)
+	Volatility  float64 `json:"volatility"`
+	RealizedVol float64 `json:"realized_vol"`
+	VolWarm     bool    `json:"vol_warm"`
+
 	// Current quotes (if active)
 	ActiveBid        *QuoteInfo `json:"active_bid,omitempty"`
 	ActiveAsk        *QuoteInfo `json:"active_ask,omitempty"`
@@ -57,6 +75,30 @@ type MarketStatus struct {
 	EndDate   time.Time `json:"end_date"`
 	Liquidity float64   `json:"liquidity"`
 	Volume24h float64   `json:"volume_24h"`
+
+	// Depth imbalance of the YES book in [-1, 1] (positive: bid-heavy) and
+	// the size-weighted touch mid
+	Imbalance  float64 `json:"imbalance"`
+	Microprice float64 `json:"microprice,omitempty"`
+
+	// Post-fill markouts per side and horizon (lifetime)
+	Markouts []MarkoutInfo `json:"markouts,omitempty"`
+
+	// Sides the trade tape detectors are defending (widened or pulled)
+	TapeDefendBid bool `json:"tape_defend_bid"`
+	TapeDefendAsk bool `json:"tape_defend_ask"`
+
+	// Draining: quoting only to reduce inventory before being removed
+	Draining bool `json:"draining"`
+
+	// Neg-risk event this market is an outcome of, and the sum of the
+	// event's YES prices (about 1 when coherent)
+	EventSlug       string  `json:"event_slug,omitempty"`
+	EventImpliedSum float64 `json:"event_implied_sum,omitempty"`
+
+	// Liquidity rewards (zero when the market has no rewards program)
+	RewardShare         float64 `json:"reward_share"`
+	ExpectedRewardDaily float64 `json:"expected_reward_daily"` // USDC
 }
 
 // PositionSnapshot represents position and P&L for a market
@@ -65,8 +107,9 @@ type PositionSnapshot struct {
 	NoQty         float64 `json:"no_qty"`
 	AvgEntryYes   float64 `json:"avg_entry_yes"`
 	AvgEntryNo    float64 `json:"avg_entry_no"`
-	RealizedPnL   float64 `json:"realized_pnl"`
+	RealizedPnL   float64 `json:"realized_pnl"` // net of fees
 	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	FeesPaid      float64 `json:"fees_paid"`
 	ExposureUSD   float64 `json:"exposure_usd"`
 	Skew          float64 `json:"skew"` // NetDelta in [-1, 1]
 	LastUpdated   time.Time `json:"last_updated"`
@@ -80,6 +123,16 @@ type QuoteInfo struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// MarkoutInfo is the average markout of our fills on one side at one
+// horizon after the fill. Positive means the mid moved our way; persistently
+// negative means we are being adversely selected.
+type MarkoutInfo struct {
+	Side    string  `json:"side"`    // BUY: our bids, SELL: our asks (YES terms)
+	Horizon string  `json:"horizon"` // e.g. "30s"
+	Fills   int     `json:"fills"`
+	Average float64 `json:"average"` // size-weighted, in price units
+}
+
 // RiskSnapshot represents aggregate risk metrics
 type RiskSnapshot struct {
 	// Exposure
@@ -0,0 +1,255 @@
+// Package backtest replays recorded Polymarket market data through the
+// Avellaneda-Stoikov Maker without touching the live exchange.
+//
+// A backtest run has three parts:
+//
+//   - Events: "book", "price_change" and "last_trade_price" messages from the
+//     market WS channel, decoded and ordered by their exchange timestamps.
+//   - SimExchange: an in-memory order gateway that rests the Maker's orders
+//     and fills them against replayed trades using a queue-position model.
+//   - Runner: advances a simulated clock through the events, applies them to
+//     a market.Book, steps the Maker every RefreshInterval, and collects PnL,
+//     fill rate, inventory path, and toxicity statistics into a Report.
+package backtest
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"polymarket-mm/internal/store"
+	"polymarket-mm/pkg/types"
+)
+
+// Event is one replayable market-channel message. Exactly one of Book,
+// PriceChange, or Trade is set.
+type Event struct {
+	Time        time.Time
+	Book        *types.WSBookEvent
+	PriceChange *types.WSPriceChangeEvent
+	Trade       *types.WSLastTradePriceEvent
+}
+
+// maxLineSize bounds a single JSONL line (full book snapshots can be large).
+const maxLineSize = 16 * 1024 * 1024
+
+// LoadRecording reads a market's recorded feed for the UTC days [from, to]
+// (zero times leave that end open): every segment store.RecordingFiles
+// finds, in order, through a store.RecordingReader, so compressed and
+// crash-torn segments read the same way the recorder wrote them.
+func LoadRecording(dataDir, market string, from, to time.Time) ([]Event, error) {
+	paths, err := store.RecordingFiles(dataDir, market, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no recordings of %s in %s", market, dataDir)
+	}
+	rr := store.OpenRecording(paths...)
+	defer rr.Close()
+	return ReadRecording(rr)
+}
+
+// ReadRecording drains a recording reader into replayable events, with the
+// same timestamp fallbacks and ordering as ReadEvents. Frames the
+// backtester does not replay, user-channel ones included, are skipped.
+func ReadRecording(rr *store.RecordingReader) ([]Event, error) {
+	var events []Event
+	var last time.Time
+	for {
+		rec, err := rr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var evt Event
+		switch {
+		case rec.Book != nil:
+			evt = Event{Time: ParseTimestamp(rec.Book.Timestamp), Book: rec.Book}
+		case rec.PriceChange != nil:
+			evt = Event{Time: ParseTimestamp(rec.PriceChange.Timestamp), PriceChange: rec.PriceChange}
+		case rec.LastTrade != nil:
+			evt = Event{Time: ParseTimestamp(rec.LastTrade.Timestamp), Trade: rec.LastTrade}
+		default:
+			continue
+		}
+		if evt.Time.IsZero() {
+			evt.Time = rec.RecvTime
+		}
+		if evt.Time.IsZero() {
+			evt.Time = last
+		}
+		last = evt.Time
+		events = append(events, evt)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, nil
+}
+
+// LoadFile reads events from a single JSONL capture of raw WS messages,
+// transparently decompressing files ending in .gz. Use LoadRecording for
+// the feed recorder's output.
+func LoadFile(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open events: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("open gzip: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return ReadEvents(r)
+}
+
+// ReadEvents decodes newline-delimited market-channel messages as sent by
+// the WS server. A line may hold a single event object or an array of them
+// (the initial book dump is an array), or a store.Frame written by the feed
+// recorder. Events without a usable timestamp fall back to the recorder's
+// receive time, then to the previous event's time. The result is stably
+// sorted by time.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var events []Event
+	var last time.Time
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		data, recv := unwrapFrame(line)
+		decoded, err := DecodeMessage(data)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		for _, evt := range decoded {
+			if evt.Time.IsZero() {
+				evt.Time = recv
+			}
+			if evt.Time.IsZero() {
+				evt.Time = last
+			}
+			last = evt.Time
+			events = append(events, evt)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read events: %w", err)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, nil
+}
+
+// unwrapFrame returns the raw event and receive time from a recorder line,
+// or the line itself and a zero time if it is a bare WS message.
+func unwrapFrame(line []byte) ([]byte, time.Time) {
+	if line[0] != '{' || !bytes.Contains(line, []byte(`"recv_ns"`)) {
+		return line, time.Time{}
+	}
+	var frame store.Frame
+	if err := json.Unmarshal(line, &frame); err != nil || len(frame.Data) == 0 {
+		return line, time.Time{}
+	}
+	return frame.Data, frame.RecvTime()
+}
+
+// DecodeMessage decodes one raw WS frame into zero or more events. Event
+// types the backtester does not replay are skipped.
+func DecodeMessage(data []byte) ([]Event, error) {
+	if len(data) > 0 && data[0] == '[' {
+		var raws []json.RawMessage
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, fmt.Errorf("unmarshal array: %w", err)
+		}
+		var events []Event
+		for _, raw := range raws {
+			evt, ok, err := decodeEvent(raw)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				events = append(events, evt)
+			}
+		}
+		return events, nil
+	}
+
+	evt, ok, err := decodeEvent(data)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []Event{evt}, nil
+}
+
+func decodeEvent(data []byte) (Event, bool, error) {
+	var envelope struct {
+		EventType string `json:"event_type"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Event{}, false, fmt.Errorf("unmarshal envelope: %w", err)
+	}
+
+	evt := Event{Time: ParseTimestamp(envelope.Timestamp)}
+	switch envelope.EventType {
+	case "book":
+		var book types.WSBookEvent
+		if err := json.Unmarshal(data, &book); err != nil {
+			return Event{}, false, fmt.Errorf("unmarshal book: %w", err)
+		}
+		evt.Book = &book
+	case "price_change":
+		var pc types.WSPriceChangeEvent
+		if err := json.Unmarshal(data, &pc); err != nil {
+			return Event{}, false, fmt.Errorf("unmarshal price_change: %w", err)
+		}
+		evt.PriceChange = &pc
+	case "last_trade_price":
+		var trade types.WSLastTradePriceEvent
+		if err := json.Unmarshal(data, &trade); err != nil {
+			return Event{}, false, fmt.Errorf("unmarshal last_trade_price: %w", err)
+		}
+		evt.Trade = &trade
+	default:
+		return Event{}, false, nil
+	}
+	return evt, true, nil
+}
+
+// ParseTimestamp converts a WS timestamp (unix millis as a string) to a
+// time. Returns the zero time if the value is empty or malformed.
+func ParseTimestamp(s string) time.Time {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
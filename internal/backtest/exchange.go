@@ -0,0 +1,535 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"polymarket-mm/internal/market"
+	"polymarket-mm/pkg/types"
+)
+
+// SimFill is one simulated execution of a resting order, expressed as the
+// user-channel events the live exchange would have sent.
+type SimFill struct {
+	Trade types.WSTradeEvent
+	Order types.WSOrderEvent
+}
+
+// ExecStats counts order flow through the simulated exchange.
+type ExecStats struct {
+	OrdersPlaced    int
+	OrdersRejected  int
+	OrdersCancelled int
+	QtyPlaced       float64
+	Fills           int
+	TakerFills      int // fills from orders that crossed the book on arrival
+	QtyFilled       float64
+	NotionalFilled  float64
+}
+
+// simOrder is a resting order with its estimated place in the price-level queue.
+type simOrder struct {
+	id         string
+	seq        int // arrival order, for time priority between our own orders
+	order      types.UserOrder
+	filled     float64
+	queueAhead float64 // visible size ahead of us at our price level
+}
+
+func (o *simOrder) remaining() float64 { return o.order.Size - o.filled }
+
+// levelKey identifies one price level of one side of a token's book. Side
+// is the side resting there: BUY for bids, SELL for asks.
+type levelKey struct {
+	tokenID string
+	side    types.Side
+	price   int64 // price in millionths
+}
+
+func keyFor(tokenID string, side types.Side, price float64) levelKey {
+	return levelKey{tokenID: tokenID, side: side, price: int64(math.Round(price * 1e6))}
+}
+
+// consumedLevel is liquidity our crossing orders took from a level that the
+// replayed book doesn't know about.
+type consumedLevel struct {
+	seen  float64 // the level's visible size when we took from it
+	taken float64
+}
+
+// SimExchange is an in-memory OrderGateway for one market. Orders rest at
+// their limit price behind whatever size was visible at that level when they
+// arrived. Replayed public trades first consume the queue ahead, then fill
+// our order; book updates that shrink the level move us up the queue, and
+// a book that moves through our price fills us outright.
+//
+// Liquidity our crossing orders take is remembered per level and
+// subtracted from it until the replayed book changes that level's size,
+// i.e. a newer snapshot or delta has replaced it. Without this, a resting
+// remainder or the next order in a batch would trade against the same
+// size again.
+//
+// SimExchange is not safe for concurrent use; the Runner drives it from a
+// single goroutine and the paper exchange serializes access with a mutex.
+type SimExchange struct {
+	info     types.MarketInfo
+	book     *market.Book
+	now      func() time.Time
+	idPrefix string // prepended to generated order and trade IDs
+	orders   map[string]*simOrder
+	consumed map[levelKey]consumedLevel
+
+	nextOrderID int
+	nextTradeID int
+	pending     []SimFill // fills produced outside OnTrade (crossing orders)
+	expired     []types.WSOrderEvent
+	stats       ExecStats
+}
+
+// NewSimExchange creates a simulated exchange backed by the given book.
+// now stamps generated events; pass Clock.Now for simulated time.
+func NewSimExchange(info types.MarketInfo, book *market.Book, now func() time.Time) *SimExchange {
+	return &SimExchange{
+		info:     info,
+		book:     book,
+		now:      now,
+		idPrefix: "bt-",
+		orders:   make(map[string]*simOrder),
+		consumed: make(map[levelKey]consumedLevel),
+	}
+}
+
+// SetIDPrefix changes the prefix of generated order and trade IDs, so
+// several SimExchanges can share one ID space.
+func (s *SimExchange) SetIDPrefix(prefix string) { s.idPrefix = prefix }
+
+// Stats returns cumulative execution statistics.
+func (s *SimExchange) Stats() ExecStats { return s.stats }
+
+// OpenOrders returns the number of resting orders.
+func (s *SimExchange) OpenOrders() int { return len(s.orders) }
+
+// PostOrders accepts orders at their limit price. Sizes are truncated to two
+// decimals like the live CLOB. An order that crosses the opposite side of the
+// book takes liquidity level by level; any remainder rests (GTC, GTD) or is
+// cancelled (FAK). Post-only orders that would cross, FOK orders that can't
+// fill in full and GTD orders already inside the expiry window are rejected
+// with the same typed errors as the live client.
+func (s *SimExchange) PostOrders(ctx context.Context, orders []types.UserOrder, negRisk bool) ([]types.OrderResponse, error) {
+	if len(orders) > 15 {
+		return nil, fmt.Errorf("batch limit is 15 orders, got %d", len(orders))
+	}
+
+	results := make([]types.OrderResponse, len(orders))
+	for i, order := range orders {
+		order.Size = math.Floor(order.Size*100) / 100
+		if order.Price <= 0 || order.Price >= 1 || order.Size <= 0 {
+			s.stats.OrdersRejected++
+			results[i] = types.OrderResponse{ErrorMsg: "invalid price or size"}
+			continue
+		}
+		if reason, msg := s.check(order); reason != nil {
+			s.stats.OrdersRejected++
+			results[i] = types.OrderResponse{ErrorMsg: msg, Err: &types.RejectionError{Reason: reason, Msg: msg}}
+			continue
+		}
+
+		s.nextOrderID++
+		o := &simOrder{
+			id:    fmt.Sprintf("%sorder-%d", s.idPrefix, s.nextOrderID),
+			seq:   s.nextOrderID,
+			order: order,
+		}
+		s.stats.OrdersPlaced++
+		s.stats.QtyPlaced += order.Size
+
+		s.takeLiquidity(o)
+		if o.remaining() <= 0 || !order.OrderType.Rests() {
+			results[i] = types.OrderResponse{Success: true, OrderID: o.id, Status: "matched"}
+			continue
+		}
+
+		o.queueAhead = s.levelSize(order.TokenID, order.Side, order.Price)
+		s.orders[o.id] = o
+		results[i] = types.OrderResponse{Success: true, OrderID: o.id, Status: "live"}
+	}
+	return results, nil
+}
+
+// check applies the order-type rules that reject an order outright.
+func (s *SimExchange) check(order types.UserOrder) (reason error, msg string) {
+	crossing := s.crossingSize(order)
+	switch {
+	case order.PostOnly && crossing > 0:
+		return types.ErrWouldCross, "invalid post-only order: order crosses book"
+	case order.OrderType == types.OrderTypeGTD &&
+		!time.Unix(order.Expiration, 0).Add(-types.GTDSecurityWindow).After(s.now()):
+		return types.ErrOrderExpired, "invalid expiration"
+	case order.OrderType == types.OrderTypeFOK && crossing < order.Size-sizeTolerance:
+		return types.ErrNotFilled, "order couldn't be fully filled, FOK orders are fully filled or killed"
+	case order.OrderType == types.OrderTypeFAK && crossing <= 0:
+		return types.ErrNotFilled, "no orders found to match with FAK order"
+	}
+	return nil, ""
+}
+
+// crossingSize is the available size an order would take on arrival.
+func (s *SimExchange) crossingSize(order types.UserOrder) float64 {
+	var total float64
+	for _, lvl := range s.crossingLevels(order) {
+		total += lvl.size
+	}
+	return total
+}
+
+// availableLevel is a price level of the opposite side of the book with
+// the size still available to us.
+type availableLevel struct {
+	price float64
+	size  float64
+}
+
+// crossingLevels returns the opposite-side levels an order's limit price
+// reaches, best first, with the size still available at each.
+func (s *SimExchange) crossingLevels(order types.UserOrder) []availableLevel {
+	bids, asks := s.book.Ladder(order.TokenID)
+	levels, side := asks, types.SELL
+	crosses := func(p float64) bool { return p <= order.Price+priceTolerance }
+	if order.Side == types.SELL {
+		levels, side = bids, types.BUY
+		crosses = func(p float64) bool { return p >= order.Price-priceTolerance }
+	}
+
+	var out []availableLevel
+	for _, lvl := range levels {
+		p, _ := strconv.ParseFloat(lvl.Price, 64)
+		if !crosses(p) {
+			break
+		}
+		size, _ := strconv.ParseFloat(lvl.Size, 64)
+		if avail := s.available(order.TokenID, side, p, size); avail > sizeTolerance {
+			out = append(out, availableLevel{price: p, size: avail})
+		}
+	}
+	return out
+}
+
+// available is a level's visible size less what our orders took from it
+// since the book last changed it. A level whose visible size has changed
+// was replaced by newer book data, which already reflects its trades.
+func (s *SimExchange) available(tokenID string, side types.Side, price, visible float64) float64 {
+	k := keyFor(tokenID, side, price)
+	c, ok := s.consumed[k]
+	if !ok {
+		return visible
+	}
+	if math.Abs(c.seen-visible) > sizeTolerance {
+		delete(s.consumed, k)
+		return visible
+	}
+	return math.Max(visible-c.taken, 0)
+}
+
+// consume records qty taken from a level showing visible size.
+func (s *SimExchange) consume(tokenID string, side types.Side, price, visible, qty float64) {
+	k := keyFor(tokenID, side, price)
+	c, ok := s.consumed[k]
+	if !ok || math.Abs(c.seen-visible) > sizeTolerance {
+		c = consumedLevel{seen: visible}
+	}
+	c.taken += qty
+	s.consumed[k] = c
+}
+
+// pruneConsumed forgets consumption on levels the book has since replaced.
+func (s *SimExchange) pruneConsumed() {
+	for k, c := range s.consumed {
+		price := float64(k.price) / 1e6
+		if math.Abs(c.seen-s.visibleSize(k.tokenID, k.side, price)) > sizeTolerance {
+			delete(s.consumed, k)
+		}
+	}
+}
+
+// expire drops GTD orders whose expiration window has been reached.
+func (s *SimExchange) expire() {
+	for id, o := range s.orders {
+		if o.order.OrderType != types.OrderTypeGTD {
+			continue
+		}
+		if !time.Unix(o.order.Expiration, 0).Add(-types.GTDSecurityWindow).After(s.now()) {
+			delete(s.orders, id)
+			s.expired = append(s.expired, s.orderEvent(o, "CANCELLATION"))
+		}
+	}
+}
+
+// CancelOrders removes the given resting orders.
+func (s *SimExchange) CancelOrders(ctx context.Context, orderIDs []string) (*types.CancelResponse, error) {
+	resp := &types.CancelResponse{}
+	for _, id := range orderIDs {
+		if _, ok := s.orders[id]; ok {
+			delete(s.orders, id)
+			s.stats.OrdersCancelled++
+			resp.Canceled = append(resp.Canceled, id)
+		}
+	}
+	return resp, nil
+}
+
+// CancelMarketOrders removes every resting order.
+func (s *SimExchange) CancelMarketOrders(ctx context.Context, conditionID string) (*types.CancelResponse, error) {
+	resp := &types.CancelResponse{}
+	for id := range s.orders {
+		resp.Canceled = append(resp.Canceled, id)
+	}
+	sort.Strings(resp.Canceled)
+	s.stats.OrdersCancelled += len(resp.Canceled)
+	s.orders = make(map[string]*simOrder)
+	return resp, nil
+}
+
+// DrainFills returns and clears fills generated while posting orders.
+func (s *SimExchange) DrainFills() []SimFill {
+	fills := s.pending
+	s.pending = nil
+	return fills
+}
+
+// DrainExpired returns and clears CANCELLATION events for GTD orders that
+// expired since the last call, in the shape the user channel sends them.
+func (s *SimExchange) DrainExpired() []types.WSOrderEvent {
+	expired := s.expired
+	s.expired = nil
+	return expired
+}
+
+// OnBookUpdate re-evaluates resting orders after a book change.
+//
+// If the opposite side has moved through an order's price (the best ask is
+// at or below our bid, or the best bid at or above our ask), the market
+// traded through us and the order fills in full at its limit price.
+// Otherwise the queue estimate is tightened: if the visible size at our
+// level dropped below what we thought was ahead of us, the orders ahead
+// must have cancelled or traded.
+func (s *SimExchange) OnBookUpdate() []SimFill {
+	s.expire()
+	s.pruneConsumed()
+	var fills []SimFill
+	for _, o := range s.sortedOrders() {
+		if s.crossedBy(o) {
+			s.consumeCrossing(o.order, o.remaining())
+			fills = append(fills, s.fill(o, o.order.Price, o.remaining(), false))
+			delete(s.orders, o.id)
+			continue
+		}
+		visible := s.levelSize(o.order.TokenID, o.order.Side, o.order.Price)
+		if visible < o.queueAhead {
+			o.queueAhead = visible
+		}
+	}
+	return fills
+}
+
+// crossedBy reports whether the opposite side of the book has moved to or
+// through a resting order's price, with liquidity we haven't already taken.
+func (s *SimExchange) crossedBy(o *simOrder) bool {
+	return len(s.crossingLevels(o.order)) > 0
+}
+
+// consumeCrossing records qty taken from the levels an order crosses, best
+// first, so later orders can't fill against the same liquidity.
+func (s *SimExchange) consumeCrossing(order types.UserOrder, qty float64) {
+	side := types.SELL
+	if order.Side == types.SELL {
+		side = types.BUY
+	}
+	for _, lvl := range s.crossingLevels(order) {
+		if qty <= sizeTolerance {
+			return
+		}
+		take := math.Min(qty, lvl.size)
+		s.consume(order.TokenID, side, lvl.price, s.visibleSize(order.TokenID, side, lvl.price), take)
+		qty -= take
+	}
+}
+
+// sortedOrders returns resting orders in arrival order, for deterministic fills.
+func (s *SimExchange) sortedOrders() []*simOrder {
+	orders := make([]*simOrder, 0, len(s.orders))
+	for _, o := range s.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].seq < orders[j].seq })
+	return orders
+}
+
+// OnTrade matches a public trade print against resting orders. A taker SELL
+// hits bids priced at or above the print; a taker BUY lifts asks priced at
+// or below it. Orders priced better than the print fill first; orders at the
+// print price only fill once the queue ahead of them is exhausted.
+func (s *SimExchange) OnTrade(trade types.WSLastTradePriceEvent) []SimFill {
+	s.expire()
+	price, _ := strconv.ParseFloat(trade.Price, 64)
+	avail, _ := strconv.ParseFloat(trade.Size, 64)
+	if price <= 0 || avail <= 0 {
+		return nil
+	}
+
+	var ourSide types.Side
+	switch types.Side(trade.Side) {
+	case types.SELL:
+		ourSide = types.BUY
+	case types.BUY:
+		ourSide = types.SELL
+	default:
+		return nil
+	}
+
+	var eligible []*simOrder
+	for _, o := range s.orders {
+		if o.order.TokenID != trade.AssetID || o.order.Side != ourSide {
+			continue
+		}
+		if ourSide == types.BUY && o.order.Price >= price-priceTolerance ||
+			ourSide == types.SELL && o.order.Price <= price+priceTolerance {
+			eligible = append(eligible, o)
+		}
+	}
+
+	// Price priority, then time priority
+	sort.Slice(eligible, func(i, j int) bool {
+		a, b := eligible[i], eligible[j]
+		if math.Abs(a.order.Price-b.order.Price) > priceTolerance {
+			if ourSide == types.BUY {
+				return a.order.Price > b.order.Price
+			}
+			return a.order.Price < b.order.Price
+		}
+		return a.seq < b.seq
+	})
+
+	var fills []SimFill
+	for _, o := range eligible {
+		if avail <= 0 {
+			break
+		}
+		if math.Abs(o.order.Price-price) <= priceTolerance {
+			consumed := math.Min(o.queueAhead, avail)
+			o.queueAhead -= consumed
+			avail -= consumed
+		}
+		qty := math.Min(o.remaining(), avail)
+		if qty <= 0 {
+			continue
+		}
+		avail -= qty
+		fills = append(fills, s.fill(o, o.order.Price, qty, false))
+		if o.remaining() <= sizeTolerance {
+			delete(s.orders, o.id)
+		}
+	}
+	return fills
+}
+
+// takeLiquidity fills the crossing portion of a newly posted order against
+// the opposite side of the book, recording what it took. Fills are queued
+// for DrainFills.
+func (s *SimExchange) takeLiquidity(o *simOrder) {
+	for _, lvl := range s.crossingLevels(o.order) {
+		if o.remaining() <= sizeTolerance {
+			return
+		}
+		qty := math.Min(o.remaining(), lvl.size)
+		s.consumeCrossing(o.order, qty)
+		s.pending = append(s.pending, s.fill(o, lvl.price, qty, true))
+	}
+}
+
+// fill books an execution against an order and builds the user-channel events.
+func (s *SimExchange) fill(o *simOrder, price, qty float64, taker bool) SimFill {
+	o.filled += qty
+	s.nextTradeID++
+
+	s.stats.Fills++
+	s.stats.QtyFilled += qty
+	s.stats.NotionalFilled += qty * price
+	if taker {
+		s.stats.TakerFills++
+	}
+
+	order := s.orderEvent(o, "UPDATE")
+	return SimFill{
+		Trade: types.WSTradeEvent{
+			EventType:  "trade",
+			ID:         fmt.Sprintf("%strade-%d", s.idPrefix, s.nextTradeID),
+			Market:     s.info.ConditionID,
+			AssetID:    o.order.TokenID,
+			Side:       string(o.order.Side),
+			Size:       formatFloat(qty),
+			Price:      formatFloat(price),
+			FeeRateBps: strconv.Itoa(o.order.FeeRateBps),
+			Outcome:    order.Outcome,
+			Timestamp:  order.Timestamp,
+		},
+		Order: order,
+	}
+}
+
+// orderEvent builds a user-channel order event for a simulated order.
+func (s *SimExchange) orderEvent(o *simOrder, eventType string) types.WSOrderEvent {
+	outcome := "Yes"
+	if o.order.TokenID == s.info.NoTokenID {
+		outcome = "No"
+	}
+	return types.WSOrderEvent{
+		EventType:    "order",
+		ID:           o.id,
+		Market:       s.info.ConditionID,
+		AssetID:      o.order.TokenID,
+		Side:         string(o.order.Side),
+		Price:        formatFloat(o.order.Price),
+		OriginalSize: formatFloat(o.order.Size),
+		SizeMatched:  formatFloat(o.filled),
+		Outcome:      outcome,
+		Timestamp:    strconv.FormatInt(s.now().UnixMilli(), 10),
+		Type:         eventType,
+	}
+}
+
+// levelSize returns the size still available at a price on one side of a
+// token's book: its visible size less what our crossing orders took.
+func (s *SimExchange) levelSize(tokenID string, side types.Side, price float64) float64 {
+	return s.available(tokenID, side, price, s.visibleSize(tokenID, side, price))
+}
+
+// visibleSize returns the replayed book's size at a price on one side of a
+// token's book. Side is the side resting there: BUY for bids, SELL for asks.
+func (s *SimExchange) visibleSize(tokenID string, side types.Side, price float64) float64 {
+	bids, asks := s.book.Ladder(tokenID)
+	levels := bids
+	if side == types.SELL {
+		levels = asks
+	}
+	for _, lvl := range levels {
+		p, _ := strconv.ParseFloat(lvl.Price, 64)
+		if math.Abs(p-price) <= priceTolerance {
+			size, _ := strconv.ParseFloat(lvl.Size, 64)
+			return size
+		}
+	}
+	return 0
+}
+
+const (
+	priceTolerance = 1e-9
+	sizeTolerance  = 1e-9
+)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,286 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/market"
+	"polymarket-mm/pkg/types"
+)
+
+func testInfo() types.MarketInfo {
+	return types.MarketInfo{
+		ConditionID:  "cond-bt",
+		Slug:         "bt-market",
+		YesTokenID:   "yes",
+		NoTokenID:    "no",
+		TickSize:     types.Tick001,
+		MinOrderSize: 1,
+	}
+}
+
+func newTestSim(t *testing.T) (*SimExchange, *market.Book) {
+	t.Helper()
+	info := testInfo()
+	clock := &Clock{t: time.UnixMilli(1_700_000_000_000)}
+	book := market.NewBook(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	book.SetClock(clock.Now)
+	book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: info.YesTokenID,
+		Buys:    []types.PriceLevel{{Price: "0.48", Size: "100"}, {Price: "0.47", Size: "50"}},
+		Sells:   []types.PriceLevel{{Price: "0.52", Size: "100"}},
+	})
+	return NewSimExchange(info, book, clock.Now), book
+}
+
+func postOne(t *testing.T, sim *SimExchange, order types.UserOrder) string {
+	t.Helper()
+	res, err := sim.PostOrders(context.Background(), []types.UserOrder{order}, false)
+	if err != nil {
+		t.Fatalf("PostOrders: %v", err)
+	}
+	if !res[0].Success {
+		t.Fatalf("order rejected: %s", res[0].ErrorMsg)
+	}
+	return res[0].OrderID
+}
+
+func TestSimQueueAheadConsumedBeforeFill(t *testing.T) {
+	t.Parallel()
+	sim, _ := newTestSim(t)
+
+	postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.48, Size: 20, Side: types.BUY})
+
+	// 80 traded at our level: all of it goes to the 100 resting ahead of us
+	fills := sim.OnTrade(types.WSLastTradePriceEvent{AssetID: "yes", Price: "0.48", Size: "80", Side: "SELL"})
+	if len(fills) != 0 {
+		t.Fatalf("expected no fills while queue ahead remains, got %d", len(fills))
+	}
+
+	// Next 30: 20 clears the queue, 10 fills us
+	fills = sim.OnTrade(types.WSLastTradePriceEvent{AssetID: "yes", Price: "0.48", Size: "30", Side: "SELL"})
+	if len(fills) != 1 {
+		t.Fatalf("expected 1 fill, got %d", len(fills))
+	}
+	if fills[0].Trade.Size != "10" || fills[0].Trade.Side != "BUY" || fills[0].Trade.Price != "0.48" {
+		t.Errorf("unexpected fill: %+v", fills[0].Trade)
+	}
+	if fills[0].Order.SizeMatched != "10" || fills[0].Order.Type != "UPDATE" {
+		t.Errorf("unexpected order event: %+v", fills[0].Order)
+	}
+	if sim.OpenOrders() != 1 {
+		t.Errorf("partially filled order should still rest, open=%d", sim.OpenOrders())
+	}
+}
+
+func TestSimBetterPriceFillsFirst(t *testing.T) {
+	t.Parallel()
+	sim, _ := newTestSim(t)
+
+	// Bid inside the spread has no queue ahead; a print at 0.48 trades through it
+	postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.50, Size: 5, Side: types.BUY})
+
+	fills := sim.OnTrade(types.WSLastTradePriceEvent{AssetID: "yes", Price: "0.48", Size: "8", Side: "SELL"})
+	if len(fills) != 1 || fills[0].Trade.Size != "5" {
+		t.Fatalf("expected full fill of 5, got %+v", fills)
+	}
+	if sim.OpenOrders() != 0 {
+		t.Errorf("fully filled order should be removed, open=%d", sim.OpenOrders())
+	}
+}
+
+func TestSimBookUpdateAdvancesQueue(t *testing.T) {
+	t.Parallel()
+	sim, book := newTestSim(t)
+
+	postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.48, Size: 10, Side: types.BUY})
+
+	// Orders ahead cancel: level shrinks from 100 to 4
+	book.ApplyPriceChange(types.WSPriceChangeEvent{PriceChanges: []types.WSPriceChange{
+		{AssetID: "yes", Price: "0.48", Size: "4", Side: "BUY", BestBid: "0.48", BestAsk: "0.52"},
+	}})
+	sim.OnBookUpdate()
+
+	fills := sim.OnTrade(types.WSLastTradePriceEvent{AssetID: "yes", Price: "0.48", Size: "10", Side: "SELL"})
+	if len(fills) != 1 || fills[0].Trade.Size != "6" {
+		t.Fatalf("expected fill of 6 after queue of 4, got %+v", fills)
+	}
+}
+
+func TestSimIgnoresWrongSideAndToken(t *testing.T) {
+	t.Parallel()
+	sim, _ := newTestSim(t)
+
+	postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.50, Size: 5, Side: types.BUY})
+
+	if fills := sim.OnTrade(types.WSLastTradePriceEvent{AssetID: "yes", Price: "0.52", Size: "50", Side: "BUY"}); len(fills) != 0 {
+		t.Errorf("taker buy should not fill our bid: %+v", fills)
+	}
+	if fills := sim.OnTrade(types.WSLastTradePriceEvent{AssetID: "no", Price: "0.40", Size: "50", Side: "SELL"}); len(fills) != 0 {
+		t.Errorf("NO trade should not fill YES bid: %+v", fills)
+	}
+}
+
+func TestSimCrossingOrderTakesLiquidity(t *testing.T) {
+	t.Parallel()
+	sim, _ := newTestSim(t)
+
+	id := postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.52, Size: 150, Side: types.BUY})
+
+	fills := sim.DrainFills()
+	if len(fills) != 1 || fills[0].Trade.Size != "100" || fills[0].Trade.Price != "0.52" {
+		t.Fatalf("expected taker fill of 100 @ 0.52, got %+v", fills)
+	}
+	if sim.Stats().TakerFills != 1 {
+		t.Errorf("TakerFills = %d, want 1", sim.Stats().TakerFills)
+	}
+	if sim.OpenOrders() != 1 {
+		t.Errorf("remainder should rest, open=%d", sim.OpenOrders())
+	}
+
+	resp, _ := sim.CancelOrders(context.Background(), []string{id})
+	if len(resp.Canceled) != 1 {
+		t.Errorf("cancel remainder: got %v", resp.Canceled)
+	}
+}
+
+func TestSimTakenLiquidityIsNotReused(t *testing.T) {
+	t.Parallel()
+	sim, book := newTestSim(t)
+
+	// Take all 100 at 0.52 and rest 50 there
+	postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.52, Size: 150, Side: types.BUY})
+	sim.DrainFills()
+
+	// The replayed book hasn't caught up: the ask we took still shows
+	if fills := sim.OnBookUpdate(); len(fills) != 0 {
+		t.Fatalf("unchanged book filled the remainder again: %+v", fills)
+	}
+	if sim.OpenOrders() != 1 {
+		t.Fatalf("remainder should still rest, open=%d", sim.OpenOrders())
+	}
+
+	// Nor can another order in the same batch take it
+	res, err := sim.PostOrders(context.Background(), []types.UserOrder{
+		{TokenID: "yes", Price: 0.52, Size: 10, Side: types.BUY, OrderType: types.OrderTypeFAK},
+		{TokenID: "yes", Price: 0.52, Size: 10, Side: types.BUY, OrderType: types.OrderTypeFAK},
+	}, false)
+	if err != nil {
+		t.Fatalf("PostOrders: %v", err)
+	}
+	for i, r := range res {
+		if r.Success {
+			t.Errorf("order %d filled against liquidity already taken", i)
+		}
+	}
+	if fills := sim.DrainFills(); len(fills) != 0 {
+		t.Errorf("fills = %+v, want none", fills)
+	}
+
+	// A delta replacing the level brings its liquidity back
+	book.ApplyPriceChange(types.WSPriceChangeEvent{PriceChanges: []types.WSPriceChange{
+		{AssetID: "yes", Price: "0.52", Size: "30", Side: "SELL", BestBid: "0.48", BestAsk: "0.52"},
+	}})
+	fills := sim.OnBookUpdate()
+	if len(fills) != 1 || fills[0].Trade.Size != "50" {
+		t.Fatalf("expected the remainder to fill once the level refreshed, got %+v", fills)
+	}
+}
+
+func TestSimBookCrossingFillsRestingOrder(t *testing.T) {
+	t.Parallel()
+	sim, book := newTestSim(t)
+
+	postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.50, Size: 7, Side: types.BUY})
+
+	// Asks drop through our bid: the market traded through us
+	book.ApplyPriceChange(types.WSPriceChangeEvent{PriceChanges: []types.WSPriceChange{
+		{AssetID: "yes", Price: "0.52", Size: "0", Side: "SELL", BestBid: "0.48", BestAsk: "0.49"},
+		{AssetID: "yes", Price: "0.49", Size: "30", Side: "SELL", BestBid: "0.48", BestAsk: "0.49"},
+	}})
+	fills := sim.OnBookUpdate()
+	if len(fills) != 1 || fills[0].Trade.Size != "7" || fills[0].Trade.Price != "0.5" {
+		t.Fatalf("expected full fill of 7 @ 0.5 at our limit, got %+v", fills)
+	}
+	if sim.OpenOrders() != 0 {
+		t.Errorf("crossed order should be removed, open=%d", sim.OpenOrders())
+	}
+}
+
+func TestSimOrderTypes(t *testing.T) {
+	t.Parallel()
+	sim, _ := newTestSim(t)
+	post := func(order types.UserOrder) types.OrderResponse {
+		t.Helper()
+		res, err := sim.PostOrders(context.Background(), []types.UserOrder{order}, false)
+		if err != nil {
+			t.Fatalf("PostOrders: %v", err)
+		}
+		return res[0]
+	}
+
+	// Post-only that would cross is rejected without trading
+	res := post(types.UserOrder{TokenID: "yes", Price: 0.52, Size: 10, Side: types.BUY, PostOnly: true})
+	if res.Success || !errors.Is(res.Err, types.ErrWouldCross) {
+		t.Errorf("post-only cross = %+v, want ErrWouldCross", res)
+	}
+
+	// FOK larger than the visible ask is killed
+	res = post(types.UserOrder{TokenID: "yes", Price: 0.52, Size: 150, Side: types.BUY, OrderType: types.OrderTypeFOK})
+	if res.Success || !errors.Is(res.Err, types.ErrNotFilled) {
+		t.Errorf("FOK = %+v, want ErrNotFilled", res)
+	}
+	if len(sim.DrainFills()) != 0 {
+		t.Error("rejected orders must not trade")
+	}
+
+	// FAK takes what it can and never rests
+	res = post(types.UserOrder{TokenID: "yes", Price: 0.52, Size: 150, Side: types.BUY, OrderType: types.OrderTypeFAK})
+	if !res.Success || res.Status != "matched" {
+		t.Errorf("FAK = %+v, want matched", res)
+	}
+	if fills := sim.DrainFills(); len(fills) != 1 || fills[0].Trade.Size != "100" {
+		t.Errorf("FAK fills = %+v, want 100", fills)
+	}
+	if sim.OpenOrders() != 0 {
+		t.Errorf("FAK remainder rested, open=%d", sim.OpenOrders())
+	}
+	if sim.Stats().OrdersRejected != 2 {
+		t.Errorf("OrdersRejected = %d, want 2", sim.Stats().OrdersRejected)
+	}
+}
+
+func TestSimExpiresGTDOrders(t *testing.T) {
+	t.Parallel()
+	info := testInfo()
+	clock := &Clock{t: time.UnixMilli(1_700_000_000_000)}
+	book := market.NewBook(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	book.SetClock(clock.Now)
+	sim := NewSimExchange(info, book, clock.Now)
+
+	exp := clock.t.Add(types.GTDSecurityWindow + 10*time.Second).Unix()
+	id := postOne(t, sim, types.UserOrder{TokenID: "yes", Price: 0.45, Size: 10, Side: types.BUY, OrderType: types.OrderTypeGTD, Expiration: exp})
+
+	sim.OnBookUpdate()
+	if sim.OpenOrders() != 1 || len(sim.DrainExpired()) != 0 {
+		t.Fatalf("GTD order expired early, open=%d", sim.OpenOrders())
+	}
+	clock.t = clock.t.Add(10 * time.Second)
+	sim.OnBookUpdate()
+	if sim.OpenOrders() != 0 {
+		t.Errorf("GTD order outlived its expiration, open=%d", sim.OpenOrders())
+	}
+	if expired := sim.DrainExpired(); len(expired) != 1 || expired[0].ID != id || expired[0].Type != "CANCELLATION" {
+		t.Errorf("expired = %+v, want a CANCELLATION for %s", expired, id)
+	}
+
+	// Already inside the security window on arrival
+	res, _ := sim.PostOrders(context.Background(), []types.UserOrder{
+		{TokenID: "yes", Price: 0.45, Size: 10, Side: types.BUY, OrderType: types.OrderTypeGTD, Expiration: exp},
+	}, false)
+	if res[0].Success || !errors.Is(res[0].Err, types.ErrOrderExpired) {
+		t.Errorf("stale GTD = %+v, want ErrOrderExpired", res[0])
+	}
+}
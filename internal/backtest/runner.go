@@ -0,0 +1,303 @@
+package backtest
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/internal/market"
+	"polymarket-mm/internal/risk"
+	"polymarket-mm/internal/strategy"
+	"polymarket-mm/pkg/types"
+)
+
+// Clock is the simulated time source shared by every component in a run.
+type Clock struct {
+	t time.Time
+}
+
+// Now returns the current simulated time.
+func (c *Clock) Now() time.Time { return c.t }
+
+// Set moves the simulated time. Callers only ever move it forward.
+func (c *Clock) Set(t time.Time) { c.t = t }
+
+// InventoryPoint is one sample of the position taken after each quote cycle.
+type InventoryPoint struct {
+	Time     time.Time
+	Mid      float64
+	YesQty   float64
+	NoQty    float64
+	NetDelta float64
+	PnL      float64 // realized + unrealized
+	Toxicity float64 // flow toxicity score at this cycle
+}
+
+// Report summarises a backtest run.
+type Report struct {
+	Start       time.Time
+	End         time.Time
+	Events      int
+	QuoteCycles int
+
+	Exec     ExecStats
+	FillRate float64 // filled quantity / placed quantity
+
+	RealizedPnL   float64
+	UnrealizedPnL float64
+	TotalPnL      float64
+	MaxDrawdown   float64 // largest peak-to-trough drop in total PnL
+	FinalMid      float64
+	FinalPosition strategy.Position
+	KillSignals   int
+
+	AvgToxicity    float64 // mean toxicity score across quote cycles
+	MaxToxicity    float64
+	AverseFraction float64 // share of quote cycles flagged as toxic
+
+	InventoryPath []InventoryPoint
+}
+
+// Runner replays events through a Maker for one market on a simulated clock.
+type Runner struct {
+	cfg     config.Config
+	info    types.MarketInfo
+	clock   *Clock
+	book    *market.Book
+	inv     *strategy.Inventory
+	maker   *strategy.Maker
+	sim     *SimExchange
+	riskMgr *risk.Manager
+	logger  *slog.Logger
+
+	report       Report
+	toxicitySum  float64
+	averseCycles int
+	peakPnL      float64
+}
+
+// NewRunner wires a Book, Inventory, risk Manager and Maker around a
+// SimExchange, all sharing one simulated Clock.
+func NewRunner(cfg config.Config, info types.MarketInfo, logger *slog.Logger) (*Runner, error) {
+	if cfg.Strategy.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("strategy.refresh_interval must be > 0")
+	}
+	if info.YesTokenID == "" || info.NoTokenID == "" {
+		return nil, fmt.Errorf("market YES and NO token IDs are required")
+	}
+
+	clock := &Clock{}
+	book := market.NewBook(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	book.SetClock(clock.Now)
+	inv := strategy.NewInventory(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	riskMgr := risk.NewManager(cfg.Risk, logger)
+	riskMgr.SetClock(clock.Now)
+	sim := NewSimExchange(info, book, clock.Now)
+
+	maker := strategy.NewMaker(cfg.Strategy, info, book, inv, sim, riskMgr, logger, nil)
+	maker.SetClock(clock.Now)
+
+	return &Runner{
+		cfg:     cfg,
+		info:    info,
+		clock:   clock,
+		book:    book,
+		inv:     inv,
+		maker:   maker,
+		sim:     sim,
+		riskMgr: riskMgr,
+		logger:  logger.With("component", "backtest"),
+	}, nil
+}
+
+// Run replays events in order. Before each event, the Maker is stepped on
+// every RefreshInterval boundary that has elapsed. Returns the final report.
+func (r *Runner) Run(ctx context.Context, events []Event) Report {
+	if len(events) == 0 {
+		return r.report
+	}
+
+	refresh := r.cfg.Strategy.RefreshInterval
+	r.report.Start = events[0].Time
+	r.clock.Set(events[0].Time)
+	next := events[0].Time.Add(refresh)
+
+	for _, evt := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		for !next.After(evt.Time) {
+			r.clock.Set(next)
+			r.step(ctx)
+			next = next.Add(refresh)
+		}
+
+		r.clock.Set(evt.Time)
+		r.apply(ctx, evt)
+		r.report.Events++
+	}
+
+	r.step(ctx)
+	r.finish()
+	return r.report
+}
+
+// apply feeds one event into the book and the simulated exchange. A trade
+// print that raises a tape alert steps the Maker at once, as Run would.
+func (r *Runner) apply(ctx context.Context, evt Event) {
+	switch {
+	case evt.Book != nil:
+		r.book.ApplyBookEvent(*evt.Book)
+		r.maker.ObserveBook()
+		r.deliver(r.sim.OnBookUpdate())
+	case evt.PriceChange != nil:
+		// Recorded deltas can't be resynced from REST; the next snapshot heals the book.
+		r.book.ApplyPriceChange(*evt.PriceChange)
+		r.maker.ObserveBook()
+		r.deliver(r.sim.OnBookUpdate())
+	case evt.Trade != nil:
+		alerted := r.maker.ObserveTrade(*evt.Trade)
+		r.deliver(r.sim.OnTrade(*evt.Trade))
+		if alerted {
+			r.step(ctx)
+		}
+	}
+}
+
+// step runs one quote cycle and samples the resulting state.
+func (r *Runner) step(ctx context.Context) {
+	r.maker.Step(ctx)
+	r.deliver(r.sim.DrainFills())
+	r.riskMgr.Flush()
+
+	for drained := false; !drained; {
+		select {
+		case kill := <-r.riskMgr.KillCh():
+			r.report.KillSignals++
+			r.logger.Warn("kill signal", "reason", kill.Reason, "time", r.clock.Now())
+		default:
+			drained = true
+		}
+	}
+
+	r.report.QuoteCycles++
+	r.sample()
+}
+
+// deliver hands simulated fills, and any GTD expiries, to the Maker as
+// user-channel events.
+func (r *Runner) deliver(fills []SimFill) {
+	for _, evt := range r.sim.DrainExpired() {
+		r.maker.HandleOrderEvent(evt)
+	}
+	for _, f := range fills {
+		r.maker.HandleTrade(f.Trade)
+		r.maker.HandleOrderEvent(f.Order)
+	}
+}
+
+func (r *Runner) sample() {
+	mid, ok := r.book.MidPrice()
+	if !ok {
+		return
+	}
+	r.inv.UpdateMarkToMarket(mid)
+	pos := r.inv.Snapshot()
+	pnl := pos.RealizedPnL + pos.UnrealizedPnL
+	tox := r.maker.Toxicity()
+
+	r.toxicitySum += tox.ToxicityScore
+	r.report.MaxToxicity = math.Max(r.report.MaxToxicity, tox.ToxicityScore)
+	if tox.IsAverse {
+		r.averseCycles++
+	}
+
+	if pnl > r.peakPnL {
+		r.peakPnL = pnl
+	}
+	r.report.MaxDrawdown = math.Max(r.report.MaxDrawdown, r.peakPnL-pnl)
+
+	r.report.InventoryPath = append(r.report.InventoryPath, InventoryPoint{
+		Time:     r.clock.Now(),
+		Mid:      mid,
+		YesQty:   pos.YesQty,
+		NoQty:    pos.NoQty,
+		NetDelta: r.inv.NetDelta(),
+		PnL:      pnl,
+		Toxicity: tox.ToxicityScore,
+	})
+}
+
+func (r *Runner) finish() {
+	r.report.End = r.clock.Now()
+	r.report.Exec = r.sim.Stats()
+	if r.report.Exec.QtyPlaced > 0 {
+		r.report.FillRate = r.report.Exec.QtyFilled / r.report.Exec.QtyPlaced
+	}
+
+	if mid, ok := r.book.MidPrice(); ok {
+		r.report.FinalMid = mid
+		r.inv.UpdateMarkToMarket(mid)
+	}
+	pos := r.inv.Snapshot()
+	r.report.FinalPosition = pos
+	r.report.RealizedPnL = pos.RealizedPnL
+	r.report.UnrealizedPnL = pos.UnrealizedPnL
+	r.report.TotalPnL = pos.RealizedPnL + pos.UnrealizedPnL
+
+	if n := len(r.report.InventoryPath); n > 0 {
+		r.report.AvgToxicity = r.toxicitySum / float64(n)
+		r.report.AverseFraction = float64(r.averseCycles) / float64(n)
+	}
+}
+
+// WriteSummary prints a human-readable summary of the report.
+func (rep Report) WriteSummary(w io.Writer) {
+	fmt.Fprintf(w, "period:          %s → %s (%s)\n",
+		rep.Start.UTC().Format(time.RFC3339), rep.End.UTC().Format(time.RFC3339), rep.End.Sub(rep.Start).Round(time.Second))
+	fmt.Fprintf(w, "events:          %d\n", rep.Events)
+	fmt.Fprintf(w, "quote cycles:    %d\n", rep.QuoteCycles)
+	fmt.Fprintf(w, "orders:          placed=%d cancelled=%d rejected=%d\n",
+		rep.Exec.OrdersPlaced, rep.Exec.OrdersCancelled, rep.Exec.OrdersRejected)
+	fmt.Fprintf(w, "fills:           %d (taker %d), qty %.2f, notional $%.2f\n",
+		rep.Exec.Fills, rep.Exec.TakerFills, rep.Exec.QtyFilled, rep.Exec.NotionalFilled)
+	fmt.Fprintf(w, "fill rate:       %.2f%%\n", rep.FillRate*100)
+	fmt.Fprintf(w, "final position:  yes=%.2f no=%.2f mid=%.4f\n",
+		rep.FinalPosition.YesQty, rep.FinalPosition.NoQty, rep.FinalMid)
+	fmt.Fprintf(w, "pnl:             realized=%.4f unrealized=%.4f total=%.4f\n",
+		rep.RealizedPnL, rep.UnrealizedPnL, rep.TotalPnL)
+	fmt.Fprintf(w, "max drawdown:    %.4f\n", rep.MaxDrawdown)
+	fmt.Fprintf(w, "toxicity:        avg=%.3f max=%.3f averse=%.1f%% of cycles\n",
+		rep.AvgToxicity, rep.MaxToxicity, rep.AverseFraction*100)
+	fmt.Fprintf(w, "kill signals:    %d\n", rep.KillSignals)
+}
+
+// WriteInventoryCSV writes the inventory path as CSV with a header row.
+func (rep Report) WriteInventoryCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "mid", "yes_qty", "no_qty", "net_delta", "pnl", "toxicity"}); err != nil {
+		return err
+	}
+	for _, p := range rep.InventoryPath {
+		row := []string{
+			p.Time.UTC().Format(time.RFC3339Nano),
+			strconv.FormatFloat(p.Mid, 'f', 6, 64),
+			strconv.FormatFloat(p.YesQty, 'f', 4, 64),
+			strconv.FormatFloat(p.NoQty, 'f', 4, 64),
+			strconv.FormatFloat(p.NetDelta, 'f', 4, 64),
+			strconv.FormatFloat(p.PnL, 'f', 6, 64),
+			strconv.FormatFloat(p.Toxicity, 'f', 4, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,211 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/internal/store"
+)
+
+func testConfig() config.Config {
+	return config.Config{
+		Strategy: config.StrategyConfig{
+			Gamma:                   0.5,
+			Sigma:                   0.2,
+			K:                       10.0,
+			T:                       0.5,
+			DefaultSpreadBps:        100,
+			OrderSizeUSD:            5,
+			RefreshInterval:         5 * time.Second,
+			StaleBookTimeout:        time.Minute,
+			FlowWindow:              60 * time.Second,
+			FlowToxicityThreshold:   0.6,
+			FlowCooldownPeriod:      120 * time.Second,
+			FlowMaxSpreadMultiplier: 3.0,
+		},
+		Risk: config.RiskConfig{
+			MaxPositionPerMarket: 1000,
+			MaxGlobalExposure:    1000,
+			MaxMarketsActive:     1,
+			KillSwitchDropPct:    0.5,
+			KillSwitchWindowSec:  60,
+			MaxDailyLoss:         1000,
+			CooldownAfterKill:    time.Minute,
+		},
+	}
+}
+
+func TestReadEventsParsesAndOrders(t *testing.T) {
+	t.Parallel()
+	data := strings.Join([]string{
+		`{"event_type":"last_trade_price","asset_id":"yes","price":"0.5","size":"3","side":"SELL","timestamp":"2000"}`,
+		`[{"event_type":"book","asset_id":"yes","timestamp":"1000","buys":[{"price":"0.49","size":"10"}],"sells":[{"price":"0.51","size":"10"}]}]`,
+		`{"event_type":"tick_size_change","timestamp":"1500"}`,
+		``,
+		`{"event_type":"price_change","market":"m","timestamp":"3000","price_changes":[{"asset_id":"yes","price":"0.50","size":"5","side":"BUY"}]}`,
+	}, "\n")
+
+	events, err := ReadEvents(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if events[0].Book == nil || events[1].Trade == nil || events[2].PriceChange == nil {
+		t.Errorf("events not ordered by timestamp: %+v", events)
+	}
+	if !events[0].Time.Equal(time.UnixMilli(1000)) {
+		t.Errorf("first event time = %v", events[0].Time)
+	}
+}
+
+func TestRunnerFillsAndReports(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	runner, err := NewRunner(testConfig(), testInfo(), logger)
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	// A one-minute session: a book, then alternating taker sells and buys
+	// that trade through the inside so the Maker's quotes get hit both ways.
+	var lines []string
+	lines = append(lines, `{"event_type":"book","asset_id":"yes","timestamp":"1000","buys":[{"price":"0.45","size":"10"}],"sells":[{"price":"0.55","size":"10"}]}`)
+	for i := 1; i <= 10; i++ {
+		ts := 1000 + i*6000
+		side, price := "SELL", "0.35"
+		if i%2 == 0 {
+			side, price = "BUY", "0.65"
+		}
+		lines = append(lines, fmt.Sprintf(
+			`{"event_type":"last_trade_price","asset_id":"yes","price":"%s","size":"50","side":"%s","timestamp":"%d"}`,
+			price, side, ts))
+	}
+
+	events, err := ReadEvents(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+
+	report := runner.Run(context.Background(), events)
+
+	if report.Events != len(events) {
+		t.Errorf("Events = %d, want %d", report.Events, len(events))
+	}
+	if report.QuoteCycles == 0 {
+		t.Fatal("expected quote cycles")
+	}
+	if report.Exec.OrdersPlaced == 0 {
+		t.Fatal("expected orders to be placed")
+	}
+	if report.Exec.Fills == 0 {
+		t.Fatal("expected fills against trades through our quotes")
+	}
+	if report.FillRate <= 0 || report.FillRate > 1 {
+		t.Errorf("FillRate = %v, want (0, 1]", report.FillRate)
+	}
+	if report.RealizedPnL <= 0 {
+		t.Errorf("round-tripping the spread should realize profit, got %v", report.RealizedPnL)
+	}
+	if len(report.InventoryPath) != report.QuoteCycles {
+		t.Errorf("inventory samples = %d, want %d", len(report.InventoryPath), report.QuoteCycles)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteInventoryCSV(&buf); err != nil {
+		t.Fatalf("WriteInventoryCSV: %v", err)
+	}
+	if rows := strings.Count(buf.String(), "\n"); rows != len(report.InventoryPath)+1 {
+		t.Errorf("csv rows = %d, want %d", rows, len(report.InventoryPath)+1)
+	}
+}
+
+func TestReadEventsUnwrapsRecorderFrames(t *testing.T) {
+	t.Parallel()
+	data := strings.Join([]string{
+		`{"recv_ns":5000000000,"channel":"market","data":{"event_type":"book","asset_id":"yes","buys":[],"sells":[]}}`,
+		`{"recv_ns":6000000000,"channel":"user","data":{"event_type":"trade","id":"t1"}}`,
+		`{"recv_ns":7000000000,"channel":"market","data":{"event_type":"last_trade_price","asset_id":"yes","price":"0.5","size":"1","side":"BUY","timestamp":"6500"}}`,
+	}, "\n")
+
+	events, err := ReadEvents(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 (user-channel frames skipped)", len(events))
+	}
+	if !events[0].Time.Equal(time.Unix(5, 0)) {
+		t.Errorf("book without exchange timestamp should use recv time, got %v", events[0].Time)
+	}
+	if !events[1].Time.Equal(time.UnixMilli(6500)) {
+		t.Errorf("exchange timestamp should win over recv time, got %v", events[1].Time)
+	}
+}
+
+func TestLoadRecordingReadsEverySegment(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	trade := func(ms int64) []byte {
+		return []byte(fmt.Sprintf(`{"event_type":"last_trade_price","market":"m1","asset_id":"yes","price":"0.5","size":"1","side":"BUY","timestamp":"%d"}`, ms))
+	}
+
+	// Two sessions on the first day, in different compressions, then one
+	// on each of the next two days
+	sessions := []struct {
+		compression string
+		recv        time.Time
+		frames      [][]byte
+	}{
+		{store.CompressionZstd, day, [][]byte{
+			[]byte(`{"event_type":"book","market":"m1","asset_id":"yes","buys":[],"sells":[]}`),
+			trade(day.Add(time.Second).UnixMilli()),
+		}},
+		{store.CompressionGzip, day.Add(time.Hour), [][]byte{
+			trade(day.Add(time.Hour).UnixMilli()),
+			[]byte(`{"event_type":"trade","market":"m1","id":"t1"}`),
+		}},
+		{store.CompressionZstd, day.Add(24 * time.Hour), [][]byte{trade(day.Add(24 * time.Hour).UnixMilli())}},
+		{store.CompressionNone, day.Add(48 * time.Hour), [][]byte{trade(day.Add(48 * time.Hour).UnixMilli())}},
+	}
+	for _, s := range sessions {
+		rec, err := store.NewRecorder(dir, s.compression, logger)
+		if err != nil {
+			t.Fatalf("NewRecorder: %v", err)
+		}
+		for _, f := range s.frames {
+			rec.RecordFrame("market", s.recv, f)
+		}
+		rec.Close()
+	}
+
+	events, err := LoadRecording(dir, "m1", day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadRecording: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4 from both segments of day one and day two", len(events))
+	}
+	if events[0].Book == nil || !events[0].Time.Equal(day) {
+		t.Errorf("first event = %+v, want the book at its receive time", events[0])
+	}
+	if last := events[3]; last.Trade == nil || !last.Time.Equal(day.Add(24*time.Hour)) {
+		t.Errorf("last event = %+v, want day two's trade", last)
+	}
+
+	if _, err := LoadRecording(dir, "m2", time.Time{}, time.Time{}); err == nil {
+		t.Error("expected an error for a market with no recordings")
+	}
+}
@@ -21,6 +21,9 @@ type Config struct {
 	Risk      RiskConfig      `mapstructure:"risk"`
 	Scanner   ScannerConfig   `mapstructure:"scanner"`
 	Store     StoreConfig     `mapstructure:"store"`
+	Paper     PaperConfig     `mapstructure:"paper"`
+	Reconcile ReconcileConfig `mapstructure:"reconcile"`
+	Heartbeat HeartbeatConfig `mapstructure:"heartbeat"`
 	Logging   LoggingConfig   `mapstructure:"logging"`
 	Dashboard DashboardConfig `mapstructure:"dashboard"`
 }
@@ -45,14 +48,34 @@ type APIConfig struct {
 	ApiKey       string `mapstructure:"api_key"`
 	Secret       string `mapstructure:"secret"`
 	Passphrase   string `mapstructure:"passphrase"`
+
+	RateLimits RateLimitConfig `mapstructure:"rate_limits"`
+}
+
+// RateLimitConfig overrides the CLOB client's per-category token buckets.
+// Zero fields keep the defaults tuned to Polymarket's published limits.
+type RateLimitConfig struct {
+	Order  BucketConfig `mapstructure:"order"`
+	Cancel BucketConfig `mapstructure:"cancel"`
+	Book   BucketConfig `mapstructure:"book"`
+	Data   BucketConfig `mapstructure:"data"`
+}
+
+// BucketConfig is one token bucket: Burst requests at once, refilled at
+// Rate per second.
+type BucketConfig struct {
+	Burst float64 `mapstructure:"burst"`
+	Rate  float64 `mapstructure:"rate"`
 }
 
 // StrategyConfig tunes the Avellaneda-Stoikov market-making algorithm.
 //
 //   - Gamma: risk aversion parameter. Higher = tighter spread, less inventory risk.
-//   - Sigma: estimated price volatility (annualized std dev).
+//   - Sigma: prior price volatility (annualized std dev), used until the
+//     online estimator has warmed up.
 //   - K:     order arrival rate. Higher K = more aggressive quotes.
-//   - T:     time horizon in years (e.g. 1.0 = 1 year).
+//   - T:     maximum time horizon in years (e.g. 1.0 = 1 year). The Maker uses
+//     the time left until the market's EndDate when that is shorter.
 //   - DefaultSpreadBps: minimum spread floor in basis points.
 //   - OrderSizeUSD: target notional size per order.
 //   - RefreshInterval: how often to recompute and reconcile quotes.
@@ -63,6 +86,93 @@ type APIConfig struct {
 //   - FlowToxicityThreshold: toxicity score above this triggers spread widening (e.g., 0.6).
 //   - FlowCooldownPeriod: stay wide for this duration after toxicity detected (e.g., 120s).
 //   - FlowMaxSpreadMultiplier: maximum spread widening factor (e.g., 3.0x).
+//     Only the side being filled takes the full widening; the other widens
+//     in proportion to the fills it gets, so it can stay tight to unwind.
+//   - FlowPullMultiplier: pull a side whose multiplier reaches this instead
+//     of widening it further (0 = never pull).
+//
+// Markouts (where the mid went after each fill, per side):
+//   - MarkoutHorizons: delays after a fill at which the mid is sampled
+//     (default 5s, 30s, 2m).
+//   - MarkoutWindow: how long resolved markouts count towards the recent
+//     average (default 10m).
+//   - MarkoutToxicity: score flow toxicity from each side's recent average
+//     markout instead of fill imbalance and velocity.
+//   - MarkoutToxicScale: adverse markout, in price, that scores 1.0
+//     (default 0.02).
+//
+// Trade tape (public last_trade_price prints; each detector is off while its
+// window or size is zero):
+//   - TapeClusterWindow / TapeClusterPrints: a cluster is at least
+//     TapeClusterPrints (default 5) prints by takers on one side within the
+//     window.
+//   - TapeSweepWindow / TapeSweepLevels: a sweep is prints by takers on one
+//     side at TapeSweepLevels (default 3) or more distinct prices within the
+//     window, i.e. several levels consumed.
+//   - TapeLargePrintUSD: a single print of at least this notional is treated
+//     as a sweep.
+//   - TapeHold: how long the side of our quotes the flow is heading into is
+//     defended after a detection (default 30s).
+//   - TapeSpreadMultiplier: spread widening of a defended side (default 2.0).
+//   - TapePull: pull a defended side instead of widening it.
+//
+// Volatility estimation (EWMA of mid returns sampled from book updates):
+//   - VolHalfLife: decay half-life of the EWMA (e.g., 10m).
+//   - VolSampleInterval: minimum spacing between mid samples (e.g., 5s).
+//   - VolWarmupSamples: returns required before replacing Sigma (e.g., 30).
+//   - VolFloor / VolCap: bounds on the sigma used for quoting (0 = unbounded).
+//
+// Resolution tapering (zero window = stage disabled):
+//   - ResolutionWidenWindow: inside this window of EndDate, multiply the spread
+//     by ResolutionSpreadMultiplier and size by ResolutionSizeFactor.
+//   - ResolutionReduceOnlyWindow: only quote the side that reduces inventory.
+//   - ResolutionStopWindow: cancel all orders and stop quoting.
+//
+// Fair value and book imbalance:
+//   - FairValue: the reference price fed into the reservation price: "mid"
+//     (default), "microprice" (touch weighted by touch size) or
+//     "weighted_mid" (touch weighted by the size in the top ImbalanceLevels).
+//   - ImbalanceLevels: book levels per side counted for imbalance and the
+//     weighted mid (default 3).
+//   - ImbalanceSkew: price widening, per unit of imbalance in [-1, 1], of the
+//     side the book leans against (the ask when bids are heavier). 0 = off.
+//
+// Cross-book quoting:
+//   - QuoteBothTokens: quote on both YES and NO tokens. Fair value comes from
+//     the synthetic touch max(YES bid, 1-NO ask) / min(YES ask, 1-NO bid),
+//     and each economic side is routed to whichever token gives the better
+//     resting price (e.g. buy NO instead of selling YES we don't hold).
+//
+// Quote ladders (LadderLevels 0 or 1 = a single quote per side):
+//   - LadderLevels: orders per side, the A-S quote plus levels behind it.
+//   - LadderSpacingTicks: ticks between consecutive levels (default 1).
+//   - LadderSizeRatio: each level's size relative to the one before it
+//     (default 1 = flat; above 1 puts more size deeper in the book).
+//
+// Order types:
+//   - QuoteOrderType: "GTC" (default) or "GTD". GTD quotes expire
+//     GTDLifetimeCycles refresh intervals after placement (default 3) and are
+//     renewed before then, so they lapse on their own if the bot stops.
+//   - PostOnly: quotes are rejected rather than crossing the spread.
+//   - FlattenOrderType: "FAK" (default) or "FOK" for orders that flatten
+//     inventory by taking liquidity.
+//
+// Liquidity rewards:
+//   - RewardsMode: on markets with a rewards program, pull each quote inside
+//     the market's rewards max spread and up to its min size whenever risk
+//     allows (not in reduce-only, toxic flow, or beyond the risk budget).
+//
+// Multi-outcome events:
+//   - EventCoherence: for outcomes of a neg-risk event, rescale the fair
+//     price so the event's YES prices sum to 1 (see market.EventBook).
+//
+// Wind-down (zero DrainTimeout = stop markets immediately):
+//   - DrainTimeout: how long a deselected market, or every market on
+//     shutdown, quotes only the inventory-reducing side, stepping from its
+//     normal price to the touch. A market is stopped once flat; past the
+//     deadline it keeps quoting at the touch until it is.
+//   - DrainFlatten: from the deadline, sell what's left with
+//     FlattenOrderType instead, once per RefreshInterval, until flat.
 type StrategyConfig struct {
 	Gamma            float64       `mapstructure:"gamma"`
 	Sigma            float64       `mapstructure:"sigma"`
@@ -78,6 +188,66 @@ type StrategyConfig struct {
 	FlowToxicityThreshold   float64       `mapstructure:"flow_toxicity_threshold"`
 	FlowCooldownPeriod      time.Duration `mapstructure:"flow_cooldown_period"`
 	FlowMaxSpreadMultiplier float64       `mapstructure:"flow_max_spread_multiplier"`
+	FlowPullMultiplier      float64       `mapstructure:"flow_pull_multiplier"`
+
+	// Markouts
+	MarkoutHorizons   []time.Duration `mapstructure:"markout_horizons"`
+	MarkoutWindow     time.Duration   `mapstructure:"markout_window"`
+	MarkoutToxicity   bool            `mapstructure:"markout_toxicity"`
+	MarkoutToxicScale float64         `mapstructure:"markout_toxic_scale"`
+
+	// Trade tape
+	TapeClusterWindow    time.Duration `mapstructure:"tape_cluster_window"`
+	TapeClusterPrints    int           `mapstructure:"tape_cluster_prints"`
+	TapeSweepWindow      time.Duration `mapstructure:"tape_sweep_window"`
+	TapeSweepLevels      int           `mapstructure:"tape_sweep_levels"`
+	TapeLargePrintUSD    float64       `mapstructure:"tape_large_print_usd"`
+	TapeHold             time.Duration `mapstructure:"tape_hold"`
+	TapeSpreadMultiplier float64       `mapstructure:"tape_spread_multiplier"`
+	TapePull             bool          `mapstructure:"tape_pull"`
+
+	// Volatility estimation
+	VolHalfLife       time.Duration `mapstructure:"vol_half_life"`
+	VolSampleInterval time.Duration `mapstructure:"vol_sample_interval"`
+	VolWarmupSamples  int           `mapstructure:"vol_warmup_samples"`
+	VolFloor          float64       `mapstructure:"vol_floor"`
+	VolCap            float64       `mapstructure:"vol_cap"`
+
+	// Resolution tapering
+	ResolutionWidenWindow      time.Duration `mapstructure:"resolution_widen_window"`
+	ResolutionSpreadMultiplier float64       `mapstructure:"resolution_spread_multiplier"`
+	ResolutionSizeFactor       float64       `mapstructure:"resolution_size_factor"`
+	ResolutionReduceOnlyWindow time.Duration `mapstructure:"resolution_reduce_only_window"`
+	ResolutionStopWindow       time.Duration `mapstructure:"resolution_stop_window"`
+
+	// Fair value and book imbalance
+	FairValue       string  `mapstructure:"fair_value"`
+	ImbalanceLevels int     `mapstructure:"imbalance_levels"`
+	ImbalanceSkew   float64 `mapstructure:"imbalance_skew"`
+
+	// Cross-book quoting
+	QuoteBothTokens bool `mapstructure:"quote_both_tokens"`
+
+	// Quote ladders
+	LadderLevels       int     `mapstructure:"ladder_levels"`
+	LadderSpacingTicks int     `mapstructure:"ladder_spacing_ticks"`
+	LadderSizeRatio    float64 `mapstructure:"ladder_size_ratio"`
+
+	// Order types
+	QuoteOrderType    string `mapstructure:"quote_order_type"`
+	PostOnly          bool   `mapstructure:"post_only"`
+	GTDLifetimeCycles int    `mapstructure:"gtd_lifetime_cycles"`
+	FlattenOrderType  string `mapstructure:"flatten_order_type"`
+
+	// Liquidity rewards
+	RewardsMode bool `mapstructure:"rewards_mode"`
+
+	// Multi-outcome events
+	EventCoherence bool `mapstructure:"event_coherence"`
+
+	// Wind-down
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+	DrainFlatten bool          `mapstructure:"drain_flatten"`
 }
 
 // RiskConfig sets hard limits that trigger order cancellation (kill switch).
@@ -89,6 +259,8 @@ type StrategyConfig struct {
 //   - KillSwitchWindowSec: time window for measuring rapid price movement.
 //   - MaxDailyLoss: max combined (realized + unrealized) loss before kill switch.
 //   - CooldownAfterKill: how long the kill switch stays engaged after firing.
+//   - MaxEventExposure: max USD exposure summed across the outcomes of one
+//     neg-risk event (0 = no event limit).
 type RiskConfig struct {
 	MaxPositionPerMarket float64       `mapstructure:"max_position_per_market"`
 	MaxGlobalExposure    float64       `mapstructure:"max_global_exposure"`
@@ -97,30 +269,95 @@ type RiskConfig struct {
 	KillSwitchWindowSec  int           `mapstructure:"kill_switch_window_sec"`
 	MaxDailyLoss         float64       `mapstructure:"max_daily_loss"`
 	CooldownAfterKill    time.Duration `mapstructure:"cooldown_after_kill"`
+	MaxEventExposure     float64       `mapstructure:"max_event_exposure"`
 }
 
 // ScannerConfig controls how the bot discovers and filters tradeable markets.
-// The scanner polls the Gamma API and ranks markets by opportunity score:
+// The scanner polls the Gamma API and ranks markets by a weighted sum of
+// scorers: Scoring maps scorer name (opportunity, rewards, spread_capture,
+// time_to_resolution, vol_edge) to weight. Empty means opportunity alone:
 // score = spread * sqrt(volume24h) * min(liquidity/10000, 1).
 // IncludeConditionIDs/IncludeSlugs/IncludeKeywords can constrain discovery to
 // a specific market set (useful for BTC-only strategies). ExcludeKeywords can
 // further remove noisy sub-families (for example 5m/15m contracts).
+// EnterScore, ExitScore and MinHoldTime damp churn at the MaxMarketsActive
+// cut-off (see market.Select); zero values select purely by rank.
 type ScannerConfig struct {
-	PollInterval        time.Duration `mapstructure:"poll_interval"`
-	MinLiquidity        float64       `mapstructure:"min_liquidity"`
-	MinVolume24h        float64       `mapstructure:"min_volume_24h"`
-	MinSpread           float64       `mapstructure:"min_spread"`
-	MaxEndDateDays      int           `mapstructure:"max_end_date_days"`
-	IncludeConditionIDs []string      `mapstructure:"include_condition_ids"`
-	IncludeSlugs        []string      `mapstructure:"include_slugs"`
-	IncludeKeywords     []string      `mapstructure:"include_keywords"`
-	ExcludeKeywords     []string      `mapstructure:"exclude_keywords"`
-	ExcludeSlugs        []string      `mapstructure:"exclude_slugs"`
+	PollInterval        time.Duration      `mapstructure:"poll_interval"`
+	MinLiquidity        float64            `mapstructure:"min_liquidity"`
+	MinVolume24h        float64            `mapstructure:"min_volume_24h"`
+	MinSpread           float64            `mapstructure:"min_spread"`
+	MaxEndDateDays      int                `mapstructure:"max_end_date_days"`
+	IncludeConditionIDs []string           `mapstructure:"include_condition_ids"`
+	IncludeSlugs        []string           `mapstructure:"include_slugs"`
+	IncludeKeywords     []string           `mapstructure:"include_keywords"`
+	ExcludeKeywords     []string           `mapstructure:"exclude_keywords"`
+	ExcludeSlugs        []string           `mapstructure:"exclude_slugs"`
+	Scoring             map[string]float64 `mapstructure:"scoring"`
+	EnterScore          float64            `mapstructure:"enter_score"`
+	ExitScore           float64            `mapstructure:"exit_score"`
+	MinHoldTime         time.Duration      `mapstructure:"min_hold_time"`
 }
 
 // StoreConfig sets where position data is persisted (JSON files).
 type StoreConfig struct {
-	DataDir string `mapstructure:"data_dir"`
+	DataDir  string         `mapstructure:"data_dir"`
+	Recorder RecorderConfig `mapstructure:"recorder"`
+	Journal  JournalConfig  `mapstructure:"journal"`
+}
+
+// JournalConfig controls the append-only order lifecycle journal written to
+// <data_dir>/journal/<YYYY-MM-DD>.jsonl. Fsync is "always" (every entry),
+// "interval" (default, every FsyncInterval, default 1s) or "never".
+type JournalConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Fsync         string        `mapstructure:"fsync"`
+	FsyncInterval time.Duration `mapstructure:"fsync_interval"`
+}
+
+// RecorderConfig controls raw WS feed recording. When enabled, every market-
+// and user-channel frame is written with its local receive time to
+// <data_dir>/recordings/<YYYY-MM-DD>/<condition_id>.jsonl[.gz|.zst], with a
+// new <condition_id>.<n>.jsonl[.gz|.zst] segment for each restart on the
+// same day. Compression is "gzip" (default), "zstd" or "none".
+type RecorderConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	Compression string `mapstructure:"compression"`
+}
+
+// PaperConfig sets up the paper exchange used in dry-run mode. Orders rest
+// on a virtual book and fill against the live market feed; StartingUSDC is
+// the virtual cash balance (default 1000).
+type PaperConfig struct {
+	StartingUSDC float64 `mapstructure:"starting_usdc"`
+}
+
+// ReconcileConfig controls how each market's local state is checked against
+// the exchange when it starts. Resting orders are adopted into the Maker when
+// AdoptOrders is set (cancelled otherwise), missed trades are replayed into
+// inventory, and token quantities that still differ from the wallet balance
+// by more than Tolerance (default 0.01) are overwritten.
+//
+// While running, every Interval (0 disables) the engine re-reads open orders
+// and trades from the last TradeLookback and books any fills the WS feed
+// dropped. TradeLookback must cover at least one Interval.
+type ReconcileConfig struct {
+	AdoptOrders   bool          `mapstructure:"adopt_orders"`
+	Tolerance     float64       `mapstructure:"tolerance"`
+	Interval      time.Duration `mapstructure:"interval"`
+	TradeLookback time.Duration `mapstructure:"trade_lookback"`
+}
+
+// HeartbeatConfig controls the exchange-side dead-man switch that pulls our
+// quotes if the bot hangs or loses its connection. Mode "auto" sends a
+// heartbeat to the CLOB every Interval (the exchange cancels all our orders
+// if one doesn't arrive within ~10s) and falls back to GTD quotes if the
+// heartbeat endpoint can't be armed at startup. Mode "gtd" always quotes GTD
+// so orders lapse shortly after the bot stops renewing them. Empty or "off"
+// disables both; quotes then rely on Engine.Stop's cancel-all.
+type HeartbeatConfig struct {
+	Mode     string        `mapstructure:"mode"`
+	Interval time.Duration `mapstructure:"interval"`
 }
 
 type LoggingConfig struct {
@@ -198,14 +435,119 @@ func (c *Config) Validate() error {
 	if c.Strategy.OrderSizeUSD <= 0 {
 		return fmt.Errorf("strategy.order_size_usd must be > 0")
 	}
+	if c.Strategy.VolCap > 0 && c.Strategy.VolFloor > c.Strategy.VolCap {
+		return fmt.Errorf("strategy.vol_floor must be <= strategy.vol_cap")
+	}
+	if c.Strategy.ResolutionSizeFactor < 0 || c.Strategy.ResolutionSizeFactor > 1 {
+		return fmt.Errorf("strategy.resolution_size_factor must be in [0, 1]")
+	}
+	switch c.Strategy.FairValue {
+	case "", "mid", "microprice", "weighted_mid":
+	default:
+		return fmt.Errorf("strategy.fair_value must be one of: mid, microprice, weighted_mid")
+	}
+	if c.Strategy.ImbalanceLevels < 0 || c.Strategy.ImbalanceSkew < 0 {
+		return fmt.Errorf("strategy.imbalance_levels and strategy.imbalance_skew must be >= 0")
+	}
+	if c.Strategy.FlowPullMultiplier != 0 && c.Strategy.FlowPullMultiplier <= 1 {
+		return fmt.Errorf("strategy.flow_pull_multiplier must be 0 (off) or > 1")
+	}
+	if c.Strategy.TapeClusterWindow < 0 || c.Strategy.TapeSweepWindow < 0 || c.Strategy.TapeHold < 0 {
+		return fmt.Errorf("strategy.tape_cluster_window, tape_sweep_window and tape_hold must be >= 0")
+	}
+	if c.Strategy.TapeClusterPrints < 0 || c.Strategy.TapeSweepLevels < 0 || c.Strategy.TapeLargePrintUSD < 0 {
+		return fmt.Errorf("strategy.tape_cluster_prints, tape_sweep_levels and tape_large_print_usd must be >= 0")
+	}
+	if c.Strategy.TapeSpreadMultiplier != 0 && c.Strategy.TapeSpreadMultiplier < 1 {
+		return fmt.Errorf("strategy.tape_spread_multiplier must be 0 (default) or >= 1")
+	}
+	for _, h := range c.Strategy.MarkoutHorizons {
+		if h <= 0 {
+			return fmt.Errorf("strategy.markout_horizons must be > 0")
+		}
+	}
+	if c.Strategy.MarkoutWindow < 0 || c.Strategy.MarkoutToxicScale < 0 {
+		return fmt.Errorf("strategy.markout_window and strategy.markout_toxic_scale must be >= 0")
+	}
+	if c.Strategy.LadderLevels < 0 || c.Strategy.LadderSpacingTicks < 0 || c.Strategy.LadderSizeRatio < 0 {
+		return fmt.Errorf("strategy.ladder_levels, ladder_spacing_ticks and ladder_size_ratio must be >= 0")
+	}
+	switch c.Strategy.QuoteOrderType {
+	case "", "GTC", "GTD":
+	default:
+		return fmt.Errorf("strategy.quote_order_type must be one of: GTC, GTD")
+	}
+	switch c.Strategy.FlattenOrderType {
+	case "", "FAK", "FOK":
+	default:
+		return fmt.Errorf("strategy.flatten_order_type must be one of: FAK, FOK")
+	}
+	if c.Strategy.GTDLifetimeCycles < 0 || c.Strategy.GTDLifetimeCycles == 1 {
+		return fmt.Errorf("strategy.gtd_lifetime_cycles must be >= 2 (or 0 for the default)")
+	}
+	if c.Strategy.DrainTimeout < 0 {
+		return fmt.Errorf("strategy.drain_timeout must be >= 0")
+	}
 	if c.Risk.MaxPositionPerMarket <= 0 {
 		return fmt.Errorf("risk.max_position_per_market must be > 0")
 	}
 	if c.Risk.MaxGlobalExposure <= 0 {
 		return fmt.Errorf("risk.max_global_exposure must be > 0")
 	}
+	if c.Risk.MaxEventExposure < 0 {
+		return fmt.Errorf("risk.max_event_exposure must be >= 0")
+	}
 	if c.Risk.MaxMarketsActive <= 0 {
 		return fmt.Errorf("risk.max_markets_active must be > 0")
 	}
+	switch c.Store.Recorder.Compression {
+	case "", "gzip", "zstd", "none":
+	default:
+		return fmt.Errorf("store.recorder.compression must be one of: gzip, zstd, none")
+	}
+	if c.Reconcile.Tolerance < 0 {
+		return fmt.Errorf("reconcile.tolerance must be >= 0")
+	}
+	if c.Reconcile.Interval > 0 && c.Reconcile.TradeLookback < c.Reconcile.Interval {
+		return fmt.Errorf("reconcile.trade_lookback must be >= reconcile.interval")
+	}
+	for _, b := range []struct {
+		name string
+		cfg  BucketConfig
+	}{
+		{"order", c.API.RateLimits.Order},
+		{"cancel", c.API.RateLimits.Cancel},
+		{"book", c.API.RateLimits.Book},
+		{"data", c.API.RateLimits.Data},
+	} {
+		if b.cfg.Burst < 0 || b.cfg.Rate < 0 {
+			return fmt.Errorf("api.rate_limits.%s burst and rate must be >= 0", b.name)
+		}
+	}
+	for name, weight := range c.Scanner.Scoring {
+		if weight < 0 {
+			return fmt.Errorf("scanner.scoring.%s weight must be >= 0", name)
+		}
+	}
+	if c.Scanner.ExitScore < 0 || c.Scanner.ExitScore > c.Scanner.EnterScore {
+		return fmt.Errorf("scanner.exit_score must be >= 0 and <= enter_score")
+	}
+	if c.Scanner.MinHoldTime < 0 {
+		return fmt.Errorf("scanner.min_hold_time must be >= 0")
+	}
+	switch c.Heartbeat.Mode {
+	case "", "off", "gtd":
+	case "auto":
+		if c.Heartbeat.Interval <= 0 || c.Heartbeat.Interval >= 10*time.Second {
+			return fmt.Errorf("heartbeat.interval must be > 0 and < 10s")
+		}
+	default:
+		return fmt.Errorf("heartbeat.mode must be one of: auto, gtd, off")
+	}
+	switch c.Store.Journal.Fsync {
+	case "", "always", "interval", "never":
+	default:
+		return fmt.Errorf("store.journal.fsync must be one of: always, interval, never")
+	}
 	return nil
 }
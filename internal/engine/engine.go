@@ -14,7 +14,11 @@ package engine
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
@@ -22,6 +26,8 @@ import (
 	"polymarket-mm/internal/config"
 	"polymarket-mm/internal/exchange"
 	"polymarket-mm/internal/market"
+	"polymarket-mm/internal/paper"
+	"polymarket-mm/internal/reconcile"
 	"polymarket-mm/internal/risk"
 	"polymarket-mm/internal/store"
 	"polymarket-mm/internal/strategy"
@@ -35,28 +41,70 @@ type marketSlot struct {
 	book      *market.Book
 	inventory *strategy.Inventory
 	maker     *strategy.Maker
+	event     *market.EventBook // nil unless an outcome of a neg-risk event
 	cancel    context.CancelFunc
+	done      <-chan struct{} // closed when the slot's context is cancelled
 	tradeCh   chan types.WSTradeEvent
 	orderCh   chan types.WSOrderEvent
+	startedAt time.Time
+	released  bool // operator allowed the scanner to drop it with inventory
+}
+
+// orderGateway is where the engine and Makers send orders: the live
+// exchange.Client, or the paper exchange in dry-run mode.
+type orderGateway interface {
+	strategy.OrderGateway
+	CancelAll(ctx context.Context) (*types.CancelResponse, error)
+}
+
+// exchangeClient is the CLOB REST API the engine reads from, implemented by
+// exchange.Client.
+type exchangeClient interface {
+	reconcile.Exchange
+	GetOrderBook(ctx context.Context, tokenID string) (*types.BookResponse, error)
+	GetFeeRate(ctx context.Context, tokenID string) (int, error)
+	PostHeartbeat(ctx context.Context, heartbeatID string) (string, error)
+	RateLimitStats() map[string]exchange.BucketStats
 }
 
 // Engine orchestrates all components of the market-making system.
 // It owns the lifecycle of all goroutines and manages market start/stop transitions.
 type Engine struct {
 	cfg     config.Config
-	client  *exchange.Client
+	client  exchangeClient
+	gateway orderGateway    // client, or paper in dry-run mode
+	paper   *paper.Exchange // nil unless dry-run
 	auth    *exchange.Auth
 	mktFeed *exchange.WSFeed
 	usrFeed *exchange.WSFeed
 	scanner *market.Scanner
 	riskMgr *risk.Manager
 	store   *store.Store
+	rec     *store.Recorder // nil unless store.recorder.enabled
+	journal *store.Journal  // nil unless store.journal.enabled
 	logger  *slog.Logger
 
 	// slots maps conditionID → running market. Protected by slotsMu.
 	slots   map[string]*marketSlot
 	slotsMu sync.RWMutex
 
+	// resolved holds markets stopped by a market_resolved event so the
+	// scanner can't restart them. Protected by slotsMu.
+	resolved map[string]bool
+
+	// shuttingDown is set by Drain so scans no longer start or drain
+	// markets. Protected by slotsMu.
+	shuttingDown bool
+
+	// gtdQuotes is set by armDeadManSwitch, before any market starts, when
+	// quotes must expire on their own; makerConfig applies it.
+	gtdQuotes bool
+
+	// events holds the neg-risk events of the latest scan; eventBooks the
+	// cross-outcome view of each event we trade. Protected by slotsMu.
+	events     map[string]types.EventInfo
+	eventBooks map[string]*market.EventBook
+
 	// tokenMap maps tokenID → conditionID so WS market events (keyed by token)
 	// can be routed to the correct market slot (keyed by condition).
 	tokenMap   map[string]string
@@ -101,6 +149,33 @@ func New(cfg config.Config, logger *slog.Logger) (*Engine, error) {
 		return nil, err
 	}
 
+	var rec *store.Recorder
+	if cfg.Store.Recorder.Enabled {
+		rec, err = store.NewRecorder(cfg.Store.DataDir, cfg.Store.Recorder.Compression, logger)
+		if err != nil {
+			return nil, err
+		}
+		mktFeed.SetRecorder(rec)
+		usrFeed.SetRecorder(rec)
+		logger.Info("recording ws feeds", "dir", cfg.Store.DataDir, "compression", cfg.Store.Recorder.Compression)
+	}
+
+	var journal *store.Journal
+	if cfg.Store.Journal.Enabled {
+		journal, err = store.OpenJournal(cfg.Store.DataDir, cfg.Store.Journal.Fsync, cfg.Store.Journal.FsyncInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("journaling orders", "dir", cfg.Store.DataDir, "fsync", cfg.Store.Journal.Fsync)
+	}
+
+	var gateway orderGateway = client
+	var paperEx *paper.Exchange
+	if cfg.DryRun {
+		paperEx = paper.NewExchange(cfg.Paper.StartingUSDC, logger)
+		gateway = paperEx
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	var dashEvents chan api.DashboardEvent
@@ -108,27 +183,44 @@ func New(cfg config.Config, logger *slog.Logger) (*Engine, error) {
 		dashEvents = make(chan api.DashboardEvent, 100)
 	}
 
-	return &Engine{
+	e := &Engine{
 		cfg:             cfg,
 		client:          client,
+		gateway:         gateway,
+		paper:           paperEx,
 		auth:            auth,
 		mktFeed:         mktFeed,
 		usrFeed:         usrFeed,
 		scanner:         scanner,
 		riskMgr:         riskMgr,
 		store:           st,
+		rec:             rec,
+		journal:         journal,
 		logger:          logger.With("component", "engine"),
 		slots:           make(map[string]*marketSlot),
+		resolved:        make(map[string]bool),
+		eventBooks:      make(map[string]*market.EventBook),
 		tokenMap:        make(map[string]string),
 		dashboardEvents: dashEvents,
 		ctx:             ctx,
 		cancel:          cancel,
-	}, nil
+	}
+	scanner.SetHistory(e)
+	return e, nil
 }
 
 // Start launches all background goroutines: WS feeds, scanner, risk manager,
 // event dispatchers, and the main market management loop.
 func (e *Engine) Start() error {
+	// Arm the dead-man switch before any market can quote
+	if heartbeatID, ok := e.armDeadManSwitch(); ok {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			e.heartbeatLoop(heartbeatID)
+		}()
+	}
+
 	// Start WebSocket feeds
 	e.wg.Add(1)
 	go func() {
@@ -180,6 +272,16 @@ func (e *Engine) Start() error {
 		e.manageMarkets()
 	}()
 
+	// Start periodic reconciliation (live only; the paper exchange is the
+	// source of its own fills)
+	if e.paper == nil && e.cfg.Reconcile.Interval > 0 {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			e.reconcileLoop()
+		}()
+	}
+
 	return nil
 }
 
@@ -194,15 +296,20 @@ func (e *Engine) Stop() {
 	// Safety net: cancel all orders on the exchange
 	cancelCtx, cancelCancel := context.WithTimeout(context.Background(), e.cfg.Strategy.StaleBookTimeout)
 	defer cancelCancel()
-	if _, err := e.client.CancelAll(cancelCtx); err != nil {
+	if resp, err := e.gateway.CancelAll(cancelCtx); err != nil {
 		e.logger.Error("failed to cancel all orders on shutdown", "error", err)
+	} else {
+		e.journalCancels("", resp, "shutdown")
+	}
+	if e.paper != nil {
+		bal := e.paper.Balances()
+		e.logger.Info("paper account", "usdc", bal.USDC, "tokens", bal.Tokens)
 	}
 
 	// Persist final positions
 	e.slotsMu.RLock()
 	for id, slot := range e.slots {
-		pos := slot.inventory.Snapshot()
-		if err := e.store.SavePosition(id, pos); err != nil {
+		if err := e.store.SaveState(id, slot.inventory.State()); err != nil {
 			e.logger.Error("failed to save position", "market", id, "error", err)
 		}
 	}
@@ -214,6 +321,12 @@ func (e *Engine) Stop() {
 	// Close resources
 	e.mktFeed.Close()
 	e.usrFeed.Close()
+	if e.rec != nil {
+		e.rec.Close()
+	}
+	if e.journal != nil {
+		e.journal.Close()
+	}
 	e.store.Close()
 
 	e.logger.Info("shutdown complete")
@@ -236,31 +349,167 @@ func (e *Engine) manageMarkets() {
 }
 
 // reconcileMarkets diffs the desired market set (from scanner) against currently
-// running markets. Stops markets no longer desired, starts newly discovered ones.
+// running markets, applying the scanner's hysteresis policy (market.Select).
+// Stops markets no longer desired, starts newly discovered ones.
 func (e *Engine) reconcileMarkets(result market.ScanResult) {
-	desired := make(map[string]types.MarketAllocation)
-	for _, alloc := range result.Markets {
-		desired[alloc.Market.ConditionID] = alloc
-	}
-
 	e.slotsMu.Lock()
 	defer e.slotsMu.Unlock()
 
-	// Stop markets no longer desired
-	for id := range e.slots {
-		if _, ok := desired[id]; !ok {
-			e.stopMarketLocked(id)
+	var ranked []types.MarketAllocation
+	for _, alloc := range slices.Concat(result.Markets, result.Dropped) {
+		if !e.resolved[alloc.Market.ConditionID] {
+			ranked = append(ranked, alloc)
+		}
+	}
+
+	if e.shuttingDown {
+		return
+	}
+
+	e.events = result.Events
+	for id, eb := range e.eventBooks {
+		if info, ok := e.events[id]; ok {
+			eb.Update(info)
+		}
+	}
+
+	// Inventory pins a market until it is flat or released. Draining
+	// markets are on their way out and only take a slot while they still
+	// hold inventory, e.g. past a drain deadline they couldn't flatten by.
+	running := make([]market.Incumbent, 0, len(e.slots))
+	for id, slot := range e.slots {
+		holding := !slot.released && holdsInventory(slot)
+		if slot.maker.Draining() && !holding {
+			continue
+		}
+		running = append(running, market.Incumbent{
+			ConditionID: id,
+			StartedAt:   slot.startedAt,
+			Holding:     holding,
+		})
+	}
+	sel := market.Select(e.cfg.Scanner, e.cfg.Risk.MaxMarketsActive, ranked, running, time.Now())
+
+	// Wind down markets no longer desired
+	for _, inc := range running {
+		if sel.Keep[inc.ConditionID] || e.slots[inc.ConditionID].maker.Draining() {
+			continue
+		}
+		if e.slots[inc.ConditionID].released {
+			e.stopMarketLocked(inc.ConditionID)
+		} else {
+			e.drainMarketLocked(inc.ConditionID)
 		}
 	}
 
-	// Start new markets
-	for id, alloc := range desired {
-		if _, ok := e.slots[id]; !ok {
+	// Start new markets (one still draining is left to finish first)
+	for _, alloc := range sel.Start {
+		if _, ok := e.slots[alloc.Market.ConditionID]; !ok {
 			e.startMarketLocked(alloc)
 		}
 	}
 }
 
+// holdsInventory reports whether a slot still has a net position to reduce.
+// Dust below the minimum order size can't be sold and doesn't count, nor do
+// matched YES/NO pairs, which are worth 1 whatever the outcome.
+func holdsInventory(slot *marketSlot) bool {
+	pos := slot.inventory.Snapshot()
+	return math.Abs(pos.YesQty-pos.NoQty) >= math.Max(slot.info.MinOrderSize, 1e-6)
+}
+
+// drainMarketLocked winds a market down (see strategy.Maker.Drain) and
+// removes it once it is flat. A market still holding inventory at its drain
+// deadline keeps draining, and keeps its slot (see reconcileMarkets), until
+// it is. Without a drain timeout the market is stopped straight away.
+func (e *Engine) drainMarketLocked(conditionID string) {
+	slot, ok := e.slots[conditionID]
+	if !ok || slot.maker.Draining() {
+		return
+	}
+	if e.cfg.Strategy.DrainTimeout <= 0 {
+		e.stopMarketLocked(conditionID)
+		return
+	}
+
+	slot.maker.Drain(time.Now().Add(e.cfg.Strategy.DrainTimeout))
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		select {
+		case <-slot.maker.Drained():
+		case <-slot.done:
+			return
+		}
+		e.slotsMu.Lock()
+		defer e.slotsMu.Unlock()
+		if e.slots[conditionID] == slot {
+			e.stopMarketLocked(conditionID)
+		}
+	}()
+}
+
+// Drain winds every market down ahead of an operator-initiated shutdown and
+// stops the scanner from starting new ones. It blocks until every market is
+// flat or ctx is cancelled, logging the positions left behind if it is; call
+// Stop afterwards. Without a drain timeout it returns immediately.
+func (e *Engine) Drain(ctx context.Context) {
+	if e.cfg.Strategy.DrainTimeout <= 0 {
+		return
+	}
+
+	e.slotsMu.Lock()
+	e.shuttingDown = true
+	slots := make([]*marketSlot, 0, len(e.slots))
+	for id, slot := range e.slots {
+		e.drainMarketLocked(id)
+		slots = append(slots, slot)
+	}
+	e.slotsMu.Unlock()
+
+	e.logger.Info("draining markets before shutdown", "markets", len(slots), "timeout", e.cfg.Strategy.DrainTimeout)
+	for _, slot := range slots {
+		select {
+		case <-slot.maker.Drained():
+		case <-slot.done:
+		case <-ctx.Done():
+			e.logger.Warn("drain interrupted, stopping now")
+			for _, slot := range slots {
+				if holdsInventory(slot) {
+					pos := slot.inventory.Snapshot()
+					e.logger.Error("stopping with inventory held",
+						"slug", slot.info.Slug,
+						"yes_qty", pos.YesQty,
+						"no_qty", pos.NoQty,
+					)
+				}
+			}
+			return
+		}
+	}
+}
+
+// ReleaseMarket lets the scanner drop a market that still holds inventory.
+// The market keeps trading until a scan no longer selects it; its position
+// is left as is when it stops. A market already draining is stopped at once.
+// The dashboard serves it as POST /api/markets/release (see
+// api.MarketReleaser).
+func (e *Engine) ReleaseMarket(conditionID string) error {
+	e.slotsMu.Lock()
+	defer e.slotsMu.Unlock()
+
+	slot, ok := e.slots[conditionID]
+	if !ok {
+		return fmt.Errorf("market %s is not running", conditionID)
+	}
+	slot.released = true
+	e.logger.Info("market released for deselection", "slug", slot.info.Slug)
+	if slot.maker.Draining() {
+		e.stopMarketLocked(conditionID)
+	}
+	return nil
+}
+
 func (e *Engine) startMarketLocked(alloc types.MarketAllocation) {
 	info := alloc.Market
 	if info.YesTokenID == "" || info.NoTokenID == "" {
@@ -268,17 +517,7 @@ func (e *Engine) startMarketLocked(alloc types.MarketAllocation) {
 		return
 	}
 
-	// Safety: reconcile startup state by cancelling any pre-existing resting
-	// orders for this market before beginning a new quote lifecycle.
-	reconcileCtx, cancelReconcile := context.WithTimeout(e.ctx, 10*time.Second)
-	_, err := e.client.CancelMarketOrders(reconcileCtx, info.ConditionID)
-	cancelReconcile()
-	if err != nil {
-		e.logger.Error("startup order reconciliation failed, skipping market",
-			"slug", info.Slug,
-			"condition_id", info.ConditionID,
-			"error", err,
-		)
+	if !e.loadFeeRates(&info) {
 		return
 	}
 
@@ -286,23 +525,51 @@ func (e *Engine) startMarketLocked(alloc types.MarketAllocation) {
 	inv := strategy.NewInventory(info.ConditionID, info.YesTokenID, info.NoTokenID)
 
 	// Restore position from persistence
-	if pos, err := e.store.LoadPosition(info.ConditionID); err == nil && pos != nil {
-		inv.SetPosition(*pos)
+	if state, err := e.store.LoadState(info.ConditionID); err == nil && state != nil {
+		inv.Restore(*state)
+	}
+
+	// Safety: reconcile startup state against the exchange before beginning
+	// a new quote lifecycle.
+	report, ok := e.reconcileStartup(info, inv)
+	if !ok {
+		return
+	}
+	if e.paper != nil {
+		e.paper.AddMarket(info, book)
 	}
 
 	tradeCh := make(chan types.WSTradeEvent, 64)
 	orderCh := make(chan types.WSOrderEvent, 64)
 
 	maker := strategy.NewMaker(
-		e.cfg.Strategy,
+		e.makerConfig(),
 		info,
 		book,
 		inv,
-		e.client,
+		e.gateway,
 		e.riskMgr,
 		e.logger,
 		e.dashboardEvents,
 	)
+	if e.journal != nil {
+		maker.SetJournal(e.journal)
+	}
+	maker.SetStateStore(e.store)
+	maker.SetMarkoutStore(e.store)
+	if state, err := e.store.LoadMarkouts(info.ConditionID); err != nil {
+		e.logger.Warn("failed to load markouts", "market", info.Slug, "error", err)
+	} else if state != nil {
+		maker.RestoreMarkouts(*state)
+	}
+	eventBook := e.attachEventLocked(info, book)
+	if eventBook != nil {
+		maker.SetEventBook(eventBook)
+		e.riskMgr.SetEvent(info.ConditionID, info.EventID)
+	}
+	if report != nil {
+		maker.AdoptOrders(report.Adopted)
+	}
 
 	ctx, cancel := context.WithCancel(e.ctx)
 
@@ -311,9 +578,12 @@ func (e *Engine) startMarketLocked(alloc types.MarketAllocation) {
 		book:      book,
 		inventory: inv,
 		maker:     maker,
+		event:     eventBook,
 		cancel:    cancel,
+		done:      ctx.Done(),
 		tradeCh:   tradeCh,
 		orderCh:   orderCh,
+		startedAt: time.Now(),
 	}
 
 	e.slots[info.ConditionID] = slot
@@ -361,10 +631,12 @@ func (e *Engine) stopMarketLocked(conditionID string) {
 
 	// Cancel goroutine (maker.Run will cancel its own orders)
 	slot.cancel()
+	if e.paper != nil {
+		e.paper.RemoveMarket(conditionID)
+	}
 
 	// Save position
-	pos := slot.inventory.Snapshot()
-	if err := e.store.SavePosition(conditionID, pos); err != nil {
+	if err := e.store.SaveState(conditionID, slot.inventory.State()); err != nil {
 		e.logger.Error("failed to save position on stop", "market", conditionID, "error", err)
 	}
 
@@ -374,6 +646,9 @@ func (e *Engine) stopMarketLocked(conditionID string) {
 
 	// Clean up risk state
 	e.riskMgr.RemoveMarket(conditionID)
+	if slot.event != nil && slot.event.Detach(conditionID) {
+		delete(e.eventBooks, slot.info.EventID)
+	}
 
 	// Clean up token map
 	e.tokenMapMu.Lock()
@@ -386,6 +661,267 @@ func (e *Engine) stopMarketLocked(conditionID string) {
 	e.logger.Info("market stopped", "slug", slot.info.Slug)
 }
 
+// attachEventLocked adds a starting market's book to its event's view,
+// creating the view for the event's first outcome. It returns nil for
+// markets that aren't part of a known neg-risk event.
+func (e *Engine) attachEventLocked(info types.MarketInfo, book *market.Book) *market.EventBook {
+	if info.EventID == "" {
+		return nil
+	}
+	eb, ok := e.eventBooks[info.EventID]
+	if !ok {
+		event, known := e.events[info.EventID]
+		if !known {
+			return nil
+		}
+		eb = market.NewEventBook(event)
+		e.eventBooks[info.EventID] = eb
+	}
+	eb.Attach(info.ConditionID, book)
+	return eb
+}
+
+// loadFeeRates fills in the market's per-token fee rates. Orders signed
+// with the wrong rate are rejected and quotes priced without it can lose
+// money, so a market whose rates can't be read isn't started (the scanner
+// retries it on its next pass).
+func (e *Engine) loadFeeRates(info *types.MarketInfo) bool {
+	ctx, cancel := context.WithTimeout(e.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, leg := range []struct {
+		tokenID string
+		rate    *int
+	}{
+		{info.YesTokenID, &info.YesFeeRateBps},
+		{info.NoTokenID, &info.NoFeeRateBps},
+	} {
+		rate, err := e.client.GetFeeRate(ctx, leg.tokenID)
+		if err != nil {
+			e.logger.Warn("failed to fetch fee rate, not starting market", "slug", info.Slug, "token", leg.tokenID, "error", err)
+			return false
+		}
+		*leg.rate = rate
+	}
+	if info.YesFeeRateBps > 0 || info.NoFeeRateBps > 0 {
+		e.logger.Info("market charges fees", "slug", info.Slug, "yes_bps", info.YesFeeRateBps, "no_bps", info.NoFeeRateBps)
+	}
+	return true
+}
+
+// reconcileStartup checks a market's restored inventory and resting orders
+// against the exchange (see package reconcile) and returns the report, whose
+// adopted orders and replayed fills the Maker must be told about. If the
+// exchange can't be read, it falls back to cancelling every resting order for
+// the market and returns a nil report. ok is false when the market must not
+// start because its resting orders are in an unknown state.
+func (e *Engine) reconcileStartup(info types.MarketInfo, inv *strategy.Inventory) (report *reconcile.Report, ok bool) {
+	ctx, cancel := context.WithTimeout(e.ctx, 15*time.Second)
+	defer cancel()
+
+	// The paper exchange starts empty and the wallet is irrelevant in
+	// dry-run, so only the blind cancel applies there.
+	if e.paper == nil {
+		report, err := reconcile.Market(ctx, e.client, info, inv, reconcile.Options{
+			MakerAddress: e.auth.FunderAddress().Hex(),
+			AdoptOrders:  e.cfg.Reconcile.AdoptOrders,
+			Tolerance:    e.cfg.Reconcile.Tolerance,
+		})
+		if err == nil {
+			return report, e.applyStartupReport(ctx, info, inv, report)
+		}
+		e.logger.Warn("startup reconciliation failed, cancelling resting orders instead",
+			"slug", info.Slug,
+			"error", err,
+		)
+	}
+
+	resp, err := e.gateway.CancelMarketOrders(ctx, info.ConditionID)
+	e.journalCancels(info.ConditionID, resp, "startup")
+	if err != nil {
+		e.logger.Error("startup order reconciliation failed, skipping market",
+			"slug", info.Slug,
+			"condition_id", info.ConditionID,
+			"error", err,
+		)
+		return nil, false
+	}
+	return nil, true
+}
+
+// applyStartupReport cancels the orders reconciliation disowned, persists a
+// corrected position, and reports any drift to the log and dashboard.
+func (e *Engine) applyStartupReport(ctx context.Context, info types.MarketInfo, inv *strategy.Inventory, report *reconcile.Report) bool {
+	if len(report.ToCancel) > 0 {
+		resp, err := e.gateway.CancelOrders(ctx, report.ToCancel)
+		e.journalCancels(info.ConditionID, resp, "orphan")
+		if err != nil {
+			e.logger.Error("failed to cancel orphaned orders, skipping market",
+				"slug", info.Slug,
+				"orders", len(report.ToCancel),
+				"error", err,
+			)
+			return false
+		}
+	}
+
+	pos := inv.Snapshot()
+	if report.Clean() {
+		e.logger.Info("startup reconciliation clean",
+			"slug", info.Slug,
+			"adopted", len(report.Adopted),
+			"yes_qty", pos.YesQty,
+			"no_qty", pos.NoQty,
+		)
+		return true
+	}
+
+	if len(report.MissedFills) > 0 || len(report.ReversedTrades) > 0 || report.Corrected {
+		if err := e.store.SaveState(info.ConditionID, inv.State()); err != nil {
+			e.logger.Error("failed to save reconciled position", "market", info.ConditionID, "error", err)
+		}
+	}
+	e.logger.Warn("startup reconciliation corrected local state",
+		"slug", info.Slug,
+		"adopted", len(report.Adopted),
+		"cancelled", len(report.ToCancel),
+		"missed_fills", len(report.MissedFills),
+		"reversed_trades", len(report.ReversedTrades),
+		"yes_qty_before", report.YesBefore,
+		"yes_qty", pos.YesQty,
+		"no_qty_before", report.NoBefore,
+		"no_qty", pos.NoQty,
+		"avg_entry_yes", pos.AvgEntryYes,
+		"avg_entry_no", pos.AvgEntryNo,
+		"seeded", len(report.Seeded),
+		"discrepancies", report.Discrepancies,
+	)
+	evt := api.NewReconcileEvent(
+		info.Slug,
+		"startup",
+		len(report.Adopted),
+		len(report.ToCancel),
+		len(report.MissedFills),
+		pos.YesQty,
+		pos.NoQty,
+		report.Discrepancies,
+	)
+	evt.AvgEntryYes, evt.AvgEntryNo = pos.AvgEntryYes, pos.AvgEntryNo
+	evt.Seeded = len(report.Seeded)
+	e.emitDashboardEvent(api.DashboardEvent{
+		Type:      "reconcile",
+		Timestamp: time.Now(),
+		MarketID:  info.ConditionID,
+		Data:      evt,
+	})
+	return true
+}
+
+// reconcileLoop periodically checks every running market against the
+// exchange so fills dropped by the user WS feed still reach inventory.
+func (e *Engine) reconcileLoop() {
+	ticker := time.NewTicker(e.cfg.Reconcile.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			e.reconcileSweep()
+		}
+	}
+}
+
+// reconcileSweep reconciles every running market once.
+func (e *Engine) reconcileSweep() {
+	for _, slot := range e.runningSlots() {
+		// Don't pile more reads onto a rate limit; the client has
+		// already drained the bucket for the Retry-After
+		if err := e.reconcileSlot(slot); errors.Is(err, types.ErrRateLimited) {
+			e.logger.Warn("periodic reconciliation rate limited, skipping remaining markets")
+			return
+		}
+	}
+}
+
+// runningSlots returns a snapshot of the running markets.
+func (e *Engine) runningSlots() []*marketSlot {
+	e.slotsMu.RLock()
+	defer e.slotsMu.RUnlock()
+	slots := make([]*marketSlot, 0, len(e.slots))
+	for _, slot := range e.slots {
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// reconcileSlot diffs one market against the exchange and lets its Maker
+// book missing fills and fix its order tracking. It returns the error that
+// stopped it from reading the exchange, if any.
+func (e *Engine) reconcileSlot(slot *marketSlot) error {
+	ctx, cancel := context.WithTimeout(e.ctx, 15*time.Second)
+	defer cancel()
+
+	since := time.Now().Add(-e.cfg.Reconcile.TradeLookback)
+	view, err := reconcile.Snapshot(ctx, e.client, slot.info, e.auth.FunderAddress().Hex(), since)
+	if err != nil {
+		e.logger.Warn("periodic reconciliation failed", "slug", slot.info.Slug, "error", err)
+		return err
+	}
+	result, err := slot.maker.Sync(ctx, view)
+	if err != nil {
+		e.logger.Warn("periodic reconciliation failed", "slug", slot.info.Slug, "error", err)
+		return err
+	}
+	if !result.Drifted() {
+		return nil
+	}
+
+	// The Maker has already persisted any booked or reversed trades
+	pos := slot.inventory.Snapshot()
+
+	var discrepancies []string
+	if n := len(result.AppliedFills); n > 0 {
+		discrepancies = append(discrepancies, fmt.Sprintf("booked %d fills missed by the WS feed", n))
+	}
+	if n := len(result.ReversedTrades); n > 0 {
+		discrepancies = append(discrepancies, fmt.Sprintf("reversed %d failed trades", n))
+	}
+	if n := len(result.DroppedOrders); n > 0 {
+		discrepancies = append(discrepancies, fmt.Sprintf("dropped %d orders no longer on the exchange", n))
+	}
+	if n := len(result.OrphanOrders); n > 0 {
+		discrepancies = append(discrepancies, fmt.Sprintf("cancelled %d untracked resting orders", n))
+	}
+
+	e.logger.Warn("periodic reconciliation corrected drift",
+		"slug", slot.info.Slug,
+		"missed_fills", len(result.AppliedFills),
+		"reversed_trades", len(result.ReversedTrades),
+		"dropped_orders", len(result.DroppedOrders),
+		"orphan_orders", len(result.OrphanOrders),
+		"yes_qty", pos.YesQty,
+		"no_qty", pos.NoQty,
+	)
+	e.emitDashboardEvent(api.DashboardEvent{
+		Type:      "reconcile",
+		Timestamp: time.Now(),
+		MarketID:  slot.info.ConditionID,
+		Data: api.NewReconcileEvent(
+			slot.info.Slug,
+			"periodic",
+			0,
+			len(result.OrphanOrders),
+			len(result.AppliedFills),
+			pos.YesQty,
+			pos.NoQty,
+			discrepancies,
+		),
+	})
+	return nil
+}
+
 func (e *Engine) handleKillSignal(kill risk.KillSignal) {
 	e.logger.Error("KILL SIGNAL received",
 		"market", kill.MarketID,
@@ -415,8 +951,10 @@ func (e *Engine) handleKillSignal(kill risk.KillSignal) {
 		}
 		// Also cancel-all as safety net
 		cancelCtx, cancelCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		if _, err := e.client.CancelAll(cancelCtx); err != nil {
+		if resp, err := e.gateway.CancelAll(cancelCtx); err != nil {
 			e.logger.Error("failed to cancel all orders", "error", err)
+		} else {
+			e.journalCancels("", resp, "kill_switch")
 		}
 		cancelCancel()
 	} else {
@@ -434,6 +972,10 @@ func (e *Engine) dispatchMarketEvents() {
 			e.routeBookEvent(evt)
 		case evt := <-e.mktFeed.PriceChangeEvents():
 			e.routePriceChange(evt)
+		case evt := <-e.mktFeed.LastTradeEvents():
+			e.routeLastTrade(evt)
+		case evt := <-e.mktFeed.MarketResolvedEvents():
+			e.handleMarketResolved(evt)
 		}
 	}
 }
@@ -454,6 +996,10 @@ func (e *Engine) routeBookEvent(evt types.WSBookEvent) {
 	}
 
 	slot.book.ApplyBookEvent(evt)
+	slot.maker.ObserveBook()
+	if e.paper != nil {
+		e.paper.OnBookUpdate(conditionID)
+	}
 }
 
 func (e *Engine) routePriceChange(evt types.WSPriceChangeEvent) {
@@ -475,11 +1021,92 @@ func (e *Engine) routePriceChange(evt types.WSPriceChangeEvent) {
 		return
 	}
 
-	slot.book.ApplyPriceChange(evt)
+	if resync := slot.book.ApplyPriceChange(evt); len(resync) > 0 {
+		e.resyncBook(slot, resync)
+	}
+	slot.maker.ObserveBook()
+	if e.paper != nil {
+		e.paper.OnBookUpdate(conditionID)
+	}
+}
+
+// routeLastTrade feeds public trade prints to the slot's trade tape
+// detectors and to the paper exchange, which fills virtual orders the print
+// traded through.
+func (e *Engine) routeLastTrade(evt types.WSLastTradePriceEvent) {
+	e.tokenMapMu.RLock()
+	conditionID, ok := e.tokenMap[evt.AssetID]
+	e.tokenMapMu.RUnlock()
+	if ok {
+		e.slotsMu.RLock()
+		slot, ok := e.slots[conditionID]
+		e.slotsMu.RUnlock()
+		if ok {
+			slot.maker.ObserveTrade(evt)
+		}
+	}
+
+	if e.paper != nil {
+		e.paper.OnTrade(evt)
+	}
+}
+
+// handleMarketResolved stops the slot for a market that has resolved. Its
+// orders can no longer fill and the book is about to disappear.
+func (e *Engine) handleMarketResolved(evt types.WSMarketResolvedEvent) {
+	e.slotsMu.Lock()
+	defer e.slotsMu.Unlock()
+
+	e.resolved[evt.Market] = true
+	slot, ok := e.slots[evt.Market]
+	if !ok {
+		return
+	}
+
+	e.logger.Info("market resolved, stopping",
+		"slug", slot.info.Slug,
+		"condition_id", evt.Market,
+		"winning_outcome", evt.WinningOutcome,
+	)
+	e.stopMarketLocked(evt.Market)
+}
+
+// resyncBook refetches REST snapshots for assets whose local ladder drifted
+// from the server's reported top of book. Runs in the background so the
+// market dispatcher is never blocked on HTTP.
+func (e *Engine) resyncBook(slot *marketSlot, assetIDs []string) {
+	e.logger.Warn("book out of sync, resyncing from REST",
+		"slug", slot.info.Slug,
+		"assets", assetIDs,
+	)
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		for _, assetID := range assetIDs {
+			ctx, cancel := context.WithTimeout(e.ctx, 10*time.Second)
+			resp, err := e.client.GetOrderBook(ctx, assetID)
+			cancel()
+			if err != nil {
+				e.logger.Error("book resync failed", "token", assetID, "error", err)
+				slot.book.ClearDesynced(assetID)
+				continue
+			}
+			slot.book.ApplyBookResponse(resp)
+		}
+	}()
 }
 
 // dispatchUserEvents routes WS user events to the correct slot's channels.
+// In dry-run mode, synthetic fills from the paper exchange take the same path.
 func (e *Engine) dispatchUserEvents() {
+	var paperTrades <-chan types.WSTradeEvent
+	var paperOrders <-chan types.WSOrderEvent
+	if e.paper != nil {
+		paperTrades = e.paper.TradeEvents()
+		paperOrders = e.paper.OrderEvents()
+	}
+
 	for {
 		select {
 		case <-e.ctx.Done():
@@ -488,6 +1115,10 @@ func (e *Engine) dispatchUserEvents() {
 			e.routeTrade(trade)
 		case order := <-e.usrFeed.OrderEvents():
 			e.routeOrder(order)
+		case trade := <-paperTrades:
+			e.routeTrade(trade)
+		case order := <-paperOrders:
+			e.routeOrder(order)
 		}
 	}
 }
@@ -522,6 +1153,24 @@ func (e *Engine) routeOrder(order types.WSOrderEvent) {
 	}
 }
 
+// journalCancels records engine-initiated cancels. market is empty for
+// account-wide cancel-all.
+func (e *Engine) journalCancels(market string, resp *types.CancelResponse, reason string) {
+	if e.journal == nil || resp == nil {
+		return
+	}
+	now := time.Now()
+	for _, id := range resp.Canceled {
+		e.journal.Record(types.JournalEntry{
+			Time:    now,
+			Type:    types.JournalCancel,
+			Market:  market,
+			OrderID: id,
+			Reason:  reason,
+		})
+	}
+}
+
 // DashboardEvents returns the dashboard event channel (may be nil).
 func (e *Engine) DashboardEvents() <-chan api.DashboardEvent {
 	return e.dashboardEvents
@@ -546,6 +1195,7 @@ func (e *Engine) GetMarketsSnapshot() []api.MarketStatus {
 		}
 
 		pos := slot.inventory.Snapshot()
+		vol := slot.maker.Volatility()
 		lastUpdated := slot.book.LastUpdated()
 		isStale := slot.book.IsStale(e.cfg.Strategy.StaleBookTimeout)
 
@@ -562,6 +1212,7 @@ func (e *Engine) GetMarketsSnapshot() []api.MarketStatus {
 			AvgEntryNo:    pos.AvgEntryNo,
 			RealizedPnL:   pos.RealizedPnL,
 			UnrealizedPnL: unrealizedPnL,
+			FeesPaid:      pos.FeesPaid,
 			ExposureUSD:   slot.inventory.TotalExposureUSD(mid),
 			Skew:          slot.inventory.NetDelta(),
 			LastUpdated:   pos.LastUpdated,
@@ -579,12 +1230,36 @@ func (e *Engine) GetMarketsSnapshot() []api.MarketStatus {
 			LastUpdated:      lastUpdated,
 			IsStale:          isStale,
 			Position:         posSnapshot,
+			Volatility:       vol.Sigma,
+			RealizedVol:      vol.Realized,
+			VolWarm:          vol.Warm,
 			ReservationPrice: 0, // Will be filled by maker
 			OptimalSpread:    0, // Will be filled by maker
 			TickSize:         parseTickSize(slot.info.TickSize),
 			EndDate:          slot.info.EndDate,
 			Liquidity:        slot.info.Liquidity,
 			Volume24h:        slot.info.Volume24h,
+			Draining:         slot.maker.Draining(),
+			EventSlug:        slot.info.EventSlug,
+		}
+		for _, s := range slot.maker.Markouts() {
+			status.Markouts = append(status.Markouts, api.MarkoutInfo{
+				Side:    string(s.Side),
+				Horizon: s.Horizon.String(),
+				Fills:   s.Count,
+				Average: s.Average(),
+			})
+		}
+		status.Imbalance, _ = slot.maker.Imbalance()
+		status.TapeDefendBid, status.TapeDefendAsk = slot.maker.TapeDefended()
+		status.Microprice, _ = slot.book.Microprice()
+		if slot.event != nil && mid > 0 {
+			status.EventImpliedSum = slot.event.ImpliedSum(slot.info.ConditionID, mid)
+		}
+		if slot.info.RewardsMaxSpread > 0 {
+			rewards := slot.maker.Rewards()
+			status.RewardShare = rewards.Share
+			status.ExpectedRewardDaily = rewards.ExpectedDaily
 		}
 
 		result = append(result, status)
@@ -593,6 +1268,25 @@ func (e *Engine) GetMarketsSnapshot() []api.MarketStatus {
 	return result
 }
 
+// RateLimitStats reports the CLOB client's rate-limit wait metrics for the
+// dashboard.
+func (e *Engine) RateLimitStats() []api.RateLimitStatus {
+	stats := e.client.RateLimitStats()
+	out := make([]api.RateLimitStatus, 0, len(stats))
+	for _, category := range []string{"order", "cancel", "book", "data", "heartbeat"} {
+		s := stats[category]
+		out = append(out, api.RateLimitStatus{
+			Category:    category,
+			Requests:    s.Requests,
+			Delayed:     s.Delayed,
+			TotalWaitMs: float64(s.TotalWait) / float64(time.Millisecond),
+			MaxWaitMs:   float64(s.MaxWait) / float64(time.Millisecond),
+			Throttled:   s.Throttled,
+		})
+	}
+	return out
+}
+
 // GetScanner returns the scanner for dashboard access.
 func (e *Engine) GetScanner() *market.Scanner {
 	return e.scanner
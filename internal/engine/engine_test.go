@@ -0,0 +1,528 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/api"
+	"polymarket-mm/internal/config"
+	"polymarket-mm/internal/exchange"
+	"polymarket-mm/internal/market"
+	"polymarket-mm/internal/risk"
+	"polymarket-mm/internal/store"
+	"polymarket-mm/internal/strategy"
+	"polymarket-mm/pkg/types"
+)
+
+// fakeClient serves the engine's REST reads from canned data.
+type fakeClient struct {
+	mu          sync.Mutex
+	orders      map[string][]types.OpenOrder // conditionID → resting orders
+	trades      map[string][]types.Trade     // conditionID → recent trades
+	ordersErr   map[string]error             // conditionID → GetOpenOrders error
+	ordersCalls map[string]int               // conditionID → GetOpenOrders calls
+	feeCalls    int
+	feeTokens   []string // tokens GetFeeRate was asked about
+	hbErr       error    // returned by PostHeartbeat when set
+	hbIDs       []string // heartbeat IDs PostHeartbeat was sent
+
+	// beforeOrders, if set, runs at the start of GetOpenOrders: after a
+	// reconcile snapshot is stamped, before it is read
+	beforeOrders func(conditionID string)
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		orders:      make(map[string][]types.OpenOrder),
+		trades:      make(map[string][]types.Trade),
+		ordersErr:   make(map[string]error),
+		ordersCalls: make(map[string]int),
+	}
+}
+
+func (c *fakeClient) GetOpenOrders(_ context.Context, conditionID string) ([]types.OpenOrder, error) {
+	if c.beforeOrders != nil {
+		c.beforeOrders(conditionID)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ordersCalls[conditionID]++
+	return c.orders[conditionID], c.ordersErr[conditionID]
+}
+
+func (c *fakeClient) GetTrades(_ context.Context, conditionID string, _ time.Time) ([]types.Trade, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.trades[conditionID], nil
+}
+
+func (c *fakeClient) GetTokenBalance(context.Context, string) (float64, error) { return 0, nil }
+
+func (c *fakeClient) GetOrderBook(_ context.Context, tokenID string) (*types.BookResponse, error) {
+	return &types.BookResponse{AssetID: tokenID}, nil
+}
+
+func (c *fakeClient) GetFeeRate(_ context.Context, tokenID string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.feeCalls++
+	c.feeTokens = append(c.feeTokens, tokenID)
+	return 0, fmt.Errorf("fee rates unavailable")
+}
+
+func (c *fakeClient) PostHeartbeat(_ context.Context, heartbeatID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hbIDs = append(c.hbIDs, heartbeatID)
+	if c.hbErr != nil {
+		return "", c.hbErr
+	}
+	return "hb", nil
+}
+
+// set runs f with the client locked, for tests changing its canned data.
+func (c *fakeClient) set(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f()
+}
+
+func (c *fakeClient) RateLimitStats() map[string]exchange.BucketStats { return nil }
+
+// fakeGateway accepts every order and records cancels.
+type fakeGateway struct {
+	mu       sync.Mutex
+	next     int
+	posted   []types.UserOrder
+	canceled []string
+}
+
+func (g *fakeGateway) PostOrders(_ context.Context, orders []types.UserOrder, _ bool) ([]types.OrderResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]types.OrderResponse, len(orders))
+	for i, o := range orders {
+		g.next++
+		g.posted = append(g.posted, o)
+		out[i] = types.OrderResponse{Success: true, OrderID: fmt.Sprintf("ord-%d", g.next), Status: "live"}
+	}
+	return out, nil
+}
+
+func (g *fakeGateway) CancelOrders(_ context.Context, ids []string) (*types.CancelResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.canceled = append(g.canceled, ids...)
+	return &types.CancelResponse{Canceled: ids}, nil
+}
+
+func (g *fakeGateway) CancelMarketOrders(context.Context, string) (*types.CancelResponse, error) {
+	return &types.CancelResponse{}, nil
+}
+
+func (g *fakeGateway) CancelAll(context.Context) (*types.CancelResponse, error) {
+	return &types.CancelResponse{}, nil
+}
+
+func (g *fakeGateway) postedOrders() []types.UserOrder {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]types.UserOrder(nil), g.posted...)
+}
+
+func (g *fakeGateway) canceledOrders() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.canceled...)
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		Strategy: config.StrategyConfig{
+			Gamma:            0.5,
+			Sigma:            0.2,
+			K:                10.0,
+			T:                0.5,
+			DefaultSpreadBps: 100,
+			OrderSizeUSD:     50,
+			RefreshInterval:  time.Hour,
+			StaleBookTimeout: 30 * time.Second,
+		},
+		Risk: config.RiskConfig{
+			MaxPositionPerMarket: 1000,
+			MaxGlobalExposure:    1000,
+			MaxMarketsActive:     2,
+		},
+		Reconcile: config.ReconcileConfig{TradeLookback: time.Hour},
+	}
+}
+
+// newTestEngine builds an Engine around fakes. Nothing is started; tests
+// drive its methods directly.
+func newTestEngine(t *testing.T, cfg config.Config) (*Engine, *fakeClient, *fakeGateway) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	st, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.Open: %v", err)
+	}
+	auth, err := exchange.NewAuth(config.Config{Wallet: config.WalletConfig{
+		PrivateKey: "0x1111111111111111111111111111111111111111111111111111111111111111",
+		ChainID:    137,
+	}})
+	if err != nil {
+		t.Fatalf("NewAuth: %v", err)
+	}
+
+	client, gw := newFakeClient(), &fakeGateway{}
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Engine{
+		cfg:        cfg,
+		client:     client,
+		gateway:    gw,
+		auth:       auth,
+		mktFeed:    exchange.NewMarketFeed("", logger),
+		usrFeed:    exchange.NewUserFeed("", auth, logger),
+		riskMgr:    risk.NewManager(cfg.Risk, logger),
+		store:      st,
+		logger:     logger,
+		slots:      make(map[string]*marketSlot),
+		resolved:   make(map[string]bool),
+		eventBooks: make(map[string]*market.EventBook),
+		tokenMap:   make(map[string]string),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	t.Cleanup(func() {
+		cancel()
+		e.wg.Wait()
+		st.Close()
+	})
+	return e, client, gw
+}
+
+// addTestSlot registers a running market holding pos. Its Maker runs only
+// if run is set.
+func addTestSlot(t *testing.T, e *Engine, id string, pos strategy.Position, run bool) *marketSlot {
+	t.Helper()
+	info := types.MarketInfo{
+		ConditionID:  id,
+		Slug:         id,
+		YesTokenID:   id + "-yes",
+		NoTokenID:    id + "-no",
+		TickSize:     types.Tick001,
+		MinOrderSize: 1,
+	}
+	book := market.NewBook(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	inv := strategy.NewInventory(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	inv.SetPosition(pos)
+	maker := strategy.NewMaker(e.makerConfig(), info, book, inv, e.gateway, e.riskMgr, e.logger, nil)
+
+	ctx, cancel := context.WithCancel(e.ctx)
+	slot := &marketSlot{
+		info:      info,
+		book:      book,
+		inventory: inv,
+		maker:     maker,
+		cancel:    cancel,
+		done:      ctx.Done(),
+		tradeCh:   make(chan types.WSTradeEvent, 64),
+		orderCh:   make(chan types.WSOrderEvent, 64),
+		startedAt: time.Now().Add(-time.Hour),
+	}
+
+	e.slotsMu.Lock()
+	e.slots[id] = slot
+	e.slotsMu.Unlock()
+	e.tokenMapMu.Lock()
+	e.tokenMap[info.YesTokenID] = id
+	e.tokenMap[info.NoTokenID] = id
+	e.tokenMapMu.Unlock()
+
+	if run {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			maker.Run(ctx, slot.tradeCh, slot.orderCh)
+		}()
+	}
+	return slot
+}
+
+// withBook gives a slot a two-sided YES book so its Maker can quote.
+func withBook(slot *marketSlot) *marketSlot {
+	slot.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: slot.info.YesTokenID,
+		Buys:    []types.PriceLevel{{Price: "0.45", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.55", Size: "100"}},
+	})
+	return slot
+}
+
+// eventually polls cond until it holds or a couple of seconds pass.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting: %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (e *Engine) running(id string) bool {
+	e.slotsMu.RLock()
+	defer e.slotsMu.RUnlock()
+	_, ok := e.slots[id]
+	return ok
+}
+
+func candidate(id string, score float64) types.MarketAllocation {
+	return types.MarketAllocation{
+		Market: types.MarketInfo{ConditionID: id, Slug: id, YesTokenID: id + "-yes", NoTokenID: id + "-no"},
+		Score:  score,
+	}
+}
+
+func TestReconcileMarketsPinsInventoryUntilReleased(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig()
+	cfg.Strategy.DrainTimeout = time.Minute
+	cfg.Risk.MaxMarketsActive = 1
+	e, client, _ := newTestEngine(t, cfg)
+
+	long := addTestSlot(t, e, "long", strategy.Position{YesQty: 20, AvgEntryYes: 0.5}, false)
+	flat := addTestSlot(t, e, "flat", strategy.Position{}, false)
+
+	// Neither is wanted any more: the flat market winds down, the one
+	// holding inventory keeps quoting and its slot
+	e.reconcileMarkets(market.ScanResult{Markets: []types.MarketAllocation{candidate("new", 10)}})
+	if long.maker.Draining() || !e.running("long") {
+		t.Error("market holding inventory was dropped")
+	}
+	if !flat.maker.Draining() {
+		t.Error("flat deselected market should drain")
+	}
+	if client.feeCalls != 0 || e.running("new") {
+		t.Error("a market started while inventory filled the only slot")
+	}
+
+	// A drain that ran out of time with inventory left still takes its slot
+	flat.inventory.SetPosition(strategy.Position{NoQty: 5, AvgEntryNo: 0.5})
+	if err := e.ReleaseMarket("long"); err != nil {
+		t.Fatalf("ReleaseMarket: %v", err)
+	}
+	e.reconcileMarkets(market.ScanResult{Markets: []types.MarketAllocation{candidate("new", 10)}})
+	if e.running("long") {
+		t.Error("released market should stop once deselected")
+	}
+	if !e.running("flat") || client.feeCalls != 0 {
+		t.Error("a draining market with inventory lost its slot")
+	}
+
+	// Releasing a draining market stops it outright
+	if err := e.ReleaseMarket("flat"); err != nil {
+		t.Fatalf("ReleaseMarket: %v", err)
+	}
+	if e.running("flat") {
+		t.Error("released draining market still running")
+	}
+}
+
+func TestDrainStopsMarketOnlyOnceFlat(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig()
+	cfg.Strategy.RefreshInterval = 10 * time.Millisecond
+	cfg.Strategy.DrainTimeout = 50 * time.Millisecond
+	cfg.Strategy.DrainFlatten = true
+	e, _, gw := newTestEngine(t, cfg)
+	slot := withBook(addTestSlot(t, e, "long", strategy.Position{YesQty: 20, AvgEntryYes: 0.5}, true))
+
+	e.slotsMu.Lock()
+	e.drainMarketLocked("long")
+	e.slotsMu.Unlock()
+
+	// At the deadline what's left is sold into the bid, but the market
+	// stays up until those fills land
+	eventually(t, "flatten at the drain deadline", func() bool {
+		for _, o := range gw.postedOrders() {
+			if o.OrderType == types.OrderTypeFAK && o.Side == types.SELL && o.Size == 20 {
+				return true
+			}
+		}
+		return false
+	})
+	time.Sleep(50 * time.Millisecond)
+	if !e.running("long") {
+		t.Fatal("market stopped at its drain deadline while holding inventory")
+	}
+
+	slot.inventory.SetPosition(strategy.Position{})
+	eventually(t, "flat market to stop", func() bool { return !e.running("long") })
+}
+
+func TestShutdownDrainWaitsForInventory(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig()
+	cfg.Strategy.RefreshInterval = 10 * time.Millisecond
+	cfg.Strategy.DrainTimeout = 20 * time.Millisecond
+	e, _, _ := newTestEngine(t, cfg)
+	withBook(addTestSlot(t, e, "long", strategy.Position{YesQty: 20, AvgEntryYes: 0.5}, true))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	e.Drain(ctx)
+	if time.Since(start) < 200*time.Millisecond {
+		t.Error("shutdown drain returned while a market still held inventory")
+	}
+	if !e.running("long") {
+		t.Error("market holding inventory was stopped by its drain deadline")
+	}
+}
+
+// reconcileEvents returns the reconcile events the engine has emitted.
+func reconcileEvents(ch chan api.DashboardEvent) []api.ReconcileEvent {
+	var out []api.ReconcileEvent
+	for {
+		select {
+		case evt := <-ch:
+			if re, ok := evt.Data.(api.ReconcileEvent); ok {
+				out = append(out, re)
+			}
+		default:
+			return out
+		}
+	}
+}
+
+func TestReconcileSweepStopsOnRateLimit(t *testing.T) {
+	t.Parallel()
+	e, client, _ := newTestEngine(t, testConfig())
+	addTestSlot(t, e, "a", strategy.Position{}, true)
+	addTestSlot(t, e, "b", strategy.Position{}, true)
+
+	// Whichever market goes first is rate limited; the other isn't read
+	limited := fmt.Errorf("GET /data/orders: %w", types.ErrRateLimited)
+	client.set(func() {
+		client.ordersErr["a"] = limited
+		client.ordersErr["b"] = limited
+	})
+	e.reconcileSweep()
+	client.set(func() {
+		if n := client.ordersCalls["a"] + client.ordersCalls["b"]; n != 1 {
+			t.Errorf("read %d markets after a rate limit, want 1", n)
+		}
+	})
+
+	// Other failures don't stop the sweep
+	client.set(func() {
+		client.ordersErr["a"] = fmt.Errorf("connection reset")
+		client.ordersErr["b"] = fmt.Errorf("connection reset")
+	})
+	e.reconcileSweep()
+	client.set(func() {
+		if n := client.ordersCalls["a"] + client.ordersCalls["b"]; n != 3 {
+			t.Errorf("read %d markets in total, want both read on the second sweep", n-1)
+		}
+	})
+}
+
+func TestReconcileSweepCancelsOrphans(t *testing.T) {
+	t.Parallel()
+	e, client, gw := newTestEngine(t, testConfig())
+	e.dashboardEvents = make(chan api.DashboardEvent, 16)
+	addTestSlot(t, e, "m1", strategy.Position{}, true)
+
+	// A resting order the Maker isn't tracking, and one in another market
+	client.set(func() {
+		client.orders["m1"] = []types.OpenOrder{
+			{ID: "stray", Market: "m1", AssetID: "m1-yes", Side: "BUY", Price: "0.40", OriginalSize: "10"},
+			{ID: "elsewhere", Market: "m1", AssetID: "other-yes", Side: "BUY", Price: "0.40", OriginalSize: "10"},
+		}
+	})
+	e.reconcileSweep()
+	if got := gw.canceledOrders(); len(got) != 1 || got[0] != "stray" {
+		t.Errorf("cancelled %v, want only the orphan", got)
+	}
+	events := reconcileEvents(e.dashboardEvents)
+	if len(events) != 1 || events[0].Trigger != "periodic" || events[0].Cancelled != 1 {
+		t.Errorf("reconcile events = %+v, want one periodic event with the orphan cancelled", events)
+	}
+}
+
+func TestReconcileSweepKeepsOrdersPlacedAfterSnapshot(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig()
+	cfg.Strategy.RefreshInterval = 10 * time.Millisecond
+	e, client, gw := newTestEngine(t, cfg)
+	e.dashboardEvents = make(chan api.DashboardEvent, 16)
+	slot := addTestSlot(t, e, "m1", strategy.Position{}, true)
+
+	// The Maker quotes only once it has a book, which arrives after the
+	// snapshot is stamped; the exchange's answer predates those orders
+	client.beforeOrders = func(string) {
+		withBook(slot)
+		eventually(t, "quotes to post", func() bool { return len(gw.postedOrders()) > 0 })
+	}
+	e.reconcileSweep()
+	if events := reconcileEvents(e.dashboardEvents); len(events) != 0 {
+		t.Fatalf("reconcile events = %+v; orders placed after the snapshot were dropped", events)
+	}
+
+	// A later snapshot that still doesn't list them drops them
+	client.beforeOrders = nil
+	e.reconcileSweep()
+	events := reconcileEvents(e.dashboardEvents)
+	if len(events) != 1 || len(events[0].Discrepancies) == 0 {
+		t.Fatalf("reconcile events = %+v, want the missing orders dropped", events)
+	}
+	if got := gw.canceledOrders(); len(got) != 0 {
+		t.Errorf("cancelled %v; orders missing from the exchange need no cancel", got)
+	}
+}
+
+func TestResolvedMarketStopsAndIsNotRestarted(t *testing.T) {
+	t.Parallel()
+	e, client, _ := newTestEngine(t, testConfig())
+	slot := addTestSlot(t, e, "done", strategy.Position{YesQty: 20, AvgEntryYes: 0.5}, true)
+
+	// Resolution stops the market even though it holds inventory
+	e.handleMarketResolved(types.WSMarketResolvedEvent{Market: "done", WinningOutcome: "Yes"})
+	if e.running("done") {
+		t.Fatal("resolved market still running")
+	}
+	select {
+	case <-slot.done:
+	default:
+		t.Error("resolved market's Maker was not cancelled")
+	}
+	e.slotsMu.RLock()
+	resolved := e.resolved["done"]
+	e.slotsMu.RUnlock()
+	if !resolved {
+		t.Error("resolved market not recorded")
+	}
+	if state, err := e.store.LoadState("done"); err != nil || state == nil || state.YesQty != 20 {
+		t.Errorf("saved state = %+v, %v; want the position kept", state, err)
+	}
+
+	// The scanner still ranks it first, but only the other candidate is tried
+	e.reconcileMarkets(market.ScanResult{Markets: []types.MarketAllocation{candidate("done", 10), candidate("fresh", 5)}})
+	client.set(func() {
+		for _, token := range client.feeTokens {
+			if token == "done-yes" || token == "done-no" {
+				t.Errorf("resolved market restarted (fee lookup for %s)", token)
+			}
+		}
+		if len(client.feeTokens) == 0 {
+			t.Error("unresolved candidate was not started")
+		}
+	})
+}
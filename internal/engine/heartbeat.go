@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/internal/exchange"
+	"polymarket-mm/pkg/types"
+)
+
+// heartbeatTimeout is how long the exchange waits for the next heartbeat
+// before cancelling all of our orders.
+const heartbeatTimeout = 10 * time.Second
+
+// armDeadManSwitch makes sure our quotes come off the book if the bot stops
+// refreshing them. It runs before any market starts quoting. In "auto" mode
+// the first heartbeat is sent synchronously; if it fails the switch can't be
+// trusted, so quotes fall back to GTD. It returns the heartbeat ID to chain
+// from and whether heartbeatLoop should run.
+func (e *Engine) armDeadManSwitch() (heartbeatID string, ok bool) {
+	if e.paper != nil {
+		return "", false
+	}
+
+	switch e.cfg.Heartbeat.Mode {
+	case "gtd":
+		e.useGTDQuotes("heartbeat.mode is gtd")
+	case "auto":
+		ctx, cancel := context.WithTimeout(e.ctx, heartbeatTimeout)
+		defer cancel()
+		id, err := e.client.PostHeartbeat(ctx, "")
+		if err != nil {
+			reason := "heartbeat failed at startup"
+			if errors.Is(err, exchange.ErrHeartbeatUnsupported) {
+				reason = "heartbeat endpoint not available"
+			}
+			e.logger.Warn("dead-man switch not armed", "error", err)
+			e.useGTDQuotes(reason)
+			return "", false
+		}
+		e.logger.Info("dead-man switch armed", "interval", e.cfg.Heartbeat.Interval)
+		return id, true
+	}
+	return "", false
+}
+
+// useGTDQuotes switches every Maker started from now on to GTD quotes.
+func (e *Engine) useGTDQuotes(reason string) {
+	if !e.gtdQuotes && e.cfg.Strategy.QuoteOrderType != string(types.OrderTypeGTD) {
+		e.logger.Warn("quoting GTD so orders lapse without renewal", "reason", reason)
+	}
+	e.gtdQuotes = true
+}
+
+// makerConfig is the strategy config a new Maker starts with: the
+// configured one, switched to GTD quotes if the dead-man switch is off.
+func (e *Engine) makerConfig() config.StrategyConfig {
+	cfg := e.cfg.Strategy
+	if e.gtdQuotes {
+		cfg.QuoteOrderType = string(types.OrderTypeGTD)
+	}
+	return cfg
+}
+
+// heartbeatState is what heartbeatLoop carries from one beat to the next.
+type heartbeatState struct {
+	id     string    // heartbeat chain to extend; empty starts a new one
+	lastOK time.Time // last accepted heartbeat
+	lapsed bool      // exchange has likely cancelled all our orders
+
+	// unreconciled holds markets whose orders may have been cancelled by a
+	// lapse and which haven't been reconciled since
+	unreconciled map[string]bool
+}
+
+// heartbeatLoop keeps the exchange's dead-man switch from firing.
+func (e *Engine) heartbeatLoop(heartbeatID string) {
+	ticker := time.NewTicker(e.cfg.Heartbeat.Interval)
+	defer ticker.Stop()
+
+	hb := &heartbeatState{id: heartbeatID, lastOK: time.Now()}
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			e.heartbeat(hb, time.Now())
+		}
+	}
+}
+
+// heartbeat sends one heartbeat. If heartbeats lapse for longer than the
+// exchange's timeout, our orders have been cancelled on the exchange; once
+// heartbeats resume, every running market is reconciled so its Maker stops
+// tracking the dead orders and requotes. A market whose reconcile fails is
+// retried on each following heartbeat until one succeeds.
+func (e *Engine) heartbeat(hb *heartbeatState, now time.Time) {
+	ctx, cancel := context.WithTimeout(e.ctx, e.cfg.Heartbeat.Interval)
+	id, err := e.client.PostHeartbeat(ctx, hb.id)
+	cancel()
+	if err != nil {
+		if e.ctx.Err() != nil {
+			return
+		}
+		// Start a new chain next time in case ours was rejected
+		hb.id = ""
+		e.logger.Warn("heartbeat failed", "error", err, "since_last_ok", now.Sub(hb.lastOK))
+		if !hb.lapsed && now.Sub(hb.lastOK) > heartbeatTimeout {
+			hb.lapsed = true
+			e.logger.Error("heartbeat lapsed, exchange has likely cancelled all orders")
+		}
+		return
+	}
+
+	hb.id = id
+	hb.lastOK = now
+	if hb.lapsed {
+		hb.lapsed = false
+		e.logger.Warn("heartbeat restored, reconciling markets")
+		if hb.unreconciled == nil {
+			hb.unreconciled = make(map[string]bool)
+		}
+		for _, slot := range e.runningSlots() {
+			hb.unreconciled[slot.info.ConditionID] = true
+		}
+	}
+	e.reconcileAfterLapse(hb)
+}
+
+// reconcileAfterLapse reconciles the markets a heartbeat lapse left
+// unreconciled, keeping those that fail for the next heartbeat.
+func (e *Engine) reconcileAfterLapse(hb *heartbeatState) {
+	if len(hb.unreconciled) == 0 {
+		return
+	}
+	slots := e.runningSlots()
+	running := make(map[string]bool, len(slots))
+	for _, slot := range slots {
+		running[slot.info.ConditionID] = true
+	}
+	// Markets stopped in the meantime no longer need it
+	for id := range hb.unreconciled {
+		if !running[id] {
+			delete(hb.unreconciled, id)
+		}
+	}
+
+	for _, slot := range slots {
+		id := slot.info.ConditionID
+		if !hb.unreconciled[id] {
+			continue
+		}
+		err := e.reconcileSlot(slot)
+		if err == nil {
+			delete(hb.unreconciled, id)
+			continue
+		}
+		e.logger.Error("market not reconciled after heartbeat lapse, retrying on next heartbeat",
+			"slug", slot.info.Slug, "error", err)
+		if errors.Is(err, types.ErrRateLimited) {
+			break
+		}
+	}
+}
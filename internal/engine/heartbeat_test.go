@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/exchange"
+	"polymarket-mm/internal/strategy"
+	"polymarket-mm/pkg/types"
+)
+
+func TestArmDeadManSwitchFallsBackToGTD(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig()
+	cfg.Strategy.RefreshInterval = 10 * time.Millisecond
+	cfg.Heartbeat.Mode = "auto"
+	cfg.Heartbeat.Interval = 5 * time.Second
+
+	// An armed switch keeps the configured quotes
+	e, _, _ := newTestEngine(t, cfg)
+	if id, ok := e.armDeadManSwitch(); !ok || id != "hb" {
+		t.Fatalf("armDeadManSwitch = %q, %v; want the chain armed", id, ok)
+	}
+	if got := e.makerConfig().QuoteOrderType; got == string(types.OrderTypeGTD) {
+		t.Error("armed switch should not force GTD quotes")
+	}
+
+	// One that can't be armed switches new Makers to GTD without
+	// rewriting the configuration
+	e, client, gw := newTestEngine(t, cfg)
+	client.set(func() { client.hbErr = exchange.ErrHeartbeatUnsupported })
+	if _, ok := e.armDeadManSwitch(); ok {
+		t.Fatal("heartbeat loop started without a working heartbeat")
+	}
+	if e.cfg.Strategy.QuoteOrderType != "" {
+		t.Errorf("config quote order type rewritten to %q", e.cfg.Strategy.QuoteOrderType)
+	}
+	withBook(addTestSlot(t, e, "m1", strategy.Position{}, true))
+	eventually(t, "quotes to post", func() bool { return len(gw.postedOrders()) > 0 })
+	for _, o := range gw.postedOrders() {
+		if o.OrderType != types.OrderTypeGTD || o.Expiration == 0 {
+			t.Errorf("posted %s order expiring at %d, want GTD", o.OrderType, o.Expiration)
+		}
+	}
+
+	// Mode gtd never sends a heartbeat
+	cfg.Heartbeat.Mode = "gtd"
+	e, client, _ = newTestEngine(t, cfg)
+	if _, ok := e.armDeadManSwitch(); ok || len(client.hbIDs) != 0 {
+		t.Errorf("gtd mode armed the switch (heartbeats %v)", client.hbIDs)
+	}
+	if e.makerConfig().QuoteOrderType != string(types.OrderTypeGTD) {
+		t.Error("gtd mode should quote GTD")
+	}
+}
+
+func TestHeartbeatLapseReconcilesUntilEveryMarketSucceeds(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig()
+	cfg.Heartbeat.Mode = "auto"
+	cfg.Heartbeat.Interval = 5 * time.Second
+	e, client, _ := newTestEngine(t, cfg)
+	addTestSlot(t, e, "ok", strategy.Position{}, true)
+	addTestSlot(t, e, "flaky", strategy.Position{}, true)
+
+	start := time.Now()
+	hb := &heartbeatState{id: "hb", lastOK: start}
+
+	// Failures within the exchange's timeout aren't a lapse yet
+	client.set(func() { client.hbErr = errors.New("connection reset") })
+	e.heartbeat(hb, start.Add(5*time.Second))
+	if hb.lapsed || hb.id != "" {
+		t.Fatalf("after one failure: lapsed=%v id=%q; want a new chain, no lapse", hb.lapsed, hb.id)
+	}
+	e.heartbeat(hb, start.Add(11*time.Second))
+	if !hb.lapsed {
+		t.Fatal("heartbeats failing past the timeout should count as a lapse")
+	}
+	if calls := client.ordersCalls["ok"]; calls != 0 {
+		t.Fatalf("reconciled %d times during the lapse", calls)
+	}
+
+	// Heartbeats resume, but one market can't be read yet
+	client.set(func() {
+		client.hbErr = nil
+		client.ordersErr["flaky"] = errors.New("timeout")
+	})
+	e.heartbeat(hb, start.Add(12*time.Second))
+	if hb.lapsed || hb.id != "hb" {
+		t.Fatalf("after recovery: lapsed=%v id=%q", hb.lapsed, hb.id)
+	}
+	if !hb.unreconciled["flaky"] || hb.unreconciled["ok"] {
+		t.Fatalf("unreconciled = %v, want only the failed market", hb.unreconciled)
+	}
+
+	// It is retried on every heartbeat until its reconcile succeeds
+	e.heartbeat(hb, start.Add(17*time.Second))
+	client.set(func() { delete(client.ordersErr, "flaky") })
+	e.heartbeat(hb, start.Add(22*time.Second))
+	if len(hb.unreconciled) != 0 {
+		t.Errorf("unreconciled = %v after a successful retry", hb.unreconciled)
+	}
+	client.set(func() {
+		if got := client.ordersCalls["flaky"]; got != 3 {
+			t.Errorf("flaky market read %d times, want 3", got)
+		}
+		if got := client.ordersCalls["ok"]; got != 1 {
+			t.Errorf("reconciled market read %d times, want once", got)
+		}
+	})
+}
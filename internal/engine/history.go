@@ -0,0 +1,30 @@
+package engine
+
+// minCaptureVolume is the USDC a market must have traded before our spread
+// capture there is reported to the scanner.
+const minCaptureVolume = 1.0
+
+// SpreadCapture implements market.MarketHistory: realized PnL (net of fees)
+// per USD traded in a market, from the live inventory if the market is
+// running and from the store otherwise.
+func (e *Engine) SpreadCapture(conditionID string) (float64, bool) {
+	e.slotsMu.RLock()
+	slot, ok := e.slots[conditionID]
+	e.slotsMu.RUnlock()
+
+	var realized, volume float64
+	if ok {
+		pos := slot.inventory.Snapshot()
+		realized, volume = pos.RealizedPnL, pos.Volume
+	} else {
+		state, err := e.store.LoadState(conditionID)
+		if err != nil || state == nil {
+			return 0, false
+		}
+		realized, volume = state.RealizedPnL, state.Volume
+	}
+	if volume < minCaptureVolume {
+		return 0, false
+	}
+	return realized / volume, true
+}
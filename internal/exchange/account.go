@@ -0,0 +1,150 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+// endCursor marks the last page of a paginated CLOB response.
+const endCursor = "LTE="
+
+// tokenDecimals is the fixed-point precision of conditional token balances.
+const tokenDecimals = 1e6
+
+// pagedResponse is the envelope of the paginated /data endpoints.
+type pagedResponse struct {
+	Data       json.RawMessage `json:"data"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+// GetOpenOrders returns our resting orders for one market (all markets if
+// conditionID is empty), following pagination to the end.
+func (c *Client) GetOpenOrders(ctx context.Context, conditionID string) ([]types.OpenOrder, error) {
+	query := map[string]string{}
+	if conditionID != "" {
+		query["market"] = conditionID
+	}
+
+	var orders []types.OpenOrder
+	err := c.getPaged(ctx, "/data/orders", query, func(page json.RawMessage) error {
+		var batch []types.OpenOrder
+		if err := json.Unmarshal(page, &batch); err != nil {
+			return err
+		}
+		orders = append(orders, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get open orders: %w", err)
+	}
+	return orders, nil
+}
+
+// GetTrades returns our trades for one market matched after the given time
+// (all history if zero), following pagination to the end.
+func (c *Client) GetTrades(ctx context.Context, conditionID string, after time.Time) ([]types.Trade, error) {
+	query := map[string]string{}
+	if conditionID != "" {
+		query["market"] = conditionID
+	}
+	if !after.IsZero() {
+		query["after"] = strconv.FormatInt(after.Unix(), 10)
+	}
+
+	var trades []types.Trade
+	err := c.getPaged(ctx, "/data/trades", query, func(page json.RawMessage) error {
+		var batch []types.Trade
+		if err := json.Unmarshal(page, &batch); err != nil {
+			return err
+		}
+		trades = append(trades, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get trades: %w", err)
+	}
+	return trades, nil
+}
+
+// GetTokenBalance returns the funder wallet's balance of a conditional
+// token, in whole tokens.
+func (c *Client) GetTokenBalance(ctx context.Context, tokenID string) (float64, error) {
+	if err := c.rl.Data.Wait(ctx); err != nil {
+		return 0, err
+	}
+	headers, err := c.auth.L2Headers("GET", "/balance-allowance", "")
+	if err != nil {
+		return 0, fmt.Errorf("l2 headers: %w", err)
+	}
+
+	var result struct {
+		Balance string `json:"balance"`
+	}
+	resp, err := c.http.R().
+		SetContext(ctx).
+		SetHeaders(headers).
+		SetQueryParams(map[string]string{
+			"asset_type":     "CONDITIONAL",
+			"token_id":       tokenID,
+			"signature_type": strconv.Itoa(int(c.auth.sigType)),
+		}).
+		SetResult(&result).
+		Get("/balance-allowance")
+	if err != nil {
+		return 0, fmt.Errorf("get balance: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return 0, c.statusError("get balance", c.rl.Data, resp)
+	}
+
+	raw, err := strconv.ParseFloat(result.Balance, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse balance %q: %w", result.Balance, err)
+	}
+	return raw / tokenDecimals, nil
+}
+
+// getPaged walks an L2-authenticated paginated endpoint, handing each
+// page's data array to fn.
+func (c *Client) getPaged(ctx context.Context, path string, query map[string]string, fn func(json.RawMessage) error) error {
+	cursor := ""
+	for {
+		if err := c.rl.Data.Wait(ctx); err != nil {
+			return err
+		}
+		headers, err := c.auth.L2Headers("GET", path, "")
+		if err != nil {
+			return fmt.Errorf("l2 headers: %w", err)
+		}
+
+		req := c.http.R().SetContext(ctx).SetHeaders(headers).SetQueryParams(query)
+		if cursor != "" {
+			req.SetQueryParam("next_cursor", cursor)
+		}
+
+		var page pagedResponse
+		resp, err := req.SetResult(&page).Get(path)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode() != http.StatusOK {
+			return c.statusError("get "+path, c.rl.Data, resp)
+		}
+		if len(page.Data) > 0 {
+			if err := fn(page.Data); err != nil {
+				return fmt.Errorf("decode page: %w", err)
+			}
+		}
+
+		if page.NextCursor == "" || page.NextCursor == endCursor || page.NextCursor == cursor {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
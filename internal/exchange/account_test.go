@@ -0,0 +1,129 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	return &Client{
+		http:   resty.New().SetBaseURL(srv.URL),
+		auth:   &Auth{creds: Credentials{ApiKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}},
+		rl:     NewRateLimiter(),
+		logger: logger,
+	}
+}
+
+func TestGetOpenOrdersFollowsCursor(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/orders" || r.URL.Query().Get("market") != "cond-1" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("POLY_API_KEY") != "key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("next_cursor") {
+		case "":
+			fmt.Fprint(w, `{"data":[{"id":"o1","asset_id":"yes","side":"BUY","price":"0.45","original_size":"10","size_matched":"0"}],"next_cursor":"MQ=="}`)
+		case "MQ==":
+			fmt.Fprint(w, `{"data":[{"id":"o2","asset_id":"no","side":"BUY","price":"0.50","original_size":"5","size_matched":"1"}],"next_cursor":"LTE="}`)
+		default:
+			http.Error(w, "unexpected cursor", http.StatusBadRequest)
+		}
+	})
+
+	orders, err := c.GetOpenOrders(context.Background(), "cond-1")
+	if err != nil {
+		t.Fatalf("GetOpenOrders: %v", err)
+	}
+	if len(orders) != 2 || orders[0].ID != "o1" || orders[1].ID != "o2" || orders[1].SizeMatched != "1" {
+		t.Errorf("orders = %+v", orders)
+	}
+}
+
+func TestGetTradesAndBalance(t *testing.T) {
+	t.Parallel()
+	after := time.Unix(1_700_000_000, 0)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/trades":
+			if r.URL.Query().Get("after") != "1700000000" {
+				http.Error(w, "missing after", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"id":"t1","status":"CONFIRMED","trader_side":"MAKER","maker_orders":[{"order_id":"o1","matched_amount":"4","price":"0.45","asset_id":"yes","side":"BUY"}]}],"next_cursor":"LTE="}`)
+		case "/balance-allowance":
+			if r.URL.Query().Get("asset_type") != "CONDITIONAL" || r.URL.Query().Get("token_id") != "yes" {
+				http.Error(w, "bad query", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"balance":"12500000"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	trades, err := c.GetTrades(context.Background(), "cond-1", after)
+	if err != nil {
+		t.Fatalf("GetTrades: %v", err)
+	}
+	if len(trades) != 1 || trades[0].TraderSide != "MAKER" || trades[0].MakerOrders[0].MatchedAmount != "4" {
+		t.Errorf("trades = %+v", trades)
+	}
+
+	bal, err := c.GetTokenBalance(context.Background(), "yes")
+	if err != nil {
+		t.Fatalf("GetTokenBalance: %v", err)
+	}
+	if bal != 12.5 {
+		t.Errorf("balance = %v, want 12.5", bal)
+	}
+}
+
+func TestGetOpenOrdersErrorStatus(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusUnauthorized)
+	})
+	if _, err := c.GetOpenOrders(context.Background(), ""); err == nil {
+		t.Error("expected error on 401")
+	}
+}
+
+func TestGetFeeRate(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fee-rate" || r.URL.Query().Get("token_id") != "yes" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"base_fee":200}`)
+	})
+
+	rate, err := c.GetFeeRate(context.Background(), "yes")
+	if err != nil {
+		t.Fatalf("GetFeeRate: %v", err)
+	}
+	if rate != 200 {
+		t.Errorf("rate = %d, want 200", rate)
+	}
+}
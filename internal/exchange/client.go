@@ -2,14 +2,23 @@
 //
 // The REST client (Client) talks to the Polymarket CLOB API for order management:
 //   - GetOrderBook:       GET  /book               — fetch L2 book for a token
+//   - GetFeeRate:         GET  /fee-rate           — a token's fee rate in bps
 //   - PostOrders:         POST /orders              — batch-place up to 15 signed orders
+//     (GTC/GTD, optionally post-only, or FOK/FAK; rejections are typed, see errors.go)
 //   - CancelOrders:       DELETE /orders            — cancel specific orders by ID
 //   - CancelAll:          DELETE /cancel-all         — emergency cancel everything
 //   - CancelMarketOrders: DELETE /cancel-market-orders — cancel one market's orders
 //   - DeriveAPIKey:       GET  /auth/derive-api-key — bootstrap L2 creds from L1 wallet
 //
+// Account reads used for reconciliation live in account.go:
+//   - GetOpenOrders:      GET  /data/orders        — our resting orders (paginated)
+//   - GetTrades:          GET  /data/trades        — our trade history (paginated)
+//   - GetTokenBalance:    GET  /balance-allowance  — conditional token balance
+//
 // Every request is rate-limited via per-category TokenBuckets, automatically retried
 // on 5xx errors, and authenticated with L2 HMAC headers (except book reads).
+// Failed requests return an *APIError matching a types.Err* sentinel; a 429
+// drains the request's bucket for the server's Retry-After.
 package exchange
 
 import (
@@ -64,12 +73,17 @@ func NewClient(cfg config.Config, auth *Auth, logger *slog.Logger) *Client {
 	return &Client{
 		http:   httpClient,
 		auth:   auth,
-		rl:     NewRateLimiter(),
+		rl:     NewRateLimiterFromConfig(cfg.API.RateLimits),
 		dryRun: cfg.DryRun,
 		logger: logger,
 	}
 }
 
+// RateLimitStats reports how long requests have waited on each bucket.
+func (c *Client) RateLimitStats() map[string]BucketStats {
+	return c.rl.Stats()
+}
+
 // GetOrderBook fetches the order book for a single token.
 func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*types.BookResponse, error) {
 	if err := c.rl.Book.Wait(ctx); err != nil {
@@ -86,17 +100,55 @@ func (c *Client) GetOrderBook(ctx context.Context, tokenID string) (*types.BookR
 		return nil, fmt.Errorf("get book: %w", err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get book: status %d: %s", resp.StatusCode(), resp.String())
+		return nil, c.statusError("get book", c.rl.Book, resp)
 	}
 	return &result, nil
 }
 
+// GetFeeRate fetches a token's fee rate in basis points. Signed orders must
+// carry it or the CLOB rejects them.
+func (c *Client) GetFeeRate(ctx context.Context, tokenID string) (int, error) {
+	if err := c.rl.Book.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		BaseFee int `json:"base_fee"`
+	}
+	resp, err := c.http.R().
+		SetContext(ctx).
+		SetQueryParam("token_id", tokenID).
+		SetResult(&result).
+		Get("/fee-rate")
+	if err != nil {
+		return 0, fmt.Errorf("get fee rate: %w", err)
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return 0, c.statusError("get fee rate", c.rl.Book, resp)
+	}
+	return result.BaseFee, nil
+}
+
 // buildOrderPayload converts a high-level UserOrder into the on-chain
 // SignedOrder + metadata the REST API expects. It converts human-readable
 // price/size to big.Int maker/taker amounts at the market's tick precision,
 // sets the maker to the funder wallet (proxy), the signer to the EOA,
 // and the taker to the zero address (open order, anyone can fill).
+// Order type combinations the CLOB would reject are refused before signing.
 func (c *Client) buildOrderPayload(order types.UserOrder) (types.OrderPayload, error) {
+	orderType := order.OrderType
+	if orderType == "" {
+		orderType = types.OrderTypeGTC
+	}
+	switch {
+	case order.PostOnly && !orderType.Rests():
+		return types.OrderPayload{}, fmt.Errorf("post-only is not allowed on %s orders", orderType)
+	case orderType == types.OrderTypeGTD && order.Expiration <= 0:
+		return types.OrderPayload{}, fmt.Errorf("GTD order needs an expiration")
+	case orderType != types.OrderTypeGTD && order.Expiration > 0:
+		return types.OrderPayload{}, fmt.Errorf("expiration is only allowed on GTD orders, got %s", orderType)
+	}
+
 	tickSize := order.TickSize
 	if tickSize == "" {
 		tickSize = types.Tick001
@@ -155,17 +207,18 @@ func (c *Client) buildOrderPayload(order types.UserOrder) (types.OrderPayload, e
 			Signature:     signedOrder.Signature,
 		},
 		Owner:     signedOrder.Owner,
-		OrderType: order.OrderType,
+		OrderType: orderType,
+		PostOnly:  order.PostOnly,
 	}, nil
 }
 
-// PostOrders places up to 15 orders in a batch.
+// PostOrders places up to types.MaxOrdersPerBatch orders in a batch.
 func (c *Client) PostOrders(ctx context.Context, orders []types.UserOrder, negRisk bool) ([]types.OrderResponse, error) {
 	if len(orders) == 0 {
 		return nil, nil
 	}
-	if len(orders) > 15 {
-		return nil, fmt.Errorf("batch limit is 15 orders, got %d", len(orders))
+	if len(orders) > types.MaxOrdersPerBatch {
+		return nil, fmt.Errorf("batch limit is %d orders, got %d", types.MaxOrdersPerBatch, len(orders))
 	}
 	if c.dryRun {
 		c.logger.Info("DRY-RUN: would post orders", "count", len(orders))
@@ -208,9 +261,14 @@ func (c *Client) PostOrders(ctx context.Context, orders []types.UserOrder, negRi
 		return nil, fmt.Errorf("post orders: %w", err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("post orders: status %d: %s", resp.StatusCode(), resp.String())
+		return nil, c.statusError("post orders", c.rl.Order, resp)
 	}
 
+	for i := range results {
+		if !results[i].Success && results[i].ErrorMsg != "" {
+			results[i].Err = ClassifyRejection(results[i].ErrorMsg)
+		}
+	}
 	return results, nil
 }
 
@@ -251,7 +309,7 @@ func (c *Client) CancelOrders(ctx context.Context, orderIDs []string) (*types.Ca
 		return nil, fmt.Errorf("cancel orders: %w", err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("cancel orders: status %d: %s", resp.StatusCode(), resp.String())
+		return nil, c.statusError("cancel orders", c.rl.Cancel, resp)
 	}
 
 	c.logger.Info("orders cancelled", "count", len(result.Canceled))
@@ -283,7 +341,7 @@ func (c *Client) CancelAll(ctx context.Context) (*types.CancelResponse, error) {
 		return nil, fmt.Errorf("cancel all: %w", err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("cancel all: status %d: %s", resp.StatusCode(), resp.String())
+		return nil, c.statusError("cancel all", c.rl.Cancel, resp)
 	}
 
 	c.logger.Warn("all orders cancelled", "count", len(result.Canceled))
@@ -317,7 +375,7 @@ func (c *Client) CancelMarketOrders(ctx context.Context, conditionID string) (*t
 		return nil, fmt.Errorf("cancel market orders: %w", err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("cancel market orders: status %d: %s", resp.StatusCode(), resp.String())
+		return nil, c.statusError("cancel market orders", c.rl.Cancel, resp)
 	}
 	return &result, nil
 }
@@ -339,7 +397,7 @@ func (c *Client) DeriveAPIKey(ctx context.Context) (*Credentials, error) {
 		return nil, fmt.Errorf("derive api key: %w", err)
 	}
 	if resp.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("derive api key: status %d: %s", resp.StatusCode(), resp.String())
+		return nil, c.statusError("derive api key", nil, resp)
 	}
 
 	c.auth.SetCredentials(result)
@@ -208,3 +208,64 @@ func TestBuildOrderPayloadRejectsInvalidTokenID(t *testing.T) {
 		t.Fatal("expected error for invalid token ID")
 	}
 }
+
+func TestBuildOrderPayloadValidatesOrderType(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := config.Config{
+		Wallet: config.WalletConfig{
+			PrivateKey:    "0x1111111111111111111111111111111111111111111111111111111111111111",
+			ChainID:       137,
+			SignatureType: 0,
+		},
+		API: config.APIConfig{CLOBBaseURL: "http://localhost", ApiKey: "k", Secret: "s", Passphrase: "p"},
+	}
+	auth, err := NewAuth(cfg)
+	if err != nil {
+		t.Fatalf("NewAuth: %v", err)
+	}
+	c := NewClient(cfg, auth, logger)
+
+	tests := []struct {
+		name       string
+		orderType  types.OrderType
+		postOnly   bool
+		expiration int64
+		wantErr    bool
+	}{
+		{"default is GTC", "", true, 0, false},
+		{"GTD with expiration", types.OrderTypeGTD, true, 1_900_000_000, false},
+		{"GTD without expiration", types.OrderTypeGTD, false, 0, true},
+		{"GTC with expiration", types.OrderTypeGTC, false, 1_900_000_000, true},
+		{"FAK", types.OrderTypeFAK, false, 0, false},
+		{"post-only FOK", types.OrderTypeFOK, true, 0, true},
+		{"post-only FAK", types.OrderTypeFAK, true, 0, true},
+	}
+	for _, tt := range tests {
+		payload, err := c.buildOrderPayload(types.UserOrder{
+			TokenID:    "12345678901234567890",
+			Price:      0.50,
+			Size:       10,
+			Side:       types.BUY,
+			OrderType:  tt.orderType,
+			PostOnly:   tt.postOnly,
+			Expiration: tt.expiration,
+			TickSize:   types.Tick001,
+		})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		want := tt.orderType
+		if want == "" {
+			want = types.OrderTypeGTC
+		}
+		if payload.OrderType != want || payload.PostOnly != tt.postOnly {
+			t.Errorf("%s: payload type %s post-only %v", tt.name, payload.OrderType, payload.PostOnly)
+		}
+	}
+}
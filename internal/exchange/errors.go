@@ -0,0 +1,121 @@
+package exchange
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"polymarket-mm/pkg/types"
+)
+
+// defaultRetryAfter is how long to back off after a 429 without a usable
+// Retry-After header: one of Polymarket's 10-second rate-limit windows.
+const defaultRetryAfter = 10 * time.Second
+
+// rejectionReasons maps fragments of the CLOB's per-order errorMsg onto
+// typed rejection reasons, most specific first.
+var rejectionReasons = []struct {
+	fragment string
+	reason   error
+}{
+	{"post-only", types.ErrWouldCross},
+	{"crosses book", types.ErrWouldCross},
+	{"expir", types.ErrOrderExpired},
+	{"balance", types.ErrInsufficientBalance},
+	{"allowance", types.ErrInsufficientBalance},
+	{"fully filled", types.ErrNotFilled},
+	{"fok", types.ErrNotFilled},
+	{"no orders found to match", types.ErrNotFilled},
+	{"closed", types.ErrMarketClosed},
+	{"not accepting orders", types.ErrMarketClosed},
+}
+
+// ClassifyRejection turns an order's errorMsg into a *types.RejectionError.
+// Unrecognised messages classify as types.ErrOrderRejected.
+func ClassifyRejection(msg string) error {
+	lower := strings.ToLower(msg)
+	for _, r := range rejectionReasons {
+		if strings.Contains(lower, r.fragment) {
+			return &types.RejectionError{Reason: r.reason, Msg: msg}
+		}
+	}
+	return &types.RejectionError{Reason: types.ErrOrderRejected, Msg: msg}
+}
+
+// APIError is a non-200 response from the CLOB. Kind is the types.Err*
+// failure it matches with errors.Is, or nil for unclassified statuses
+// (5xx after retries, 404).
+type APIError struct {
+	Op         string
+	Status     int
+	Kind       error
+	Body       string
+	RetryAfter time.Duration // set on 429s
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: status %d: %s", e.Op, e.Status, e.Body)
+}
+
+func (e *APIError) Unwrap() error { return e.Kind }
+
+// classifyStatus maps an HTTP status and body onto a typed failure.
+func classifyStatus(status int, body string) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return types.ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return types.ErrAuthExpired
+	case status >= 400 && status < 500 && status != http.StatusNotFound:
+		lower := strings.ToLower(body)
+		switch {
+		case strings.Contains(lower, "balance"), strings.Contains(lower, "allowance"):
+			return types.ErrInsufficientBalance
+		case strings.Contains(lower, "closed"), strings.Contains(lower, "not accepting orders"):
+			return types.ErrMarketClosed
+		}
+		return types.ErrValidation
+	}
+	return nil
+}
+
+// statusError builds the *APIError for a failed response. On a 429 the
+// request's bucket (nil for unlimited endpoints) is drained for the
+// server's Retry-After, so every caller of that category backs off together
+// instead of retrying into the limit.
+func (c *Client) statusError(op string, bucket *TokenBucket, resp *resty.Response) error {
+	err := &APIError{
+		Op:     op,
+		Status: resp.StatusCode(),
+		Kind:   classifyStatus(resp.StatusCode(), resp.String()),
+		Body:   resp.String(),
+	}
+	if err.Status == http.StatusTooManyRequests {
+		err.RetryAfter = parseRetryAfter(resp.Header().Get("Retry-After"), time.Now())
+		if bucket != nil {
+			bucket.Drain(err.RetryAfter)
+		}
+		c.logger.Warn("rate limited by exchange, backing off", "op", op, "retry_after", err.RetryAfter)
+	}
+	return err
+}
+
+// parseRetryAfter reads a Retry-After header in either delay-seconds or
+// HTTP-date form.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
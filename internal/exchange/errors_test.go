@@ -0,0 +1,108 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestClassifyRejection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		msg  string
+		want error
+	}{
+		{"invalid post-only order: order crosses book", types.ErrWouldCross},
+		{"not enough balance / allowance", types.ErrInsufficientBalance},
+		{"invalid expiration", types.ErrOrderExpired},
+		{"order couldn't be fully filled, FOK orders are fully filled or killed", types.ErrNotFilled},
+		{"no orders found to match with FAK order", types.ErrNotFilled},
+		{"invalid tick size", types.ErrOrderRejected},
+	}
+	for _, tt := range tests {
+		err := ClassifyRejection(tt.msg)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("ClassifyRejection(%q) = %v, want %v", tt.msg, err, tt.want)
+		}
+		var rej *types.RejectionError
+		if !errors.As(err, &rej) || rej.Msg != tt.msg {
+			t.Errorf("ClassifyRejection(%q) lost the message: %v", tt.msg, err)
+		}
+	}
+}
+
+func TestClassifyStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{http.StatusTooManyRequests, "", types.ErrRateLimited},
+		{http.StatusUnauthorized, "Unauthorized/Invalid api key", types.ErrAuthExpired},
+		{http.StatusBadRequest, `{"error":"not enough balance / allowance"}`, types.ErrInsufficientBalance},
+		{http.StatusBadRequest, `{"error":"market is closed"}`, types.ErrMarketClosed},
+		{http.StatusBadRequest, `{"error":"invalid order payload"}`, types.ErrValidation},
+		{http.StatusNotFound, "", nil},
+		{http.StatusBadGateway, "", nil},
+	}
+	for _, tt := range tests {
+		if got := classifyStatus(tt.status, tt.body); got != tt.want {
+			t.Errorf("classifyStatus(%d, %q) = %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"3", 3 * time.Second},
+		{now.Add(5 * time.Second).Format(http.TimeFormat), 5 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"", defaultRetryAfter},
+		{"soon", defaultRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitedResponseDrainsBucket(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		http.Error(w, `{"error":"too many requests"}`, http.StatusTooManyRequests)
+	})
+
+	_, err := c.GetTokenBalance(context.Background(), "yes")
+	if !errors.Is(err, types.ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 2*time.Second || apiErr.Status != http.StatusTooManyRequests {
+		t.Errorf("api error = %+v", apiErr)
+	}
+	if stats := c.RateLimitStats()["data"]; stats.Throttled != 1 {
+		t.Errorf("data bucket stats = %+v, want one throttle", stats)
+	}
+
+	// The next read waits out the Retry-After instead of hitting the limit
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := c.rl.Data.Wait(ctx); err == nil {
+		t.Error("bucket should still be backing off")
+	}
+}
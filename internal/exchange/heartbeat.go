@@ -0,0 +1,62 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// heartbeatPath is the CLOB's dead-man switch endpoint. Once a heartbeat has
+// been sent, the exchange cancels all of the account's orders if the next one
+// doesn't arrive within its timeout.
+const heartbeatPath = "/v1/heartbeats"
+
+// ErrHeartbeatUnsupported is returned when the CLOB doesn't expose the
+// heartbeat endpoint.
+var ErrHeartbeatUnsupported = errors.New("heartbeat endpoint not available")
+
+// PostHeartbeat proves liveness to the exchange. Each heartbeat carries the
+// ID returned by the previous one (empty to start a new chain) and returns
+// the ID for the next.
+func (c *Client) PostHeartbeat(ctx context.Context, heartbeatID string) (string, error) {
+	if c.dryRun {
+		return heartbeatID, nil
+	}
+	if err := c.rl.Heartbeat.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(map[string]string{"heartbeat_id": heartbeatID})
+	if err != nil {
+		return "", fmt.Errorf("marshal heartbeat: %w", err)
+	}
+	headers, err := c.auth.L2Headers("POST", heartbeatPath, string(body))
+	if err != nil {
+		return "", fmt.Errorf("l2 headers: %w", err)
+	}
+
+	var result struct {
+		HeartbeatID string `json:"heartbeat_id"`
+	}
+	resp, err := c.http.R().
+		SetContext(ctx).
+		SetHeaders(headers).
+		SetBody(json.RawMessage(body)).
+		SetResult(&result).
+		Post(heartbeatPath)
+	if err != nil {
+		return "", fmt.Errorf("post heartbeat: %w", err)
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+		return result.HeartbeatID, nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return "", ErrHeartbeatUnsupported
+	default:
+		// Not drained on a 429: backing off past the exchange's timeout
+		// would cancel every resting order
+		return "", c.statusError("post heartbeat", nil, resp)
+	}
+}
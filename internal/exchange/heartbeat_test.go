@@ -0,0 +1,77 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestPostHeartbeatChainsIDs(t *testing.T) {
+	t.Parallel()
+	var got []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != heartbeatPath || r.Header.Get("POLY_API_KEY") != "key" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var body struct {
+			HeartbeatID string `json:"heartbeat_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		got = append(got, body.HeartbeatID)
+		fmt.Fprintf(w, `{"heartbeat_id":"hb-%d"}`, len(got))
+	})
+
+	id, err := c.PostHeartbeat(context.Background(), "")
+	if err != nil {
+		t.Fatalf("PostHeartbeat: %v", err)
+	}
+	if id, err = c.PostHeartbeat(context.Background(), id); err != nil {
+		t.Fatalf("PostHeartbeat: %v", err)
+	}
+	if id != "hb-2" || len(got) != 2 || got[0] != "" || got[1] != "hb-1" {
+		t.Errorf("id = %q, sent %q", id, got)
+	}
+}
+
+func TestPostHeartbeatUnsupported(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	if _, err := c.PostHeartbeat(context.Background(), ""); !errors.Is(err, ErrHeartbeatUnsupported) {
+		t.Errorf("err = %v, want ErrHeartbeatUnsupported", err)
+	}
+}
+
+func TestPostHeartbeatIgnoresDataBackoff(t *testing.T) {
+	t.Parallel()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != heartbeatPath {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, `{"error":"too many requests"}`, http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"heartbeat_id":"hb-1"}`)
+	})
+
+	// A 429 on an account read backs the data bucket off for 30s
+	if _, err := c.GetTokenBalance(context.Background(), "yes"); !errors.Is(err, types.ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if id, err := c.PostHeartbeat(ctx, ""); err != nil || id != "hb-1" {
+		t.Errorf("PostHeartbeat = %q, %v; want it sent despite the data back-off", id, err)
+	}
+}
@@ -4,16 +4,27 @@
 // windows. This file provides a smooth token-bucket implementation that refills
 // continuously (rather than in 10s bursts) to avoid hitting hard limits.
 //
-// Three buckets are maintained:
+// Five buckets are maintained:
 //   - Order:  350 burst / 50 per sec (maps to Polymarket's 3500/10s limit)
 //   - Cancel: 300 burst / 30 per sec (maps to 3000/10s limit)
 //   - Book:   150 burst / 15 per sec (maps to 1500/10s limit)
+//   - Data:   50 burst / 5 per sec (account reads: open orders, trades, balances)
+//   - Heartbeat: 5 burst / 1 per sec (dead-man switch)
+//
+// Each bucket's burst and rate can be overridden from config.RateLimitConfig,
+// except Heartbeat's. A 429 drains the bucket for the server's Retry-After
+// (see statusError), and every bucket keeps wait-time stats for the
+// dashboard. Heartbeat is never drained: a back-off longer than the
+// exchange's heartbeat timeout would cancel all of our orders.
 package exchange
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
+
+	"polymarket-mm/internal/config"
 )
 
 // TokenBucket implements a token-bucket rate limiter with continuous refill.
@@ -24,6 +35,16 @@ type TokenBucket struct {
 	capacity float64   // maximum burst size
 	rate     float64   // tokens refilled per second
 	lastTime time.Time // last time tokens were calculated
+	stats    BucketStats
+}
+
+// BucketStats summarises how much a bucket has held callers back.
+type BucketStats struct {
+	Requests  int64         `json:"requests"`
+	Delayed   int64         `json:"delayed"`    // requests that had to wait
+	TotalWait time.Duration `json:"total_wait"` // summed over all requests
+	MaxWait   time.Duration `json:"max_wait"`
+	Throttled int64         `json:"throttled"` // 429s that drained the bucket
 }
 
 // NewTokenBucket creates a rate limiter with the given capacity and refill rate.
@@ -38,18 +59,15 @@ func NewTokenBucket(capacity, ratePerSecond float64) *TokenBucket {
 
 // Wait blocks until a token is available or ctx is cancelled.
 func (tb *TokenBucket) Wait(ctx context.Context) error {
+	start := time.Now()
 	for {
 		tb.mu.Lock()
 		now := time.Now()
-		elapsed := now.Sub(tb.lastTime).Seconds()
-		tb.tokens += elapsed * tb.rate
-		if tb.tokens > tb.capacity {
-			tb.tokens = tb.capacity
-		}
-		tb.lastTime = now
+		tb.refillLocked(now)
 
 		if tb.tokens >= 1 {
 			tb.tokens--
+			tb.recordLocked(now.Sub(start))
 			tb.mu.Unlock()
 			return nil
 		}
@@ -74,15 +92,88 @@ type RateLimiter struct {
 	Order  *TokenBucket // POST /orders — placing new orders
 	Cancel *TokenBucket // DELETE /orders, /cancel-all, /cancel-market-orders
 	Book   *TokenBucket // GET /book — order book reads
+	Data   *TokenBucket // GET /data/orders, /data/trades, /balance-allowance
+
+	// POST /v1/heartbeats, kept apart so other categories' back-offs
+	// can't delay it
+	Heartbeat *TokenBucket
 }
 
 // NewRateLimiter creates rate limiters tuned to Polymarket's published limits.
 // Capacities are set to the 10-second burst allowance, rates to 1/10th for
 // smooth refill.
 func NewRateLimiter() *RateLimiter {
+	return NewRateLimiterFromConfig(config.RateLimitConfig{})
+}
+
+// NewRateLimiterFromConfig is NewRateLimiter with per-category overrides;
+// zero fields keep the defaults.
+func NewRateLimiterFromConfig(cfg config.RateLimitConfig) *RateLimiter {
 	return &RateLimiter{
-		Order:  NewTokenBucket(350, 50),  // 3500 per 10s window
-		Cancel: NewTokenBucket(300, 30),  // 3000 per 10s window
-		Book:   NewTokenBucket(150, 15),  // 1500 per 10s window
+		Order:  newBucket(cfg.Order, 350, 50),  // 3500 per 10s window
+		Cancel: newBucket(cfg.Cancel, 300, 30), // 3000 per 10s window
+		Book:   newBucket(cfg.Book, 150, 15),   // 1500 per 10s window
+		Data:   newBucket(cfg.Data, 50, 5),     // low-volume reconciliation reads
+
+		Heartbeat: NewTokenBucket(5, 1), // one request per heartbeat interval
 	}
 }
+
+func newBucket(cfg config.BucketConfig, burst, rate float64) *TokenBucket {
+	if cfg.Burst > 0 {
+		burst = cfg.Burst
+	}
+	if cfg.Rate > 0 {
+		rate = cfg.Rate
+	}
+	return NewTokenBucket(burst, rate)
+}
+
+// Stats returns each bucket's wait metrics keyed by category.
+func (rl *RateLimiter) Stats() map[string]BucketStats {
+	return map[string]BucketStats{
+		"order":     rl.Order.Stats(),
+		"cancel":    rl.Cancel.Stats(),
+		"book":      rl.Book.Stats(),
+		"data":      rl.Data.Stats(),
+		"heartbeat": rl.Heartbeat.Stats(),
+	}
+}
+
+// refillLocked adds the tokens accrued since the last update.
+func (tb *TokenBucket) refillLocked(now time.Time) {
+	tb.tokens += now.Sub(tb.lastTime).Seconds() * tb.rate
+	if tb.tokens > tb.capacity {
+		tb.tokens = tb.capacity
+	}
+	tb.lastTime = now
+}
+
+func (tb *TokenBucket) recordLocked(waited time.Duration) {
+	tb.stats.Requests++
+	if waited <= 0 {
+		return
+	}
+	tb.stats.Delayed++
+	tb.stats.TotalWait += waited
+	if waited > tb.stats.MaxWait {
+		tb.stats.MaxWait = waited
+	}
+}
+
+// Drain empties the bucket so the next token becomes available only after
+// d. It never shortens an existing back-off.
+func (tb *TokenBucket) Drain(d time.Duration) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.refillLocked(time.Now())
+	tb.tokens = math.Min(tb.tokens, 1-d.Seconds()*tb.rate)
+	tb.stats.Throttled++
+}
+
+// Stats returns the bucket's wait metrics.
+func (tb *TokenBucket) Stats() BucketStats {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.stats
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"polymarket-mm/internal/config"
 )
 
 func TestNewTokenBucketStartsFull(t *testing.T) {
@@ -70,3 +72,36 @@ func TestTokenBucketContextCancelled(t *testing.T) {
 		t.Error("expected context error, got nil")
 	}
 }
+
+func TestTokenBucketDrainBacksOff(t *testing.T) {
+	t.Parallel()
+	tb := NewTokenBucket(100, 100)
+
+	tb.Drain(150 * time.Millisecond)
+	// A shorter Retry-After must not cut the back-off short
+	tb.Drain(10 * time.Millisecond)
+
+	start := time.Now()
+	if err := tb.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Wait after drain returned in %v, want ~150ms", elapsed)
+	}
+
+	stats := tb.Stats()
+	if stats.Requests != 1 || stats.Delayed != 1 || stats.Throttled != 2 || stats.MaxWait < 100*time.Millisecond {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestNewRateLimiterFromConfigOverrides(t *testing.T) {
+	t.Parallel()
+	rl := NewRateLimiterFromConfig(config.RateLimitConfig{Data: config.BucketConfig{Burst: 7}})
+	if rl.Data.capacity != 7 || rl.Data.rate != 5 {
+		t.Errorf("data bucket = %v burst / %v per sec, want 7 / 5", rl.Data.capacity, rl.Data.rate)
+	}
+	if rl.Order.capacity != 350 {
+		t.Errorf("order burst = %v, want default 350", rl.Order.capacity)
+	}
+}
@@ -3,7 +3,9 @@
 // Two independent feeds run concurrently:
 //
 //   - Market feed (public): subscribes by asset ID (token ID), receives
-//     "book" snapshots and "price_change" deltas for the order book.
+//     "book" snapshots and "price_change" deltas for the order book,
+//     "last_trade_price" prints of public trades, and "market_resolved"
+//     notices.
 //
 //   - User feed (authenticated): subscribes by condition ID, receives
 //     "trade" fills and "order" lifecycle events (placement, cancellation).
@@ -35,6 +37,12 @@ const (
 	tradeBufferSize  = 64                // buffer for trade/order events
 )
 
+// FrameRecorder receives every raw frame read from a feed, tagged with the
+// channel type and local receive time. Implementations must not block.
+type FrameRecorder interface {
+	RecordFrame(channel string, recv time.Time, data []byte)
+}
+
 // WSFeed manages a single WebSocket connection (market or user channel).
 // It handles connection lifecycle, subscription tracking, message routing,
 // and automatic reconnection with exponential backoff.
@@ -52,9 +60,13 @@ type WSFeed struct {
 	// Typed event channels — consumers read from these via accessor methods
 	bookCh        chan types.WSBookEvent        // full book snapshots
 	priceChangeCh chan types.WSPriceChangeEvent // incremental book updates
+	lastTradeCh   chan types.WSLastTradePriceEvent // public trade prints
+	resolvedCh    chan types.WSMarketResolvedEvent  // market resolutions
 	tradeCh       chan types.WSTradeEvent       // fill notifications
 	orderCh       chan types.WSOrderEvent       // order lifecycle events
 
+	recorder FrameRecorder // optional raw frame tee, set before Run
+
 	logger *slog.Logger
 }
 
@@ -66,6 +78,8 @@ func NewMarketFeed(wsURL string, logger *slog.Logger) *WSFeed {
 		subscribed:    make(map[string]bool),
 		bookCh:        make(chan types.WSBookEvent, readBufferSize),
 		priceChangeCh: make(chan types.WSPriceChangeEvent, readBufferSize),
+		lastTradeCh:   make(chan types.WSLastTradePriceEvent, readBufferSize),
+		resolvedCh:    make(chan types.WSMarketResolvedEvent, tradeBufferSize),
 		tradeCh:       make(chan types.WSTradeEvent, tradeBufferSize),
 		orderCh:       make(chan types.WSOrderEvent, tradeBufferSize),
 		logger:        logger.With("component", "ws_market"),
@@ -81,6 +95,8 @@ func NewUserFeed(wsURL string, auth *Auth, logger *slog.Logger) *WSFeed {
 		subscribed:    make(map[string]bool),
 		bookCh:        make(chan types.WSBookEvent, readBufferSize),
 		priceChangeCh: make(chan types.WSPriceChangeEvent, readBufferSize),
+		lastTradeCh:   make(chan types.WSLastTradePriceEvent, readBufferSize),
+		resolvedCh:    make(chan types.WSMarketResolvedEvent, tradeBufferSize),
 		tradeCh:       make(chan types.WSTradeEvent, tradeBufferSize),
 		orderCh:       make(chan types.WSOrderEvent, tradeBufferSize),
 		logger:        logger.With("component", "ws_user"),
@@ -93,12 +109,21 @@ func (f *WSFeed) BookEvents() <-chan types.WSBookEvent { return f.bookCh }
 // PriceChangeEvents returns a read-only channel of price change events.
 func (f *WSFeed) PriceChangeEvents() <-chan types.WSPriceChangeEvent { return f.priceChangeCh }
 
+// LastTradeEvents returns a read-only channel of public trade prints.
+func (f *WSFeed) LastTradeEvents() <-chan types.WSLastTradePriceEvent { return f.lastTradeCh }
+
+// MarketResolvedEvents returns market resolution notices (market channel only).
+func (f *WSFeed) MarketResolvedEvents() <-chan types.WSMarketResolvedEvent { return f.resolvedCh }
+
 // TradeEvents returns a read-only channel of trade events (user channel).
 func (f *WSFeed) TradeEvents() <-chan types.WSTradeEvent { return f.tradeCh }
 
 // OrderEvents returns a read-only channel of order events (user channel).
 func (f *WSFeed) OrderEvents() <-chan types.WSOrderEvent { return f.orderCh }
 
+// SetRecorder tees every received frame to r. Must be called before Run.
+func (f *WSFeed) SetRecorder(r FrameRecorder) { f.recorder = r }
+
 // Run connects and maintains the WebSocket connection with auto-reconnect.
 // Blocks until ctx is cancelled.
 func (f *WSFeed) Run(ctx context.Context) error {
@@ -142,6 +167,7 @@ func (f *WSFeed) Subscribe(ctx context.Context, ids []string) error {
 	}
 	if f.channelType == "market" {
 		msg.AssetIDs = ids
+		msg.CustomFeatureEnabled = true
 	} else {
 		msg.Markets = ids
 	}
@@ -219,6 +245,9 @@ func (f *WSFeed) connectAndRead(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("read: %w", err)
 		}
+		if f.recorder != nil {
+			f.recorder.RecordFrame(f.channelType, time.Now(), msg)
+		}
 
 		f.dispatchMessage(msg)
 	}
@@ -234,8 +263,9 @@ func (f *WSFeed) sendInitialSubscription() error {
 
 	if f.channelType == "market" {
 		msg := types.WSSubscribeMsg{
-			Type:     "market",
-			AssetIDs: ids,
+			Type:                 "market",
+			AssetIDs:             ids,
+			CustomFeatureEnabled: true, // needed for market_resolved
 		}
 		return f.writeJSON(msg)
 	}
@@ -308,7 +338,31 @@ func (f *WSFeed) dispatchMessage(data []byte) {
 			f.logger.Warn("order channel full, dropping event", "id", evt.ID)
 		}
 
-	case "last_trade_price", "tick_size_change", "best_bid_ask", "new_market", "market_resolved":
+	case "last_trade_price":
+		var evt types.WSLastTradePriceEvent
+		if err := json.Unmarshal(data, &evt); err != nil {
+			f.logger.Error("unmarshal last_trade_price event", "error", err)
+			return
+		}
+		select {
+		case f.lastTradeCh <- evt:
+		default:
+			f.logger.Warn("last_trade_price channel full, dropping event", "asset", evt.AssetID)
+		}
+
+	case "market_resolved":
+		var evt types.WSMarketResolvedEvent
+		if err := json.Unmarshal(data, &evt); err != nil {
+			f.logger.Error("unmarshal market_resolved event", "error", err)
+			return
+		}
+		select {
+		case f.resolvedCh <- evt:
+		default:
+			f.logger.Warn("market_resolved channel full, dropping event", "market", evt.Market)
+		}
+
+	case "tick_size_change", "best_bid_ask", "new_market":
 		// Informational events we don't need to process
 		f.logger.Debug("ignoring event", "type", envelope.EventType)
 
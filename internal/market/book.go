@@ -6,11 +6,18 @@
 //   - WebSocket events via ApplyBookEvent (full snapshots) and ApplyPriceChange
 //     (incremental updates)
 //
+// Between snapshots, every price_change delta inserts, resizes or removes a
+// level in the sorted bid/ask ladders. Each delta also carries the server's
+// best bid/ask after the change; if our ladder disagrees, the asset is flagged
+// for a REST resync until the next full snapshot arrives.
+//
 // The Book is concurrency-safe (RWMutex protected) and provides derived
 // values like MidPrice and BestBidAsk for the strategy layer.
 package market
 
 import (
+	"math"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -29,7 +36,9 @@ type Book struct {
 	yes      types.OrderBookSnapshot // YES token order book (bids desc, asks asc)
 	no       types.OrderBookSnapshot // NO token order book
 	lastHash map[string]string     // latest book hash per asset (for staleness)
+	desynced map[string]bool       // assets awaiting a resync snapshot
 	updated  time.Time             // last time any book data arrived
+	now      func() time.Time      // clock (replaced by the backtester)
 }
 
 // NewBook creates a new local order book for a market.
@@ -39,9 +48,19 @@ func NewBook(marketID, yesToken, noToken string) *Book {
 		yesToken: yesToken,
 		noToken:  noToken,
 		lastHash: make(map[string]string),
+		desynced: make(map[string]bool),
+		now:      time.Now,
 	}
 }
 
+// SetClock replaces the time source used for update timestamps and
+// staleness checks. The backtester uses it to run on simulated time.
+func (b *Book) SetClock(now func() time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.now = now
+}
+
 // ApplyBookEvent replaces the book for one token with a full snapshot.
 func (b *Book) ApplyBookEvent(event types.WSBookEvent) {
 	b.applySnapshot(event.AssetID, event.Buys, event.Sells, event.Hash)
@@ -58,10 +77,10 @@ func (b *Book) applySnapshot(assetID string, bids, asks []types.PriceLevel, hash
 
 	snap := types.OrderBookSnapshot{
 		AssetID:   assetID,
-		Bids:      bids,
-		Asks:      asks,
+		Bids:      sortLevels(bids, true),
+		Asks:      sortLevels(asks, false),
 		Hash:      hash,
-		Timestamp: time.Now(),
+		Timestamp: b.now(),
 	}
 
 	if assetID == b.yesToken {
@@ -71,18 +90,72 @@ func (b *Book) applySnapshot(assetID string, bids, asks []types.PriceLevel, hash
 	}
 
 	b.lastHash[assetID] = hash
-	b.updated = time.Now()
+	delete(b.desynced, assetID)
+	b.updated = b.now()
 }
 
-// ApplyPriceChange applies an incremental price_change event.
-func (b *Book) ApplyPriceChange(event types.WSPriceChangeEvent) {
+// ApplyPriceChange applies an incremental price_change event to the bid/ask
+// ladders. A size of "0" removes the level. After all changes are applied,
+// the best bid/ask reported by the server is compared against the local
+// ladder; assets that disagree are returned so the caller can resync them
+// from REST. An asset is only returned once until its next full snapshot.
+func (b *Book) ApplyPriceChange(event types.WSPriceChangeEvent) []string {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	// The last change per asset carries the server's top of book after the event.
+	latest := make(map[string]types.WSPriceChange)
+	var assets []string
+
 	for _, pc := range event.PriceChanges {
+		snap := b.snapshotLocked(pc.AssetID)
+		if snap == nil {
+			continue
+		}
+
+		switch types.Side(pc.Side) {
+		case types.BUY:
+			snap.Bids = applyLevel(snap.Bids, pc.Price, pc.Size, true)
+		case types.SELL:
+			snap.Asks = applyLevel(snap.Asks, pc.Price, pc.Size, false)
+		}
+		snap.Hash = pc.Hash
+		snap.Timestamp = b.now()
+
 		b.lastHash[pc.AssetID] = pc.Hash
+		if _, seen := latest[pc.AssetID]; !seen {
+			assets = append(assets, pc.AssetID)
+		}
+		latest[pc.AssetID] = pc
+	}
+	b.updated = b.now()
+
+	var resync []string
+	for _, assetID := range assets {
+		pc := latest[assetID]
+		snap := b.snapshotLocked(assetID)
+		if topMatches(snap.Bids, pc.BestBid) && topMatches(snap.Asks, pc.BestAsk) {
+			continue
+		}
+		if !b.desynced[assetID] {
+			b.desynced[assetID] = true
+			resync = append(resync, assetID)
+		}
+	}
+	return resync
+}
+
+// snapshotLocked returns the snapshot for an asset, or nil if the asset
+// does not belong to this market. Must be called with lock held.
+func (b *Book) snapshotLocked(assetID string) *types.OrderBookSnapshot {
+	switch assetID {
+	case b.yesToken:
+		return &b.yes
+	case b.noToken:
+		return &b.no
+	default:
+		return nil
 	}
-	b.updated = time.Now()
 }
 
 // MidPrice returns the mid price for the YES token, computed as
@@ -111,6 +184,79 @@ func (b *Book) BestBidAsk() (bid, ask float64, ok bool) {
 	return parsePrice(b.yes.Bids[0].Price), parsePrice(b.yes.Asks[0].Price), true
 }
 
+// TokenBestBidAsk returns the best bid and ask for either token. A missing
+// side is returned as 0; ok is false only if both sides are empty or the
+// token does not belong to this market.
+func (b *Book) TokenBestBidAsk(assetID string) (bid, ask float64, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snap := b.snapshotLocked(assetID)
+	if snap == nil || (len(snap.Bids) == 0 && len(snap.Asks) == 0) {
+		return 0, 0, false
+	}
+	if len(snap.Bids) > 0 {
+		bid = parsePrice(snap.Bids[0].Price)
+	}
+	if len(snap.Asks) > 0 {
+		ask = parsePrice(snap.Asks[0].Price)
+	}
+	return bid, ask, true
+}
+
+// SyntheticBidAsk combines both books into one YES-denominated touch.
+// Buying NO at p is economically selling YES at 1-p, so the best YES-
+// equivalent bid is max(YES bid, 1 - NO ask) and the best ask is
+// min(YES ask, 1 - NO bid). Returns false unless both sides are known.
+func (b *Book) SyntheticBidAsk() (bid, ask float64, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.yes.Bids) > 0 {
+		bid = parsePrice(b.yes.Bids[0].Price)
+	}
+	if len(b.yes.Asks) > 0 {
+		ask = parsePrice(b.yes.Asks[0].Price)
+	}
+	if len(b.no.Asks) > 0 {
+		bid = math.Max(bid, 1-parsePrice(b.no.Asks[0].Price))
+	}
+	if len(b.no.Bids) > 0 {
+		if noBid := 1 - parsePrice(b.no.Bids[0].Price); ask == 0 || noBid < ask {
+			ask = noBid
+		}
+	}
+	if bid <= 0 || ask <= 0 {
+		return 0, 0, false
+	}
+	return bid, ask, true
+}
+
+// SyntheticMidPrice returns the mid of the combined YES/NO touch in YES
+// terms. Returns false if either synthetic side is missing.
+func (b *Book) SyntheticMidPrice() (float64, bool) {
+	bid, ask, ok := b.SyntheticBidAsk()
+	if !ok {
+		return 0, false
+	}
+	return (bid + ask) / 2, true
+}
+
+// Ladder returns copies of the bid and ask levels for one token, best first.
+// Returns nil slices if the token does not belong to this market.
+func (b *Book) Ladder(assetID string) (bids, asks []types.PriceLevel) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snap := b.snapshotLocked(assetID)
+	if snap == nil {
+		return nil, nil
+	}
+	bids = append([]types.PriceLevel(nil), snap.Bids...)
+	asks = append([]types.PriceLevel(nil), snap.Asks...)
+	return bids, asks
+}
+
 // IsStale returns true if the book hasn't been updated within maxAge.
 func (b *Book) IsStale(maxAge time.Duration) bool {
 	b.mu.RLock()
@@ -119,7 +265,7 @@ func (b *Book) IsStale(maxAge time.Duration) bool {
 	if b.updated.IsZero() {
 		return true
 	}
-	return time.Since(b.updated) > maxAge
+	return b.now().Sub(b.updated) > maxAge
 }
 
 // LastUpdated returns the timestamp of the last book update.
@@ -129,7 +275,87 @@ func (b *Book) LastUpdated() time.Time {
 	return b.updated
 }
 
+// IsDesynced returns true if the asset's ladder disagreed with the server's
+// reported top of book and no resync snapshot has been applied since.
+func (b *Book) IsDesynced(assetID string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.desynced[assetID]
+}
+
+// ClearDesynced drops the resync flag for an asset so the next mismatching
+// delta requests another resync. Used when a REST resync attempt fails.
+func (b *Book) ClearDesynced(assetID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.desynced, assetID)
+}
+
+// priceEpsilon absorbs float formatting differences such as "0.5" vs "0.50".
+const priceEpsilon = 1e-9
+
+// applyLevel inserts, resizes or removes one price level in a sorted ladder.
+// Bids are kept descending (best first), asks ascending.
+func applyLevel(levels []types.PriceLevel, price, size string, desc bool) []types.PriceLevel {
+	p := parsePrice(price)
+	remove := parsePrice(size) <= 0
+
+	// First index whose price is at or beyond p in ladder order
+	i := sort.Search(len(levels), func(i int) bool {
+		lp := parsePrice(levels[i].Price)
+		if desc {
+			return lp <= p+priceEpsilon
+		}
+		return lp >= p-priceEpsilon
+	})
+
+	exists := i < len(levels) && math.Abs(parsePrice(levels[i].Price)-p) < priceEpsilon
+	switch {
+	case exists && remove:
+		return append(levels[:i], levels[i+1:]...)
+	case exists:
+		levels[i].Size = size
+		return levels
+	case remove:
+		return levels
+	}
+
+	levels = append(levels, types.PriceLevel{})
+	copy(levels[i+1:], levels[i:])
+	levels[i] = types.PriceLevel{Price: price, Size: size}
+	return levels
+}
+
+// sortLevels returns a copy of levels ordered best-first with empty levels
+// dropped. The REST API does not guarantee best-first ordering.
+func sortLevels(levels []types.PriceLevel, desc bool) []types.PriceLevel {
+	out := make([]types.PriceLevel, 0, len(levels))
+	for _, l := range levels {
+		if parsePrice(l.Size) > 0 {
+			out = append(out, l)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if desc {
+			return parsePrice(out[i].Price) > parsePrice(out[j].Price)
+		}
+		return parsePrice(out[i].Price) < parsePrice(out[j].Price)
+	})
+	return out
+}
+
+// topMatches reports whether the ladder's best level agrees with the
+// server-reported best price. An empty or zero best means an empty side.
+func topMatches(levels []types.PriceLevel, best string) bool {
+	want := parsePrice(best)
+	if len(levels) == 0 {
+		return want == 0
+	}
+	return math.Abs(parsePrice(levels[0].Price)-want) < priceEpsilon
+}
+
 func parsePrice(s string) float64 {
 	v, _ := strconv.ParseFloat(s, 64)
 	return v
 }
+
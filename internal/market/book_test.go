@@ -1,6 +1,7 @@
 package market
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -148,3 +149,200 @@ func TestIsStale(t *testing.T) {
 		t.Error("book should be stale after maxAge")
 	}
 }
+
+func seedYesBook(b *Book) {
+	b.ApplyBookResponse(&types.BookResponse{
+		AssetID: testYesToken,
+		Bids:    []types.PriceLevel{{Price: "0.50", Size: "100"}, {Price: "0.48", Size: "50"}},
+		Asks:    []types.PriceLevel{{Price: "0.52", Size: "100"}, {Price: "0.55", Size: "80"}},
+		Hash:    "h0",
+	})
+}
+
+func TestApplyBookResponseSortsLevels(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+
+	// REST returns bids ascending and asks descending (best last)
+	b.ApplyBookResponse(&types.BookResponse{
+		AssetID: testYesToken,
+		Bids:    []types.PriceLevel{{Price: "0.40", Size: "10"}, {Price: "0.45", Size: "10"}},
+		Asks:    []types.PriceLevel{{Price: "0.60", Size: "10"}, {Price: "0.55", Size: "10"}},
+		Hash:    "h1",
+	})
+
+	bid, ask, ok := b.BestBidAsk()
+	if !ok {
+		t.Fatal("BestBidAsk returned ok=false")
+	}
+	if bid != 0.45 || ask != 0.55 {
+		t.Errorf("bid/ask = %v/%v, want 0.45/0.55", bid, ask)
+	}
+}
+
+func TestApplyPriceChangeInsertsBetterLevel(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+	seedYesBook(b)
+
+	resync := b.ApplyPriceChange(types.WSPriceChangeEvent{
+		PriceChanges: []types.WSPriceChange{
+			{AssetID: testYesToken, Price: "0.51", Size: "25", Side: "BUY", Hash: "h1", BestBid: "0.51", BestAsk: "0.52"},
+		},
+	})
+	if len(resync) != 0 {
+		t.Fatalf("unexpected resync request: %v", resync)
+	}
+
+	bid, ask, _ := b.BestBidAsk()
+	if bid != 0.51 || ask != 0.52 {
+		t.Errorf("bid/ask = %v/%v, want 0.51/0.52", bid, ask)
+	}
+}
+
+func TestApplyPriceChangeRemovesLevel(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+	seedYesBook(b)
+
+	resync := b.ApplyPriceChange(types.WSPriceChangeEvent{
+		PriceChanges: []types.WSPriceChange{
+			{AssetID: testYesToken, Price: "0.52", Size: "0", Side: "SELL", Hash: "h1", BestBid: "0.50", BestAsk: "0.55"},
+			{AssetID: testYesToken, Price: "0.5", Size: "0", Side: "BUY", Hash: "h2", BestBid: "0.48", BestAsk: "0.55"},
+		},
+	})
+	if len(resync) != 0 {
+		t.Fatalf("unexpected resync request: %v", resync)
+	}
+
+	bid, ask, _ := b.BestBidAsk()
+	if bid != 0.48 || ask != 0.55 {
+		t.Errorf("bid/ask = %v/%v, want 0.48/0.55", bid, ask)
+	}
+	mid, _ := b.MidPrice()
+	if math.Abs(mid-0.515) > 1e-9 {
+		t.Errorf("mid = %v, want 0.515", mid)
+	}
+}
+
+func TestApplyPriceChangeUpdatesSize(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+	seedYesBook(b)
+
+	b.ApplyPriceChange(types.WSPriceChangeEvent{
+		PriceChanges: []types.WSPriceChange{
+			{AssetID: testYesToken, Price: "0.50", Size: "7", Side: "BUY", Hash: "h1", BestBid: "0.50", BestAsk: "0.52"},
+		},
+	})
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.yes.Bids) != 2 {
+		t.Fatalf("bid levels = %d, want 2", len(b.yes.Bids))
+	}
+	if b.yes.Bids[0].Size != "7" {
+		t.Errorf("best bid size = %s, want 7", b.yes.Bids[0].Size)
+	}
+}
+
+func TestApplyPriceChangeMismatchRequestsResync(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+	seedYesBook(b)
+
+	// Server says best bid is 0.49 but our ladder still has 0.50 on top
+	change := types.WSPriceChangeEvent{
+		PriceChanges: []types.WSPriceChange{
+			{AssetID: testYesToken, Price: "0.47", Size: "10", Side: "BUY", Hash: "h1", BestBid: "0.49", BestAsk: "0.52"},
+		},
+	}
+
+	resync := b.ApplyPriceChange(change)
+	if len(resync) != 1 || resync[0] != testYesToken {
+		t.Fatalf("resync = %v, want [%s]", resync, testYesToken)
+	}
+	if !b.IsDesynced(testYesToken) {
+		t.Error("asset should be flagged desynced")
+	}
+
+	// Further mismatches don't re-request while a resync is pending
+	if again := b.ApplyPriceChange(change); len(again) != 0 {
+		t.Errorf("duplicate resync request: %v", again)
+	}
+
+	// A full snapshot clears the flag
+	seedYesBook(b)
+	if b.IsDesynced(testYesToken) {
+		t.Error("snapshot should clear desynced flag")
+	}
+}
+
+func TestApplyPriceChangeIgnoresForeignAsset(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+	seedYesBook(b)
+
+	resync := b.ApplyPriceChange(types.WSPriceChangeEvent{
+		PriceChanges: []types.WSPriceChange{
+			{AssetID: "other-token", Price: "0.90", Size: "10", Side: "BUY", BestBid: "0.90"},
+		},
+	})
+	if len(resync) != 0 {
+		t.Errorf("foreign asset should not request resync: %v", resync)
+	}
+	bid, _, _ := b.BestBidAsk()
+	if bid != 0.50 {
+		t.Errorf("bid = %v, want 0.50", bid)
+	}
+}
+
+func TestSyntheticBidAskUsesBetterBook(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+
+	b.ApplyBookEvent(types.WSBookEvent{
+		AssetID: testYesToken,
+		Buys:    []types.PriceLevel{{Price: "0.40", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.60", Size: "100"}},
+	})
+	// NO ask 0.55 implies a YES bid of 0.45; NO bid 0.38 implies a YES ask of 0.62
+	b.ApplyBookEvent(types.WSBookEvent{
+		AssetID: testNoToken,
+		Buys:    []types.PriceLevel{{Price: "0.38", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.55", Size: "100"}},
+	})
+
+	bid, ask, ok := b.SyntheticBidAsk()
+	if !ok {
+		t.Fatal("SyntheticBidAsk returned ok=false")
+	}
+	if math.Abs(bid-0.45) > 1e-9 || math.Abs(ask-0.60) > 1e-9 {
+		t.Errorf("synthetic touch = %v/%v, want 0.45/0.60", bid, ask)
+	}
+	if mid, _ := b.SyntheticMidPrice(); math.Abs(mid-0.525) > 1e-9 {
+		t.Errorf("synthetic mid = %v, want 0.525", mid)
+	}
+}
+
+func TestSyntheticBidAskFromNoBookOnly(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+
+	if _, _, ok := b.SyntheticBidAsk(); ok {
+		t.Error("empty books should have no synthetic touch")
+	}
+
+	b.ApplyBookEvent(types.WSBookEvent{
+		AssetID: testNoToken,
+		Buys:    []types.PriceLevel{{Price: "0.30", Size: "10"}},
+		Sells:   []types.PriceLevel{{Price: "0.35", Size: "10"}},
+	})
+	bid, ask, ok := b.SyntheticBidAsk()
+	if !ok || math.Abs(bid-0.65) > 1e-9 || math.Abs(ask-0.70) > 1e-9 {
+		t.Errorf("synthetic touch = %v/%v ok=%v, want 0.65/0.70", bid, ask, ok)
+	}
+	if _, _, ok := b.TokenBestBidAsk("unknown"); ok {
+		t.Error("TokenBestBidAsk should reject foreign tokens")
+	}
+}
@@ -0,0 +1,115 @@
+package market
+
+import (
+	"sync"
+
+	"polymarket-mm/pkg/types"
+)
+
+// minImpliedSum and maxImpliedSum bound the implied probability sum an
+// EventBook will normalise. Outside them the prices are more likely wrong
+// (a missing outcome, a stale reference) than incoherent.
+const (
+	minImpliedSum = 0.5
+	maxImpliedSum = 1.5
+)
+
+// EventBook is a view across all outcomes of a neg-risk event. Outcomes we
+// trade are priced from their live Book; the rest from the reference prices
+// of the latest scan. Safe for concurrent use: each outcome's Maker reads it
+// from its own goroutine.
+type EventBook struct {
+	mu    sync.RWMutex
+	info  types.EventInfo
+	ref   map[string]float64 // conditionID -> reference YES price
+	books map[string]*Book   // conditionID -> live book of an outcome we trade
+}
+
+// NewEventBook creates the view for one event.
+func NewEventBook(info types.EventInfo) *EventBook {
+	eb := &EventBook{books: make(map[string]*Book)}
+	eb.Update(info)
+	return eb
+}
+
+// Update replaces the event's outcome list and reference prices with those
+// of a newer scan.
+func (eb *EventBook) Update(info types.EventInfo) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.info = info
+	eb.ref = make(map[string]float64, len(info.Outcomes))
+	for _, o := range info.Outcomes {
+		eb.ref[o.ConditionID] = o.YesPrice
+	}
+}
+
+// Info returns the event's description from the latest scan.
+func (eb *EventBook) Info() types.EventInfo {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	return eb.info
+}
+
+// Attach registers the live book of an outcome we started trading.
+func (eb *EventBook) Attach(conditionID string, book *Book) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.books[conditionID] = book
+}
+
+// Detach unregisters an outcome's book and reports whether any remain.
+func (eb *EventBook) Detach(conditionID string) (empty bool) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	delete(eb.books, conditionID)
+	return len(eb.books) == 0
+}
+
+// Prices returns each outcome's YES price: its live mid if we trade it and
+// its book has both sides, its reference price otherwise.
+func (eb *EventBook) Prices() map[string]float64 {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	prices := make(map[string]float64, len(eb.ref))
+	for id, ref := range eb.ref {
+		prices[id] = ref
+		if book, ok := eb.books[id]; ok {
+			if mid, ok := book.MidPrice(); ok {
+				prices[id] = mid
+			}
+		}
+	}
+	return prices
+}
+
+// ImpliedSum is the sum of the outcomes' YES prices, with conditionID
+// priced at own. For a coherent event it is about 1.
+func (eb *EventBook) ImpliedSum(conditionID string, own float64) float64 {
+	sum := own
+	for id, p := range eb.Prices() {
+		if id != conditionID {
+			sum += p
+		}
+	}
+	return sum
+}
+
+// CoherentPrice rescales own, the outcome's own fair price, so that the
+// event's implied probabilities sum to 1. It returns own unchanged (and
+// false) if the outcome isn't part of the event or the sum is implausible.
+func (eb *EventBook) CoherentPrice(conditionID string, own float64) (float64, bool) {
+	eb.mu.RLock()
+	_, ok := eb.ref[conditionID]
+	eb.mu.RUnlock()
+	if !ok {
+		return own, false
+	}
+	sum := eb.ImpliedSum(conditionID, own)
+	if sum < minImpliedSum || sum > maxImpliedSum {
+		return own, false
+	}
+	return own / sum, true
+}
@@ -0,0 +1,81 @@
+package market
+
+import (
+	"math"
+	"testing"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestGroupEventsTagsNegRiskOutcomes(t *testing.T) {
+	t.Parallel()
+	a := baseMarket()
+	a.ConditionID, a.BestBid, a.BestAsk = "a", 0.58, 0.62
+	b := baseMarket()
+	b.ConditionID, b.OutcomePrices = "b", `["0.3","0.7"]`
+	c := baseMarket()
+	c.ConditionID, c.LastTradePrice = "c", 0.15
+	solo := baseMarket()
+	solo.ConditionID = "solo"
+
+	byCondition := groupEvents([]GammaEvent{
+		{ID: "ev", Slug: "who-wins", NegRisk: true, Markets: []GammaMarket{a, b, c}},
+		{ID: "plain", Markets: []GammaMarket{solo, solo}},
+	})
+	allocs := []types.MarketAllocation{
+		{Market: types.MarketInfo{ConditionID: "b"}},
+		{Market: types.MarketInfo{ConditionID: "solo"}},
+	}
+	events := attachEvents(allocs, byCondition)
+
+	if allocs[0].Market.EventID != "ev" || allocs[0].Market.EventSlug != "who-wins" || allocs[1].Market.EventID != "" {
+		t.Fatalf("allocations = %+v", allocs)
+	}
+	ev, ok := events["ev"]
+	if !ok || len(events) != 1 || len(ev.Outcomes) != 3 {
+		t.Fatalf("events = %+v", events)
+	}
+	want := []float64{0.60, 0.3, 0.15}
+	for i, o := range ev.Outcomes {
+		if math.Abs(o.YesPrice-want[i]) > 1e-9 {
+			t.Errorf("outcome %s price = %v, want %v", o.ConditionID, o.YesPrice, want[i])
+		}
+	}
+}
+
+func TestEventBookCoherentPrice(t *testing.T) {
+	t.Parallel()
+	eb := NewEventBook(types.EventInfo{ID: "ev", Outcomes: []types.EventOutcome{
+		{ConditionID: "a", YesPrice: 0.50},
+		{ConditionID: "b", YesPrice: 0.40},
+		{ConditionID: "c", YesPrice: 0.30},
+	}})
+
+	// A live book overrides b's reference price
+	book := NewBook("b", "b-yes", "b-no")
+	book.ApplyBookResponse(&types.BookResponse{
+		AssetID: "b-yes",
+		Bids:    []types.PriceLevel{{Price: "0.34", Size: "10"}},
+		Asks:    []types.PriceLevel{{Price: "0.36", Size: "10"}},
+	})
+	eb.Attach("b", book)
+
+	// 0.60 + 0.35 + 0.30 = 1.25: a's coherent price is 0.60 / 1.25
+	got, ok := eb.CoherentPrice("a", 0.60)
+	if !ok || math.Abs(got-0.48) > 1e-9 {
+		t.Errorf("coherent price = %v, %v; want 0.48", got, ok)
+	}
+	if _, ok := eb.CoherentPrice("unknown", 0.60); ok {
+		t.Error("outcome outside the event should not be rescaled")
+	}
+	if got, ok := eb.CoherentPrice("a", 5); ok || got != 5 {
+		t.Errorf("implausible sum rescaled: %v", got)
+	}
+
+	if eb.Detach("b") != true {
+		t.Error("event book should be empty after detaching its only outcome")
+	}
+	if got, _ := eb.CoherentPrice("a", 0.30); math.Abs(got-0.30) > 1e-9 {
+		t.Errorf("after detach, coherent price = %v, want 0.30 (sum 1.0)", got)
+	}
+}
@@ -0,0 +1,116 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"polymarket-mm/pkg/types"
+)
+
+// GammaEvent is the JSON shape of a Gamma /events entry. A neg-risk event
+// groups binary markets of which exactly one resolves YES (e.g. election
+// candidates, price-range buckets).
+type GammaEvent struct {
+	ID      string        `json:"id"`
+	Slug    string        `json:"slug"`
+	Title   string        `json:"title"`
+	Active  bool          `json:"active"`
+	Closed  bool          `json:"closed"`
+	NegRisk bool          `json:"negRisk"`
+	Markets []GammaMarket `json:"markets"`
+}
+
+// fetchEvents pages through the active events on Gamma.
+func (s *Scanner) fetchEvents(ctx context.Context) ([]GammaEvent, error) {
+	var allEvents []GammaEvent
+	offset := 0
+	limit := 100
+
+	for {
+		var page []GammaEvent
+		resp, err := s.httpClient.R().
+			SetContext(ctx).
+			SetQueryParams(map[string]string{
+				"limit":  strconv.Itoa(limit),
+				"offset": strconv.Itoa(offset),
+				"active": "true",
+				"closed": "false",
+			}).
+			SetResult(&page).
+			Get("/events")
+		if err != nil {
+			return nil, fmt.Errorf("fetch events page %d: %w", offset, err)
+		}
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("fetch events: status %d", resp.StatusCode())
+		}
+
+		allEvents = append(allEvents, page...)
+
+		if len(page) < limit {
+			break
+		}
+		offset += limit
+	}
+
+	return allEvents, nil
+}
+
+// groupEvents indexes the neg-risk events with at least two outcomes by
+// the condition ID of each of their markets. Outcomes include markets we
+// would never trade, so the event's implied probabilities are complete.
+func groupEvents(events []GammaEvent) map[string]*types.EventInfo {
+	byCondition := make(map[string]*types.EventInfo)
+	for _, ev := range events {
+		if !ev.NegRisk || ev.Closed || len(ev.Markets) < 2 {
+			continue
+		}
+		info := &types.EventInfo{ID: ev.ID, Slug: ev.Slug, Title: ev.Title}
+		for _, gm := range ev.Markets {
+			if gm.Closed || gm.ConditionID == "" {
+				continue
+			}
+			info.Outcomes = append(info.Outcomes, types.EventOutcome{
+				ConditionID: gm.ConditionID,
+				Question:    gm.Question,
+				YesPrice:    referencePrice(gm),
+			})
+		}
+		for _, o := range info.Outcomes {
+			byCondition[o.ConditionID] = info
+		}
+	}
+	return byCondition
+}
+
+// attachEvents tags allocations with their event and returns the events
+// they belong to, keyed by event ID.
+func attachEvents(allocs []types.MarketAllocation, byCondition map[string]*types.EventInfo) map[string]types.EventInfo {
+	events := make(map[string]types.EventInfo)
+	for i := range allocs {
+		info, ok := byCondition[allocs[i].Market.ConditionID]
+		if !ok {
+			continue
+		}
+		allocs[i].Market.EventID = info.ID
+		allocs[i].Market.EventSlug = info.Slug
+		events[info.ID] = *info
+	}
+	return events
+}
+
+// referencePrice is a market's YES price as Gamma last saw it: the mid of
+// its touch, else its quoted outcome price, else its last trade.
+func referencePrice(gm GammaMarket) float64 {
+	if gm.BestBid > 0 && gm.BestAsk > 0 {
+		return (gm.BestBid + gm.BestAsk) / 2
+	}
+	var prices []string
+	if err := parseJSONArray(gm.OutcomePrices, &prices); err == nil && len(prices) > 0 {
+		if p, err := strconv.ParseFloat(prices[0], 64); err == nil {
+			return p
+		}
+	}
+	return gm.LastTradePrice
+}
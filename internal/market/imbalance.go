@@ -0,0 +1,62 @@
+package market
+
+// Depth-weighted analytics on the YES book.
+//
+// On thin books the size resting at the touch says more about the next
+// price move than the mid does: a bid with ten times the ask's size is
+// likely to hold while the ask gets lifted. These helpers turn the top N
+// levels into an imbalance signal and size-weighted fair prices.
+
+// Imbalance returns (bidDepth − askDepth) / (bidDepth + askDepth) over the
+// top levels of each side of the YES book, in [-1, 1]. Positive means more
+// size is bid than offered. Returns false if either side is empty.
+func (b *Book) Imbalance(levels int) (float64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bidDepth, askDepth, ok := b.depthLocked(levels)
+	if !ok {
+		return 0, false
+	}
+	return (bidDepth - askDepth) / (bidDepth + askDepth), true
+}
+
+// Microprice is the touch mid weighted towards the side with less size:
+// (bid × askSize + ask × bidSize) / (bidSize + askSize). A heavy bid pulls
+// it towards the ask, where the price is more likely to go next.
+func (b *Book) Microprice() (float64, bool) {
+	return b.WeightedMid(1)
+}
+
+// WeightedMid generalises Microprice to the size resting in the top levels
+// of each side, which is less noisy when the touch itself is tiny.
+func (b *Book) WeightedMid(levels int) (float64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bidDepth, askDepth, ok := b.depthLocked(levels)
+	if !ok {
+		return 0, false
+	}
+	bid := parsePrice(b.yes.Bids[0].Price)
+	ask := parsePrice(b.yes.Asks[0].Price)
+	return (bid*askDepth + ask*bidDepth) / (bidDepth + askDepth), true
+}
+
+// depthLocked sums the size of the top levels of each side of the YES
+// book. levels < 1 counts the touch only.
+func (b *Book) depthLocked(levels int) (bidDepth, askDepth float64, ok bool) {
+	if levels < 1 {
+		levels = 1
+	}
+	for i := 0; i < levels && i < len(b.yes.Bids); i++ {
+		bidDepth += parsePrice(b.yes.Bids[i].Size)
+	}
+	for i := 0; i < levels && i < len(b.yes.Asks); i++ {
+		askDepth += parsePrice(b.yes.Asks[i].Size)
+	}
+	if bidDepth <= 0 || askDepth <= 0 {
+		return 0, 0, false
+	}
+	return bidDepth, askDepth, true
+}
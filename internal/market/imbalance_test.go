@@ -0,0 +1,39 @@
+package market
+
+import (
+	"math"
+	"testing"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestImbalanceAndWeightedPrices(t *testing.T) {
+	t.Parallel()
+	b := newTestBook()
+	b.ApplyBookResponse(&types.BookResponse{
+		AssetID: testYesToken,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "300"}, {Price: "0.48", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}, {Price: "0.52", Size: "400"}},
+	})
+
+	// Touch only: 300 bid vs 100 ask
+	if imb, ok := b.Imbalance(1); !ok || math.Abs(imb-0.5) > 1e-9 {
+		t.Errorf("Imbalance(1) = %v, %v; want 0.5", imb, ok)
+	}
+	// Heavy bid pulls the microprice towards the ask: (0.49*100 + 0.51*300) / 400
+	if mp, ok := b.Microprice(); !ok || math.Abs(mp-0.505) > 1e-9 {
+		t.Errorf("Microprice = %v, %v; want 0.505", mp, ok)
+	}
+	// Two levels: 400 bid vs 500 ask tips the other way
+	if imb, ok := b.Imbalance(2); !ok || math.Abs(imb-(-100.0/900)) > 1e-9 {
+		t.Errorf("Imbalance(2) = %v, %v; want -1/9", imb, ok)
+	}
+	if wm, ok := b.WeightedMid(2); !ok || math.Abs(wm-(0.49*500+0.51*400)/900) > 1e-9 {
+		t.Errorf("WeightedMid(2) = %v, %v", wm, ok)
+	}
+
+	empty := newTestBook()
+	if _, ok := empty.Imbalance(3); ok {
+		t.Error("Imbalance on an empty book should not be ok")
+	}
+}
@@ -18,7 +18,8 @@ import (
 )
 
 // Scanner periodically polls the Gamma API to discover the best market-making
-// opportunities. It ranks markets by a composite score:
+// opportunities. It ranks markets by a weighted sum of scorers chosen in
+// ScannerConfig.Scoring (see scoring.go); by default that is opportunity alone:
 //
 //   score = spread × √(volume24h) × min(liquidity/10000, 1)
 //
@@ -28,34 +29,45 @@ import (
 
 // GammaMarket is the JSON shape returned by the Gamma API.
 type GammaMarket struct {
-	ID                    string  `json:"id"`
-	Question              string  `json:"question"`
-	ConditionID           string  `json:"conditionId"`
-	Slug                  string  `json:"slug"`
-	Active                bool    `json:"active"`
-	Closed                bool    `json:"closed"`
-	AcceptingOrders       bool    `json:"acceptingOrders"`
-	EnableOrderBook       bool    `json:"enableOrderBook"`
-	EndDate               string  `json:"endDate"`
-	Liquidity             string  `json:"liquidity"`
-	Volume24hr            float64 `json:"volume24hr"`
-	Outcomes              string  `json:"outcomes"`
-	OutcomePrices         string  `json:"outcomePrices"`
-	ClobTokenIds          string  `json:"clobTokenIds"`
-	NegRisk               bool    `json:"negRisk"`
-	Spread                float64 `json:"spread"`
-	BestBid               float64 `json:"bestBid"`
-	BestAsk               float64 `json:"bestAsk"`
-	LastTradePrice        float64 `json:"lastTradePrice"`
-	OrderPriceMinTickSize float64 `json:"orderPriceMinTickSize"`
-	OrderMinSize          float64 `json:"orderMinSize"`
-	RewardsMinSize        float64 `json:"rewardsMinSize"`
-	RewardsMaxSpread      float64 `json:"rewardsMaxSpread"`
+	ID                    string        `json:"id"`
+	Question              string        `json:"question"`
+	ConditionID           string        `json:"conditionId"`
+	Slug                  string        `json:"slug"`
+	Active                bool          `json:"active"`
+	Closed                bool          `json:"closed"`
+	AcceptingOrders       bool          `json:"acceptingOrders"`
+	EnableOrderBook       bool          `json:"enableOrderBook"`
+	EndDate               string        `json:"endDate"`
+	Liquidity             string        `json:"liquidity"`
+	Volume24hr            float64       `json:"volume24hr"`
+	Outcomes              string        `json:"outcomes"`
+	OutcomePrices         string        `json:"outcomePrices"`
+	ClobTokenIds          string        `json:"clobTokenIds"`
+	NegRisk               bool          `json:"negRisk"`
+	Spread                float64       `json:"spread"`
+	BestBid               float64       `json:"bestBid"`
+	BestAsk               float64       `json:"bestAsk"`
+	LastTradePrice        float64       `json:"lastTradePrice"`
+	OneDayPriceChange     float64       `json:"oneDayPriceChange"`
+	OrderPriceMinTickSize float64       `json:"orderPriceMinTickSize"`
+	OrderMinSize          float64       `json:"orderMinSize"`
+	RewardsMinSize        float64       `json:"rewardsMinSize"`
+	RewardsMaxSpread      float64       `json:"rewardsMaxSpread"`
+	ClobRewards           []GammaReward `json:"clobRewards"`
 }
 
-// ScanResult contains markets ranked by opportunity quality.
+// GammaReward is one liquidity rewards program on a Gamma market.
+type GammaReward struct {
+	RewardsDailyRate float64 `json:"rewardsDailyRate"`
+}
+
+// ScanResult contains markets ranked by opportunity quality. Dropped holds
+// the markets that passed the filters but ranked below MaxMarketsActive.
+// Events holds the neg-risk events any of them belong to, keyed by event ID.
 type ScanResult struct {
 	Markets   []types.MarketAllocation
+	Dropped   []types.MarketAllocation
+	Events    map[string]types.EventInfo
 	ScannedAt time.Time
 }
 
@@ -65,7 +77,9 @@ type Scanner struct {
 	cfg        config.ScannerConfig // filter thresholds + poll interval
 	riskCfg    config.RiskConfig    // MaxMarketsActive, MaxPositionPerMarket
 	logger     *slog.Logger
-	resultCh   chan ScanResult // engine reads selected markets from here
+	resultCh   chan ScanResult   // engine reads selected markets from here
+	custom     map[string]Scorer // scorers added with RegisterScorer
+	history    MarketHistory     // our own trading record, for spread_capture
 }
 
 // NewScanner creates a market scanner.
@@ -117,14 +131,24 @@ func (s *Scanner) scan(ctx context.Context) {
 
 	filtered := s.filterMarkets(markets)
 	ranked := s.rankMarkets(filtered)
+	events := s.scanEvents(ctx, ranked)
 
 	// Cap to max active markets
+	var dropped []types.MarketAllocation
 	if len(ranked) > s.riskCfg.MaxMarketsActive {
-		ranked = ranked[:s.riskCfg.MaxMarketsActive]
+		ranked, dropped = ranked[:s.riskCfg.MaxMarketsActive], ranked[s.riskCfg.MaxMarketsActive:]
+	}
+	for _, alloc := range ranked {
+		s.logger.Debug("market selected", "market", alloc.Market.Slug, "score", alloc.Score, "components", alloc.ScoreComponents)
+	}
+	for _, alloc := range dropped {
+		s.logger.Debug("market not selected", "market", alloc.Market.Slug, "score", alloc.Score, "components", alloc.ScoreComponents)
 	}
 
 	result := ScanResult{
 		Markets:   ranked,
+		Dropped:   dropped,
+		Events:    events,
 		ScannedAt: time.Now(),
 	}
 
@@ -132,6 +156,7 @@ func (s *Scanner) scan(ctx context.Context) {
 		"total", len(markets),
 		"filtered", len(filtered),
 		"selected", len(ranked),
+		"events", len(events),
 	)
 
 	// Non-blocking send
@@ -147,6 +172,25 @@ func (s *Scanner) scan(ctx context.Context) {
 	}
 }
 
+// scanEvents groups ranked neg-risk markets into their events. Events are
+// only fetched when a neg-risk market is ranked; if they can't be, the
+// markets are traded as independent binaries until the next scan.
+func (s *Scanner) scanEvents(ctx context.Context, ranked []types.MarketAllocation) map[string]types.EventInfo {
+	negRisk := false
+	for _, alloc := range ranked {
+		negRisk = negRisk || alloc.Market.NegRisk
+	}
+	if !negRisk {
+		return nil
+	}
+	events, err := s.fetchEvents(ctx)
+	if err != nil {
+		s.logger.Warn("event fetch failed, treating neg-risk markets as independent", "error", err)
+		return nil
+	}
+	return attachEvents(ranked, groupEvents(events))
+}
+
 func (s *Scanner) fetchMarkets(ctx context.Context) ([]GammaMarket, error) {
 	var allMarkets []GammaMarket
 	offset := 0
@@ -307,21 +351,38 @@ func (s *Scanner) filterMarkets(markets []GammaMarket) []GammaMarket {
 	return result
 }
 
-// rankMarkets scores and sorts markets by opportunity quality.
-// score = spread × √volume × liquidityFactor, where liquidityFactor
-// is capped at 1.0 (10k USD liquidity saturates the bonus).
+// rankMarkets scores and sorts markets by opportunity quality. Each market's
+// score is the sum of its configured scorers times their weights; the
+// weighted terms are kept per scorer in ScoreComponents.
 func (s *Scanner) rankMarkets(markets []GammaMarket) []types.MarketAllocation {
 	type scored struct {
-		market GammaMarket
-		score  float64
+		market      GammaMarket
+		score       float64
+		components  map[string]float64
+		rewardYield float64
 	}
 
+	env := ScoreEnv{
+		Now:     time.Now(),
+		Capital: s.riskCfg.MaxPositionPerMarket,
+		History: s.history,
+	}
+	if env.History == nil {
+		env.History = noHistory{}
+	}
+	terms := s.scorers()
+
 	var scoredMarkets []scored
 	for _, m := range markets {
+		sm := scored{market: m, components: make(map[string]float64, len(terms))}
+		for _, term := range terms {
+			v := term.weight * term.scorer.Score(m, env)
+			sm.components[term.name] = v
+			sm.score += v
+		}
 		liquidity, _ := strconv.ParseFloat(m.Liquidity, 64)
-		liquidityFactor := math.Min(liquidity/10000.0, 1.0)
-		score := m.Spread * math.Sqrt(m.Volume24hr) * liquidityFactor
-		scoredMarkets = append(scoredMarkets, scored{market: m, score: score})
+		sm.rewardYield = RewardYield(rewardsDailyRate(m), liquidity, env.Capital)
+		scoredMarkets = append(scoredMarkets, sm)
 	}
 
 	sort.Slice(scoredMarkets, func(i, j int) bool {
@@ -331,15 +392,37 @@ func (s *Scanner) rankMarkets(markets []GammaMarket) []types.MarketAllocation {
 	result := make([]types.MarketAllocation, len(scoredMarkets))
 	for i, sm := range scoredMarkets {
 		result[i] = types.MarketAllocation{
-			Market:         convertToMarketInfo(sm.market),
-			MaxPositionUSD: s.riskCfg.MaxPositionPerMarket,
-			Score:          sm.score,
+			Market:          convertToMarketInfo(sm.market),
+			MaxPositionUSD:  s.riskCfg.MaxPositionPerMarket,
+			Score:           sm.score,
+			ScoreComponents: sm.components,
+			RewardYield:     sm.rewardYield,
 		}
 	}
 
 	return result
 }
 
+// RewardYield estimates the daily liquidity rewards per USD if we quote
+// capital alongside the market's existing liquidity, assuming rewards are
+// shared pro rata with the resting book.
+func RewardYield(dailyRate, liquidity, capital float64) float64 {
+	if dailyRate <= 0 || capital <= 0 {
+		return 0
+	}
+	share := capital / (capital + math.Max(liquidity, 0))
+	return dailyRate * share / capital
+}
+
+// rewardsDailyRate sums the market's active reward programs.
+func rewardsDailyRate(gm GammaMarket) float64 {
+	var rate float64
+	for _, r := range gm.ClobRewards {
+		rate += r.RewardsDailyRate
+	}
+	return rate
+}
+
 // convertToMarketInfo transforms a Gamma API response into the internal
 // MarketInfo type used throughout the bot. It parses JSON-encoded token IDs,
 // maps the numeric tick size to the TickSize enum, and converts string
@@ -399,6 +482,7 @@ func convertToMarketInfo(gm GammaMarket) types.MarketInfo {
 		LastTradePrice:   gm.LastTradePrice,
 		RewardsMinSize:   gm.RewardsMinSize,
 		RewardsMaxSpread: gm.RewardsMaxSpread,
+		RewardsDailyRate: rewardsDailyRate(gm),
 	}
 }
 
@@ -357,3 +357,117 @@ func TestRankMarketsLiquidityCap(t *testing.T) {
 			ranked[0].Score, ranked[1].Score)
 	}
 }
+
+func TestRankMarketsRewardsLiftQuietMarkets(t *testing.T) {
+	t.Parallel()
+	s := newTestScanner()
+	s.cfg.Scoring = map[string]float64{ScoreOpportunity: 1, ScoreRewards: 2}
+
+	busy := baseMarket()
+	busy.ID = "busy"
+	busy.Spread = 0.02
+	busy.Volume24hr = 10000
+	busy.Liquidity = "10000"
+
+	quiet := baseMarket()
+	quiet.ID = "quiet"
+	quiet.Spread = 0.02
+	quiet.Volume24hr = 100
+	quiet.Liquidity = "1900"
+	quiet.ClobRewards = []GammaReward{{RewardsDailyRate: 20}, {RewardsDailyRate: 5}}
+
+	ranked := s.rankMarkets([]GammaMarket{busy, quiet})
+	if ranked[0].Market.ID != "quiet" {
+		t.Fatalf("rewards should rank the quiet market first, got %+v", ranked)
+	}
+	// 100 USD alongside 1900: a 5% share of 25/day = 1.25/day, 1.25% yield
+	if math.Abs(ranked[0].RewardYield-0.0125) > 1e-12 || ranked[0].Market.RewardsDailyRate != 25 {
+		t.Errorf("reward yield = %v, daily rate = %v", ranked[0].RewardYield, ranked[0].Market.RewardsDailyRate)
+	}
+	if ranked[1].RewardYield != 0 {
+		t.Errorf("market without rewards has yield %v", ranked[1].RewardYield)
+	}
+}
+
+type fakeHistory map[string]float64
+
+func (h fakeHistory) SpreadCapture(conditionID string) (float64, bool) {
+	c, ok := h[conditionID]
+	return c, ok
+}
+
+func TestRankMarketsWeightsScorerComponents(t *testing.T) {
+	t.Parallel()
+	s := newTestScanner()
+	s.cfg.Scoring = map[string]float64{
+		ScoreOpportunity:      1,
+		ScoreSpreadCapture:    2,
+		ScoreTimeToResolution: 3,
+		ScoreVolEdge:          0.5,
+		"unknown":             1,
+	}
+	s.SetHistory(fakeHistory{"good": 0.01})
+
+	good := baseMarket()
+	good.ID, good.ConditionID = "good", "good"
+	good.OneDayPriceChange = -0.10
+	good.EndDate = time.Now().Add(84 * time.Hour).Format(time.RFC3339)
+
+	other := baseMarket()
+	other.ID, other.ConditionID = "other", "other"
+	other.OneDayPriceChange = 0.001
+
+	ranked := s.rankMarkets([]GammaMarket{good, other})
+	byID := map[string]map[string]float64{}
+	for _, a := range ranked {
+		byID[a.Market.ID] = a.ScoreComponents
+		var sum float64
+		for _, v := range a.ScoreComponents {
+			sum += v
+		}
+		if math.Abs(sum-a.Score) > 1e-9 {
+			t.Errorf("%s: components sum to %v, score %v", a.Market.ID, sum, a.Score)
+		}
+	}
+
+	// 1% realized capture × 2; half of the week horizon × 3; 0.05 / 0.10 × 0.5
+	g := byID["good"]
+	want := map[string]float64{ScoreSpreadCapture: 2, ScoreTimeToResolution: 1.5, ScoreVolEdge: 0.25}
+	for name, w := range want {
+		if math.Abs(g[name]-w) > 1e-3 {
+			t.Errorf("good %s = %v, want %v", name, g[name], w)
+		}
+	}
+	if _, ok := g["unknown"]; ok {
+		t.Error("unknown scorer should be ignored")
+	}
+
+	// No history scores 0; a flat market's move is floored at 0.01
+	o := byID["other"]
+	if o[ScoreSpreadCapture] != 0 || math.Abs(o[ScoreVolEdge]-2.5) > 1e-9 || o[ScoreTimeToResolution] != 3 {
+		t.Errorf("other components = %v", o)
+	}
+}
+
+func TestRegisterScorerOverridesBuiltin(t *testing.T) {
+	t.Parallel()
+	s := newTestScanner()
+	s.RegisterScorer(ScoreOpportunity, ScorerFunc(func(m GammaMarket, _ ScoreEnv) float64 {
+		if m.ID == "favourite" {
+			return 1
+		}
+		return 0
+	}))
+
+	fav := baseMarket()
+	fav.ID = "favourite"
+	fav.Spread = 0.01
+	wide := baseMarket()
+	wide.ID = "wide"
+	wide.Spread = 0.20
+
+	ranked := s.rankMarkets([]GammaMarket{wide, fav})
+	if ranked[0].Market.ID != "favourite" || ranked[0].ScoreComponents[ScoreOpportunity] != 1 {
+		t.Errorf("custom scorer not used: %+v", ranked)
+	}
+}
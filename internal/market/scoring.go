@@ -0,0 +1,173 @@
+package market
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Built-in scorer names, as used in ScannerConfig.Scoring.
+const (
+	ScoreOpportunity      = "opportunity"
+	ScoreRewards          = "rewards"
+	ScoreSpreadCapture    = "spread_capture"
+	ScoreTimeToResolution = "time_to_resolution"
+	ScoreVolEdge          = "vol_edge"
+)
+
+// resolutionHorizon is the time left at which time_to_resolution saturates:
+// a market a week or more from its end date scores the full 1.0.
+const resolutionHorizon = 7 * 24 * time.Hour
+
+// minPriceMove floors the daily price change in vol_edge so a market that
+// hasn't moved doesn't score infinitely.
+const minPriceMove = 0.01
+
+// Scorer rates one filtered market. Scores are combined as a weighted sum,
+// so each scorer should return a value whose typical magnitude is
+// comparable across markets; weights in ScannerConfig.Scoring do the rest.
+type Scorer interface {
+	Score(m GammaMarket, env ScoreEnv) float64
+}
+
+// ScorerFunc adapts a plain function to the Scorer interface.
+type ScorerFunc func(m GammaMarket, env ScoreEnv) float64
+
+// Score calls f.
+func (f ScorerFunc) Score(m GammaMarket, env ScoreEnv) float64 { return f(m, env) }
+
+// ScoreEnv is the context a Scorer sees besides the market itself.
+type ScoreEnv struct {
+	Now     time.Time
+	Capital float64       // USD we would commit to the market (MaxPositionPerMarket)
+	History MarketHistory // our own trading record; never nil
+}
+
+// MarketHistory exposes the bot's own past performance per market.
+type MarketHistory interface {
+	// SpreadCapture returns realized PnL per USD traded in the market, and
+	// false if we have too little history there to say.
+	SpreadCapture(conditionID string) (float64, bool)
+}
+
+// noHistory is the MarketHistory used until the engine provides one.
+type noHistory struct{}
+
+func (noHistory) SpreadCapture(string) (float64, bool) { return 0, false }
+
+// builtinScorers are the scorers available without registration.
+var builtinScorers = map[string]Scorer{
+	ScoreOpportunity:      ScorerFunc(opportunityScore),
+	ScoreRewards:          ScorerFunc(rewardsScore),
+	ScoreSpreadCapture:    ScorerFunc(spreadCaptureScore),
+	ScoreTimeToResolution: ScorerFunc(timeToResolutionScore),
+	ScoreVolEdge:          ScorerFunc(volEdgeScore),
+}
+
+// opportunityScore is spread × √volume × min(liquidity/10000, 1): wide,
+// busy, reasonably deep markets score highest.
+func opportunityScore(m GammaMarket, _ ScoreEnv) float64 {
+	liquidity, _ := strconv.ParseFloat(m.Liquidity, 64)
+	liquidityFactor := math.Min(liquidity/10000.0, 1.0)
+	return m.Spread * math.Sqrt(m.Volume24hr) * liquidityFactor
+}
+
+// rewardsScore is the expected daily reward yield in percent.
+func rewardsScore(m GammaMarket, env ScoreEnv) float64 {
+	liquidity, _ := strconv.ParseFloat(m.Liquidity, 64)
+	return RewardYield(rewardsDailyRate(m), liquidity, env.Capital) * 100
+}
+
+// spreadCaptureScore is our realized PnL per USD traded in the market, in
+// percent. Markets we haven't traded enough score 0, neither helped nor hurt.
+func spreadCaptureScore(m GammaMarket, env ScoreEnv) float64 {
+	capture, ok := env.History.SpreadCapture(m.ConditionID)
+	if !ok {
+		return 0
+	}
+	return capture * 100
+}
+
+// timeToResolutionScore favours markets far from their end date, where
+// informed flow and resolution jumps are least likely: time left as a
+// fraction of resolutionHorizon, capped at 1. Markets without an end date
+// score 1.
+func timeToResolutionScore(m GammaMarket, env ScoreEnv) float64 {
+	if m.EndDate == "" {
+		return 1
+	}
+	end, err := time.Parse(time.RFC3339, m.EndDate)
+	if err != nil {
+		return 0
+	}
+	left := end.Sub(env.Now)
+	if left <= 0 {
+		return 0
+	}
+	return math.Min(float64(left)/float64(resolutionHorizon), 1)
+}
+
+// volEdgeScore is the spread earned per unit of daily price movement: wide
+// spreads in calm markets beat the same spread in a market that's trending.
+func volEdgeScore(m GammaMarket, _ ScoreEnv) float64 {
+	return m.Spread / math.Max(math.Abs(m.OneDayPriceChange), minPriceMove)
+}
+
+// weightedScorer is one configured term of the composite score.
+type weightedScorer struct {
+	name   string
+	weight float64
+	scorer Scorer
+}
+
+// scorers resolves ScannerConfig.Scoring into weighted terms, in name order
+// so logs are stable. No configuration means opportunity alone. Unknown
+// names are logged and skipped.
+func (s *Scanner) scorers() []weightedScorer {
+	weights := s.cfg.Scoring
+	if len(weights) == 0 {
+		weights = map[string]float64{ScoreOpportunity: 1}
+	}
+
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var terms []weightedScorer
+	for _, name := range names {
+		weight := weights[name]
+		if weight == 0 {
+			continue
+		}
+		scorer, ok := s.custom[name]
+		if !ok {
+			scorer, ok = builtinScorers[name]
+		}
+		if !ok {
+			if s.logger != nil {
+				s.logger.Warn("unknown scorer in scanner.scoring, ignoring", "name", name)
+			}
+			continue
+		}
+		terms = append(terms, weightedScorer{name: name, weight: weight, scorer: scorer})
+	}
+	return terms
+}
+
+// RegisterScorer makes a custom scorer available to ScannerConfig.Scoring
+// under name, replacing any built-in of the same name. Call before Run.
+func (s *Scanner) RegisterScorer(name string, scorer Scorer) {
+	if s.custom == nil {
+		s.custom = make(map[string]Scorer)
+	}
+	s.custom[name] = scorer
+}
+
+// SetHistory provides the bot's trading record to history-based scorers.
+// Call before Run.
+func (s *Scanner) SetHistory(h MarketHistory) {
+	s.history = h
+}
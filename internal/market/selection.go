@@ -0,0 +1,72 @@
+package market
+
+import (
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/pkg/types"
+)
+
+// Incumbent is a market the engine is already trading, as seen by Select.
+type Incumbent struct {
+	ConditionID string
+	StartedAt   time.Time
+	Holding     bool // has open inventory and hasn't been released
+}
+
+// Selection is Select's verdict: which running markets to keep and which
+// new ones to start. Running markets not in Keep are to be stopped.
+type Selection struct {
+	Keep  map[string]bool
+	Start []types.MarketAllocation
+}
+
+// Select applies the scanner's hysteresis policy to a scan. ranked is every
+// market that passed the filters, best first (ScanResult.Markets followed
+// by ScanResult.Dropped); capacity is MaxMarketsActive.
+//
+// A running market is pinned, kept whatever the scan says and counted
+// against capacity, while it is younger than MinHoldTime or holds
+// inventory. The remaining slots go first to markets scoring at least
+// EnterScore, running or not, in rank order, then to running markets still
+// scoring at least ExitScore. A market between the two thresholds therefore
+// keeps its slot until a stronger market needs it, and a new market has to
+// clear EnterScore to get in.
+func Select(cfg config.ScannerConfig, capacity int, ranked []types.MarketAllocation, running []Incumbent, now time.Time) Selection {
+	sel := Selection{Keep: make(map[string]bool)}
+	isRunning := make(map[string]bool, len(running))
+	used := 0
+
+	for _, inc := range running {
+		isRunning[inc.ConditionID] = true
+		if inc.Holding || now.Sub(inc.StartedAt) < cfg.MinHoldTime {
+			sel.Keep[inc.ConditionID] = true
+			used++
+		}
+	}
+
+	take := func(alloc types.MarketAllocation) {
+		id := alloc.Market.ConditionID
+		if sel.Keep[id] || used >= capacity {
+			return
+		}
+		used++
+		if isRunning[id] {
+			sel.Keep[id] = true
+		} else {
+			sel.Start = append(sel.Start, alloc)
+		}
+	}
+
+	for _, alloc := range ranked {
+		if alloc.Score >= cfg.EnterScore {
+			take(alloc)
+		}
+	}
+	for _, alloc := range ranked {
+		if isRunning[alloc.Market.ConditionID] && alloc.Score >= cfg.ExitScore {
+			take(alloc)
+		}
+	}
+	return sel
+}
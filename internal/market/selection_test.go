@@ -0,0 +1,82 @@
+package market
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/pkg/types"
+)
+
+func alloc(id string, score float64) types.MarketAllocation {
+	return types.MarketAllocation{Market: types.MarketInfo{ConditionID: id}, Score: score}
+}
+
+func startedIDs(sel Selection) []string {
+	var ids []string
+	for _, a := range sel.Start {
+		ids = append(ids, a.Market.ConditionID)
+	}
+	return ids
+}
+
+func TestSelectByRankWithoutHysteresis(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	ranked := []types.MarketAllocation{alloc("a", 3), alloc("b", 2), alloc("c", 1)}
+	running := []Incumbent{{ConditionID: "c", StartedAt: now.Add(-time.Hour)}}
+
+	sel := Select(config.ScannerConfig{}, 2, ranked, running, now)
+	if sel.Keep["c"] || !slices.Equal(startedIDs(sel), []string{"a", "b"}) {
+		t.Errorf("keep = %v, start = %v; want c dropped, a and b started", sel.Keep, startedIDs(sel))
+	}
+}
+
+func TestSelectKeepsIncumbentBetweenThresholds(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	cfg := config.ScannerConfig{EnterScore: 2, ExitScore: 1}
+	old := now.Add(-time.Hour)
+
+	// c has slipped below the entry bar but not the exit bar; the newcomer d
+	// doesn't clear the entry bar, so c keeps its slot
+	ranked := []types.MarketAllocation{alloc("a", 3), alloc("d", 1.8), alloc("c", 1.5)}
+	running := []Incumbent{{ConditionID: "a", StartedAt: old}, {ConditionID: "c", StartedAt: old}}
+	sel := Select(cfg, 2, ranked, running, now)
+	if !sel.Keep["a"] || !sel.Keep["c"] || len(sel.Start) != 0 {
+		t.Errorf("keep = %v, start = %v; want a and c kept", sel.Keep, startedIDs(sel))
+	}
+
+	// A newcomer above the entry bar takes c's slot
+	ranked = []types.MarketAllocation{alloc("a", 3), alloc("e", 2.5), alloc("c", 1.5)}
+	sel = Select(cfg, 2, ranked, running, now)
+	if sel.Keep["c"] || !slices.Equal(startedIDs(sel), []string{"e"}) {
+		t.Errorf("keep = %v, start = %v; want c replaced by e", sel.Keep, startedIDs(sel))
+	}
+
+	// Below the exit bar c goes even with a free slot
+	ranked = []types.MarketAllocation{alloc("a", 3), alloc("c", 0.5)}
+	sel = Select(cfg, 2, ranked, running, now)
+	if sel.Keep["c"] {
+		t.Errorf("keep = %v; want c dropped below exit score", sel.Keep)
+	}
+}
+
+func TestSelectPinsYoungAndHoldingMarkets(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	cfg := config.ScannerConfig{MinHoldTime: 10 * time.Minute}
+	running := []Incumbent{
+		{ConditionID: "young", StartedAt: now.Add(-time.Minute)},
+		{ConditionID: "holding", StartedAt: now.Add(-time.Hour), Holding: true},
+		{ConditionID: "idle", StartedAt: now.Add(-time.Hour)},
+	}
+
+	// Neither pinned market is in the scan; together they fill capacity
+	ranked := []types.MarketAllocation{alloc("new", 5), alloc("idle", 4)}
+	sel := Select(cfg, 2, ranked, running, now)
+	if !sel.Keep["young"] || !sel.Keep["holding"] || sel.Keep["idle"] || len(sel.Start) != 0 {
+		t.Errorf("keep = %v, start = %v; want only the pinned markets", sel.Keep, startedIDs(sel))
+	}
+}
@@ -0,0 +1,406 @@
+// Package paper implements a paper-trading exchange for dry-run mode.
+//
+// The paper Exchange stands in for the live order gateway: it keeps the
+// bot's virtual resting orders, matches them against the live market-channel
+// feed (book updates and public trade prints) using the backtester's
+// queue-position model, and emits synthetic user-channel WSTradeEvent /
+// WSOrderEvent messages that the engine routes exactly like real fills.
+// Virtual USDC and token balances are debited and credited on every fill,
+// and orders that the balances cannot cover are rejected the way the CLOB
+// rejects them.
+package paper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"polymarket-mm/internal/backtest"
+	"polymarket-mm/internal/market"
+	"polymarket-mm/pkg/types"
+)
+
+const (
+	// DefaultStartingUSDC is the virtual cash balance used when none is configured.
+	DefaultStartingUSDC = 1000.0
+
+	eventBufferSize = 256 // synthetic user events awaiting the engine dispatcher
+
+	// ErrInsufficientBalance mirrors the CLOB rejection message.
+	ErrInsufficientBalance = "not enough balance / allowance"
+)
+
+// Balances is a snapshot of the virtual account.
+type Balances struct {
+	USDC          float64            // cash, including amounts reserved by open BUY orders
+	AvailableUSDC float64            // cash not reserved by open orders
+	Tokens        map[string]float64 // token ID → quantity held
+}
+
+// paperOrder tracks what an open order has reserved from the balances.
+type paperOrder struct {
+	conditionID string
+	tokenID     string
+	side        types.Side
+	price       float64
+	remaining   float64
+}
+
+// paperMarket is one market's simulated matching engine.
+type paperMarket struct {
+	info types.MarketInfo
+	sim  *backtest.SimExchange
+}
+
+// Exchange is a concurrency-safe paper-trading order gateway.
+type Exchange struct {
+	mu       sync.Mutex
+	markets  map[string]*paperMarket // conditionID → market
+	byToken  map[string]string       // tokenID → conditionID
+	orders   map[string]*paperOrder  // orderID → open order
+	usdc     float64
+	tokens   map[string]float64
+	reserved float64 // USDC reserved by open BUY orders
+	now      func() time.Time
+
+	tradeCh chan types.WSTradeEvent
+	orderCh chan types.WSOrderEvent
+	logger  *slog.Logger
+}
+
+// NewExchange creates a paper exchange with the given starting cash.
+func NewExchange(startingUSDC float64, logger *slog.Logger) *Exchange {
+	if startingUSDC <= 0 {
+		startingUSDC = DefaultStartingUSDC
+	}
+	return &Exchange{
+		markets: make(map[string]*paperMarket),
+		byToken: make(map[string]string),
+		orders:  make(map[string]*paperOrder),
+		usdc:    startingUSDC,
+		tokens:  make(map[string]float64),
+		now:     time.Now,
+		tradeCh: make(chan types.WSTradeEvent, eventBufferSize),
+		orderCh: make(chan types.WSOrderEvent, eventBufferSize),
+		logger:  logger.With("component", "paper"),
+	}
+}
+
+// SetClock overrides the exchange's time source, which decides when GTD
+// orders expire. It's for tests; call it before the exchange is in use.
+func (x *Exchange) SetClock(now func() time.Time) { x.now = now }
+
+// TradeEvents returns synthetic fill notifications, shaped like the user channel's.
+func (x *Exchange) TradeEvents() <-chan types.WSTradeEvent { return x.tradeCh }
+
+// OrderEvents returns synthetic order lifecycle events, shaped like the user channel's.
+func (x *Exchange) OrderEvents() <-chan types.WSOrderEvent { return x.orderCh }
+
+// AddMarket starts matching orders for a market against its live book.
+func (x *Exchange) AddMarket(info types.MarketInfo, book *market.Book) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if _, ok := x.markets[info.ConditionID]; ok {
+		return
+	}
+	sim := backtest.NewSimExchange(info, book, func() time.Time { return x.now() })
+	sim.SetIDPrefix("paper-" + shortID(info.ConditionID) + "-")
+	x.markets[info.ConditionID] = &paperMarket{info: info, sim: sim}
+	x.byToken[info.YesTokenID] = info.ConditionID
+	x.byToken[info.NoTokenID] = info.ConditionID
+}
+
+// RemoveMarket cancels a market's open orders and stops matching it.
+// Token balances are kept.
+func (x *Exchange) RemoveMarket(conditionID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	m, ok := x.markets[conditionID]
+	if !ok {
+		return
+	}
+	resp, _ := m.sim.CancelMarketOrders(context.Background(), conditionID)
+	x.releaseLocked(resp.Canceled)
+	delete(x.byToken, m.info.YesTokenID)
+	delete(x.byToken, m.info.NoTokenID)
+	delete(x.markets, conditionID)
+}
+
+// Balances returns a snapshot of the virtual account.
+func (x *Exchange) Balances() Balances {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	tokens := make(map[string]float64, len(x.tokens))
+	for id, qty := range x.tokens {
+		tokens[id] = qty
+	}
+	return Balances{USDC: x.usdc, AvailableUSDC: x.usdc - x.reserved, Tokens: tokens}
+}
+
+// PostOrders places virtual orders. Orders the balances cannot cover are
+// rejected; the rest rest on the paper book, and any crossing portion fills
+// immediately against the live book.
+func (x *Exchange) PostOrders(ctx context.Context, orders []types.UserOrder, negRisk bool) ([]types.OrderResponse, error) {
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	if len(orders) > 15 {
+		return nil, fmt.Errorf("batch limit is 15 orders, got %d", len(orders))
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	results := make([]types.OrderResponse, len(orders))
+	for i, order := range orders {
+		m, ok := x.markets[x.byToken[order.TokenID]]
+		if !ok {
+			results[i] = types.OrderResponse{ErrorMsg: "market not found"}
+			continue
+		}
+
+		order.Size = math.Floor(order.Size*100) / 100
+		if !x.canCoverLocked(order) {
+			results[i] = types.OrderResponse{ErrorMsg: ErrInsufficientBalance}
+			continue
+		}
+
+		res, err := m.sim.PostOrders(ctx, []types.UserOrder{order}, negRisk)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = res[0]
+		if !res[0].Success {
+			continue
+		}
+
+		po := &paperOrder{
+			conditionID: m.info.ConditionID,
+			tokenID:     order.TokenID,
+			side:        order.Side,
+			price:       order.Price,
+			remaining:   order.Size,
+		}
+		x.orders[res[0].OrderID] = po
+		if po.side == types.BUY {
+			x.reserved += po.price * po.remaining
+		}
+		if res[0].Status == "live" {
+			x.emitOrderLocked(m, res[0].OrderID, order, "PLACEMENT")
+		}
+
+		x.applyFillsLocked(m.sim.DrainFills())
+	}
+	return results, nil
+}
+
+// CancelOrders cancels virtual orders by ID.
+func (x *Exchange) CancelOrders(ctx context.Context, orderIDs []string) (*types.CancelResponse, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	result := &types.CancelResponse{}
+	byMarket := make(map[string][]string)
+	for _, id := range orderIDs {
+		if po, ok := x.orders[id]; ok {
+			byMarket[po.conditionID] = append(byMarket[po.conditionID], id)
+		}
+	}
+	for conditionID, ids := range byMarket {
+		resp, _ := x.markets[conditionID].sim.CancelOrders(ctx, ids)
+		x.releaseLocked(resp.Canceled)
+		result.Canceled = append(result.Canceled, resp.Canceled...)
+	}
+	return result, nil
+}
+
+// CancelMarketOrders cancels every virtual order in one market.
+func (x *Exchange) CancelMarketOrders(ctx context.Context, conditionID string) (*types.CancelResponse, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	m, ok := x.markets[conditionID]
+	if !ok {
+		return &types.CancelResponse{}, nil
+	}
+	resp, _ := m.sim.CancelMarketOrders(ctx, conditionID)
+	x.releaseLocked(resp.Canceled)
+	return resp, nil
+}
+
+// CancelAll cancels every virtual order.
+func (x *Exchange) CancelAll(ctx context.Context) (*types.CancelResponse, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	result := &types.CancelResponse{}
+	for conditionID, m := range x.markets {
+		resp, _ := m.sim.CancelMarketOrders(ctx, conditionID)
+		x.releaseLocked(resp.Canceled)
+		result.Canceled = append(result.Canceled, resp.Canceled...)
+	}
+	return result, nil
+}
+
+// OnBookUpdate fills orders the market's live book has moved through and
+// expires GTD orders past their expiration.
+// Call it after every book snapshot or delta applied to the market's Book.
+func (x *Exchange) OnBookUpdate(conditionID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if m, ok := x.markets[conditionID]; ok {
+		fills := m.sim.OnBookUpdate()
+		x.expireLocked(m)
+		x.applyFillsLocked(fills)
+	}
+}
+
+// OnTrade matches a public trade print against the paper book.
+func (x *Exchange) OnTrade(trade types.WSLastTradePriceEvent) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if m, ok := x.markets[x.byToken[trade.AssetID]]; ok {
+		fills := m.sim.OnTrade(trade)
+		x.expireLocked(m)
+		x.applyFillsLocked(fills)
+	}
+}
+
+// expireLocked frees the reservations of GTD orders the market's simulator
+// expired and tells the engine they're gone, as the CLOB does.
+func (x *Exchange) expireLocked(m *paperMarket) {
+	for _, evt := range m.sim.DrainExpired() {
+		x.releaseLocked([]string{evt.ID})
+		x.logger.Info("paper order expired", "market", evt.Market, "id", evt.ID)
+		x.emitLocked(types.WSTradeEvent{}, evt)
+	}
+}
+
+// canCoverLocked checks an order against available balances, as the CLOB
+// does: BUYs need the notional in unreserved USDC, SELLs need the tokens.
+func (x *Exchange) canCoverLocked(order types.UserOrder) bool {
+	if order.Side == types.BUY {
+		return order.Price*order.Size <= x.usdc-x.reserved+1e-9
+	}
+	committed := 0.0
+	for _, po := range x.orders {
+		if po.tokenID == order.TokenID && po.side == types.SELL {
+			committed += po.remaining
+		}
+	}
+	return order.Size <= x.tokens[order.TokenID]-committed+1e-9
+}
+
+// applyFillsLocked settles simulated fills against the balances and emits
+// the corresponding user-channel events.
+func (x *Exchange) applyFillsLocked(fills []backtest.SimFill) {
+	for _, f := range fills {
+		price, _ := strconv.ParseFloat(f.Trade.Price, 64)
+		qty, _ := strconv.ParseFloat(f.Trade.Size, 64)
+
+		if po, ok := x.orders[f.Order.ID]; ok {
+			if po.side == types.BUY {
+				x.reserved -= po.price * qty
+			}
+			po.remaining -= qty
+			if po.remaining <= 1e-9 {
+				delete(x.orders, f.Order.ID)
+			}
+		}
+
+		if types.Side(f.Trade.Side) == types.BUY {
+			x.usdc -= price * qty
+			x.tokens[f.Trade.AssetID] += qty
+		} else {
+			x.usdc += price * qty
+			x.tokens[f.Trade.AssetID] -= qty
+		}
+		if x.reserved < 1e-9 {
+			x.reserved = 0
+		}
+
+		x.logger.Info("paper fill",
+			"market", f.Trade.Market,
+			"side", f.Trade.Side,
+			"price", price,
+			"size", qty,
+			"usdc", x.usdc,
+			"tokens", x.tokens[f.Trade.AssetID],
+		)
+
+		x.emitLocked(f.Trade, f.Order)
+	}
+}
+
+// releaseLocked frees the reservations of cancelled orders.
+func (x *Exchange) releaseLocked(orderIDs []string) {
+	for _, id := range orderIDs {
+		po, ok := x.orders[id]
+		if !ok {
+			continue
+		}
+		if po.side == types.BUY {
+			x.reserved -= po.price * po.remaining
+		}
+		delete(x.orders, id)
+	}
+	if x.reserved < 1e-9 {
+		x.reserved = 0 // float drift
+	}
+}
+
+func (x *Exchange) emitOrderLocked(m *paperMarket, orderID string, order types.UserOrder, eventType string) {
+	outcome := "Yes"
+	if order.TokenID == m.info.NoTokenID {
+		outcome = "No"
+	}
+	x.emitLocked(types.WSTradeEvent{}, types.WSOrderEvent{
+		EventType:    "order",
+		ID:           orderID,
+		Market:       m.info.ConditionID,
+		AssetID:      order.TokenID,
+		Side:         string(order.Side),
+		Price:        strconv.FormatFloat(order.Price, 'f', -1, 64),
+		OriginalSize: strconv.FormatFloat(order.Size, 'f', -1, 64),
+		SizeMatched:  "0",
+		Outcome:      outcome,
+		Timestamp:    strconv.FormatInt(x.now().UnixMilli(), 10),
+		Type:         eventType,
+	})
+}
+
+// emitLocked queues synthetic events without blocking; a zero-valued trade
+// or order (no ID) is skipped. A dropped fill is logged as an error since
+// the Maker's inventory would no longer match the paper balances.
+func (x *Exchange) emitLocked(trade types.WSTradeEvent, order types.WSOrderEvent) {
+	if trade.ID != "" {
+		select {
+		case x.tradeCh <- trade:
+		default:
+			x.logger.Error("paper trade channel full, dropping fill", "id", trade.ID)
+		}
+	}
+	if order.ID != "" {
+		select {
+		case x.orderCh <- order:
+		default:
+			x.logger.Warn("paper order channel full, dropping event", "id", order.ID)
+		}
+	}
+}
+
+func shortID(conditionID string) string {
+	if len(conditionID) > 10 {
+		return conditionID[:10]
+	}
+	return conditionID
+}
@@ -0,0 +1,193 @@
+package paper
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/market"
+	"polymarket-mm/pkg/types"
+)
+
+func newTestExchange(t *testing.T, usdc float64) (*Exchange, *market.Book) {
+	t.Helper()
+	info := types.MarketInfo{
+		ConditionID:  "cond-paper",
+		YesTokenID:   "yes",
+		NoTokenID:    "no",
+		TickSize:     types.Tick001,
+		MinOrderSize: 1,
+	}
+	book := market.NewBook(info.ConditionID, info.YesTokenID, info.NoTokenID)
+	book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "yes",
+		Buys:    []types.PriceLevel{{Price: "0.45", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.55", Size: "100"}},
+	})
+
+	x := NewExchange(usdc, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	x.AddMarket(info, book)
+	return x, book
+}
+
+func post(t *testing.T, x *Exchange, order types.UserOrder) types.OrderResponse {
+	t.Helper()
+	res, err := x.PostOrders(context.Background(), []types.UserOrder{order}, false)
+	if err != nil {
+		t.Fatalf("PostOrders: %v", err)
+	}
+	return res[0]
+}
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestPaperBuyFillsFromTradePrint(t *testing.T) {
+	t.Parallel()
+	x, _ := newTestExchange(t, 100)
+
+	res := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.50, Size: 10, Side: types.BUY})
+	if !res.Success || res.Status != "live" {
+		t.Fatalf("order not accepted: %+v", res)
+	}
+	if placed := <-x.OrderEvents(); placed.Type != "PLACEMENT" || placed.ID != res.OrderID {
+		t.Errorf("expected PLACEMENT for %s, got %+v", res.OrderID, placed)
+	}
+	if bal := x.Balances(); !approx(bal.AvailableUSDC, 95) {
+		t.Errorf("AvailableUSDC = %v, want 95 (5 reserved)", bal.AvailableUSDC)
+	}
+
+	// A taker sell prints below our bid: we were ahead of it in price
+	x.OnTrade(types.WSLastTradePriceEvent{AssetID: "yes", Price: "0.45", Size: "4", Side: "SELL"})
+
+	trade := <-x.TradeEvents()
+	if trade.Market != "cond-paper" || trade.Side != "BUY" || trade.Size != "4" || trade.Price != "0.5" {
+		t.Errorf("unexpected trade event: %+v", trade)
+	}
+	if update := <-x.OrderEvents(); update.Type != "UPDATE" || update.SizeMatched != "4" {
+		t.Errorf("unexpected order event: %+v", update)
+	}
+
+	bal := x.Balances()
+	if !approx(bal.USDC, 98) || !approx(bal.Tokens["yes"], 4) {
+		t.Errorf("balances = %+v, want usdc 98, yes 4", bal)
+	}
+	if !approx(bal.AvailableUSDC, 95) {
+		t.Errorf("AvailableUSDC = %v, want 95 (3 still reserved)", bal.AvailableUSDC)
+	}
+}
+
+func TestPaperRejectsUncoveredOrders(t *testing.T) {
+	t.Parallel()
+	x, _ := newTestExchange(t, 10)
+
+	if res := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.60, Size: 5, Side: types.SELL}); res.Success || res.ErrorMsg != ErrInsufficientBalance {
+		t.Errorf("sell without tokens should be rejected, got %+v", res)
+	}
+	if res := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.50, Size: 30, Side: types.BUY}); res.Success {
+		t.Errorf("buy of $15 with $10 should be rejected, got %+v", res)
+	}
+	if res := post(t, x, types.UserOrder{TokenID: "unknown", Price: 0.50, Size: 1, Side: types.BUY}); res.Success {
+		t.Errorf("order for unknown market should be rejected, got %+v", res)
+	}
+}
+
+func TestPaperBookCrossFillsAndSellSettles(t *testing.T) {
+	t.Parallel()
+	x, book := newTestExchange(t, 100)
+
+	// Crossing buy takes the 0.55 ask immediately
+	res := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.55, Size: 10, Side: types.BUY})
+	if !res.Success || res.Status != "matched" {
+		t.Fatalf("crossing order should match immediately: %+v", res)
+	}
+	if trade := <-x.TradeEvents(); trade.Size != "10" {
+		t.Errorf("unexpected taker fill: %+v", trade)
+	}
+
+	// Rest an ask, then the bid side moves up through it
+	ask := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.60, Size: 10, Side: types.SELL})
+	if !ask.Success {
+		t.Fatalf("sell covered by tokens should be accepted: %+v", ask)
+	}
+	book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "yes",
+		Buys:    []types.PriceLevel{{Price: "0.61", Size: "50"}},
+		Sells:   []types.PriceLevel{{Price: "0.63", Size: "50"}},
+	})
+	x.OnBookUpdate("cond-paper")
+
+	if trade := <-x.TradeEvents(); trade.Side != "SELL" || trade.Size != "10" || trade.Price != "0.6" {
+		t.Errorf("unexpected crossed fill: %+v", trade)
+	}
+	bal := x.Balances()
+	if !approx(bal.USDC, 100-5.5+6) || !approx(bal.Tokens["yes"], 0) {
+		t.Errorf("balances = %+v", bal)
+	}
+}
+
+func TestPaperCancelReleasesReservation(t *testing.T) {
+	t.Parallel()
+	x, _ := newTestExchange(t, 100)
+
+	res := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.40, Size: 50, Side: types.BUY})
+	if !approx(x.Balances().AvailableUSDC, 80) {
+		t.Fatalf("AvailableUSDC = %v, want 80", x.Balances().AvailableUSDC)
+	}
+
+	resp, err := x.CancelOrders(context.Background(), []string{res.OrderID})
+	if err != nil || len(resp.Canceled) != 1 {
+		t.Fatalf("CancelOrders = %+v, %v", resp, err)
+	}
+	if !approx(x.Balances().AvailableUSDC, 100) {
+		t.Errorf("AvailableUSDC = %v, want 100 after cancel", x.Balances().AvailableUSDC)
+	}
+
+	post(t, x, types.UserOrder{TokenID: "yes", Price: 0.40, Size: 50, Side: types.BUY})
+	x.RemoveMarket("cond-paper")
+	if !approx(x.Balances().AvailableUSDC, 100) {
+		t.Errorf("RemoveMarket should release reservations, available = %v", x.Balances().AvailableUSDC)
+	}
+}
+
+func TestPaperExpiredGTDReleasesReservation(t *testing.T) {
+	t.Parallel()
+	x, _ := newTestExchange(t, 100)
+	now := time.Unix(1_700_000_000, 0)
+	x.SetClock(func() time.Time { return now })
+
+	res := post(t, x, types.UserOrder{
+		TokenID: "yes", Price: 0.40, Size: 50, Side: types.BUY,
+		OrderType: types.OrderTypeGTD, Expiration: now.Add(5 * time.Minute).Unix(),
+	})
+	if !res.Success {
+		t.Fatalf("GTD order rejected: %+v", res)
+	}
+	<-x.OrderEvents() // PLACEMENT
+	if !approx(x.Balances().AvailableUSDC, 80) {
+		t.Fatalf("AvailableUSDC = %v, want 80", x.Balances().AvailableUSDC)
+	}
+
+	// Past the expiration's security window the CLOB drops the order
+	now = now.Add(5 * time.Minute)
+	x.OnBookUpdate("cond-paper")
+
+	select {
+	case evt := <-x.OrderEvents():
+		if evt.Type != "CANCELLATION" || evt.ID != res.OrderID {
+			t.Errorf("order event = %+v, want CANCELLATION of %s", evt, res.OrderID)
+		}
+	default:
+		t.Fatal("expiry emitted no order event")
+	}
+	if !approx(x.Balances().AvailableUSDC, 100) {
+		t.Errorf("AvailableUSDC = %v, want 100 after expiry", x.Balances().AvailableUSDC)
+	}
+
+	// The freed cash covers an order for the whole balance
+	if res := post(t, x, types.UserOrder{TokenID: "yes", Price: 0.40, Size: 250, Side: types.BUY}); !res.Success {
+		t.Errorf("order after expiry rejected: %+v", res)
+	}
+}
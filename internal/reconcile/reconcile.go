@@ -0,0 +1,350 @@
+// Package reconcile compares the bot's local view of a market (restored
+// Inventory, tracked orders) with the exchange's own records and computes
+// the corrections.
+//
+// The exchange is the source of truth:
+//   - Open orders from GET /data/orders are adopted into the Maker (or
+//     cancelled, if adoption is disabled) instead of being blindly cancelled.
+//   - Trades from GET /data/trades that matched around or after the position
+//     was last saved are replayed through the Inventory trade ledger, which
+//     books the ones the bot missed while it was down (so average entry and
+//     realized PnL stay right) and reverses any whose settlement failed.
+//   - Token balances from GET /balance-allowance are what the wallet really
+//     holds; if Inventory still disagrees after the replay, its quantities
+//     are overwritten and the discrepancy is reported. Tokens the ledger
+//     can't account for are given an entry price from our latest buys of
+//     them, or the market's mark where no trade explains them, so the
+//     average entry covers the whole position.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"polymarket-mm/internal/strategy"
+	"polymarket-mm/pkg/types"
+)
+
+// replayOverlap re-reads trades from slightly before the last save; the
+// trade ledger drops the ones already booked.
+const replayOverlap = time.Minute
+
+// DefaultTolerance absorbs rounding between locally booked fills and the
+// 6-decimal on-chain balances.
+const DefaultTolerance = 0.01
+
+// Exchange is the account state the reconciler reads. *exchange.Client
+// satisfies it.
+type Exchange interface {
+	GetOpenOrders(ctx context.Context, conditionID string) ([]types.OpenOrder, error)
+	GetTrades(ctx context.Context, conditionID string, after time.Time) ([]types.Trade, error)
+	GetTokenBalance(ctx context.Context, tokenID string) (float64, error)
+}
+
+// Options tunes a reconciliation run.
+type Options struct {
+	MakerAddress string  // funder wallet; identifies our side of maker fills
+	AdoptOrders  bool    // adopt resting orders instead of cancelling them
+	Tolerance    float64 // quantity differences at or below this are ignored (0 = DefaultTolerance)
+}
+
+// Report describes what a reconciliation run found and changed.
+type Report struct {
+	Market string
+
+	Adopted  []types.OpenOrder // resting orders the Maker should track
+	ToCancel []string          // resting orders the caller should cancel
+
+	MissedFills    []strategy.Fill // fills replayed into Inventory
+	ReversedTrades []string        // booked trades whose settlement failed
+
+	YesBefore, NoBefore     float64 // Inventory quantities before reconciling
+	YesExchange, NoExchange float64 // wallet balances
+	Corrected               bool    // Inventory quantities were overwritten
+	Seeded                  []Seed  // entries given to balance the ledger couldn't explain
+
+	Discrepancies []string // human-readable notes for the log and dashboard
+}
+
+// Seed is the entry price given to wallet balance the ledger didn't know
+// about.
+type Seed struct {
+	TokenID string
+	Qty     float64
+	Price   float64
+	Source  string // "trades", "mark" or "trades+mark"
+}
+
+// Clean reports whether local state already matched the exchange.
+func (r *Report) Clean() bool {
+	return len(r.ToCancel) == 0 && len(r.MissedFills) == 0 && len(r.ReversedTrades) == 0 && !r.Corrected
+}
+
+// Market reconciles one market's Inventory against the exchange and returns
+// the orders to adopt or cancel. Inventory is updated in place; the caller
+// owns acting on Adopted and ToCancel.
+func Market(ctx context.Context, ex Exchange, info types.MarketInfo, inv *strategy.Inventory, opts Options) (*Report, error) {
+	tol := opts.Tolerance
+	if tol <= 0 {
+		tol = DefaultTolerance
+	}
+
+	pos := inv.Snapshot()
+	report := &Report{
+		Market:    info.ConditionID,
+		YesBefore: pos.YesQty,
+		NoBefore:  pos.NoQty,
+	}
+
+	orders, err := ex.GetOpenOrders(ctx, info.ConditionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		if o.AssetID != info.YesTokenID && o.AssetID != info.NoTokenID {
+			continue
+		}
+		if opts.AdoptOrders {
+			report.Adopted = append(report.Adopted, o)
+		} else {
+			report.ToCancel = append(report.ToCancel, o.ID)
+		}
+	}
+
+	// Replay trades since the position was last saved. A fresh market has
+	// nothing to replay; the balance check below seeds it.
+	var ours []strategy.TradeFills
+	fetched := false
+	if !pos.LastUpdated.IsZero() {
+		since := pos.LastUpdated.Add(-replayOverlap)
+		trades, err := ex.GetTrades(ctx, info.ConditionID, since)
+		if err != nil {
+			return nil, err
+		}
+		ours, fetched = OurTrades(trades, opts.MakerAddress, since), true
+		for _, t := range ours {
+			switch inv.ApplyTrade(t) {
+			case strategy.TradeBooked:
+				report.MissedFills = append(report.MissedFills, t.Fills...)
+			case strategy.TradeReversed:
+				report.ReversedTrades = append(report.ReversedTrades, t.TradeID)
+			}
+		}
+		if n := len(report.MissedFills); n > 0 {
+			report.Discrepancies = append(report.Discrepancies, fmt.Sprintf("replayed %d missed fills", n))
+		}
+		if n := len(report.ReversedTrades); n > 0 {
+			report.Discrepancies = append(report.Discrepancies, fmt.Sprintf("reversed %d failed trades", n))
+		}
+	}
+
+	report.YesExchange, err = ex.GetTokenBalance(ctx, info.YesTokenID)
+	if err != nil {
+		return nil, err
+	}
+	report.NoExchange, err = ex.GetTokenBalance(ctx, info.NoTokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Balance the ledger can't explain is priced from our trades: those
+	// already replayed, or for a fresh market its whole history
+	history := func() ([]strategy.TradeFills, error) {
+		if !fetched {
+			trades, err := ex.GetTrades(ctx, info.ConditionID, time.Time{})
+			if err != nil {
+				return nil, err
+			}
+			ours, fetched = OurTrades(trades, opts.MakerAddress, time.Time{}), true
+		}
+		return ours, nil
+	}
+	yesMark, noMark := mark(info)
+
+	pos = inv.Snapshot()
+	for _, leg := range []struct {
+		name    string
+		tokenID string
+		qty     *float64
+		avg     *float64
+		balance float64
+		mark    float64
+	}{
+		{"YES", info.YesTokenID, &pos.YesQty, &pos.AvgEntryYes, report.YesExchange, yesMark},
+		{"NO", info.NoTokenID, &pos.NoQty, &pos.AvgEntryNo, report.NoExchange, noMark},
+	} {
+		if math.Abs(*leg.qty-leg.balance) <= tol {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies,
+			fmt.Sprintf("%s qty %.4f != balance %.4f", leg.name, *leg.qty, leg.balance))
+		report.Corrected = true
+
+		// Tokens gone from the wallet leave the entry of the rest as is
+		extra := leg.balance - *leg.qty
+		if extra <= 0 {
+			*leg.qty = leg.balance
+			continue
+		}
+		trades, err := history()
+		if err != nil {
+			return nil, err
+		}
+		seed, ok := seedEntry(leg.tokenID, extra, trades, leg.mark)
+		if !ok {
+			report.Discrepancies = append(report.Discrepancies,
+				fmt.Sprintf("no price for %.4f unexplained %s, entry left at %.4f", extra, leg.name, *leg.avg))
+			*leg.qty = leg.balance
+			continue
+		}
+		before := *leg.avg
+		*leg.avg = (*leg.qty**leg.avg + extra*seed.Price) / leg.balance
+		*leg.qty = leg.balance
+		report.Seeded = append(report.Seeded, seed)
+		report.Discrepancies = append(report.Discrepancies,
+			fmt.Sprintf("%s entry %.4f -> %.4f: %.4f unexplained at %.4f from %s", leg.name, before, *leg.avg, extra, seed.Price, seed.Source))
+	}
+	if report.Corrected {
+		inv.SetPosition(pos)
+	}
+
+	return report, nil
+}
+
+// seedEntry prices qty tokens the ledger doesn't account for: at what our
+// latest buys of the token paid, topped up at the mark if they cover less
+// than qty. ok is false if neither is available.
+func seedEntry(tokenID string, qty float64, trades []strategy.TradeFills, mark float64) (seed Seed, ok bool) {
+	var covered, notional float64
+	for i := len(trades) - 1; i >= 0 && covered < qty; i-- {
+		if trades[i].Status == "FAILED" {
+			continue
+		}
+		for _, f := range trades[i].Fills {
+			if f.TokenID != tokenID || f.Side != types.BUY || covered >= qty {
+				continue
+			}
+			take := math.Min(f.Size, qty-covered)
+			covered += take
+			notional += take * f.Price
+		}
+	}
+
+	seed = Seed{TokenID: tokenID, Qty: qty}
+	switch {
+	case covered >= qty:
+		seed.Price, seed.Source = notional/qty, "trades"
+	case mark <= 0 && covered > 0:
+		seed.Price, seed.Source = notional/covered, "trades"
+	case mark <= 0:
+		return seed, false
+	case covered > 0:
+		seed.Price, seed.Source = (notional+(qty-covered)*mark)/qty, "trades+mark"
+	default:
+		seed.Price, seed.Source = mark, "mark"
+	}
+	return seed, true
+}
+
+// mark is the market's YES and NO mid from its scanned top of book, or its
+// last trade price; zero if neither is known.
+func mark(info types.MarketInfo) (yes, no float64) {
+	switch {
+	case info.BestBid > 0 && info.BestAsk > 0:
+		yes = (info.BestBid + info.BestAsk) / 2
+	case info.LastTradePrice > 0:
+		yes = info.LastTradePrice
+	default:
+		return 0, 0
+	}
+	return yes, 1 - yes
+}
+
+// Snapshot fetches the exchange view of one market for the periodic
+// reconciler: resting orders, and our side of trades matched after since.
+func Snapshot(ctx context.Context, ex Exchange, info types.MarketInfo, makerAddress string, since time.Time) (strategy.ExchangeView, error) {
+	view := strategy.ExchangeView{AsOf: time.Now()}
+
+	orders, err := ex.GetOpenOrders(ctx, info.ConditionID)
+	if err != nil {
+		return view, err
+	}
+	trades, err := ex.GetTrades(ctx, info.ConditionID, since)
+	if err != nil {
+		return view, err
+	}
+	view.OpenOrders = orders
+	view.Trades = OurTrades(trades, makerAddress, since)
+	return view, nil
+}
+
+// OurTrades extracts our side of trades matched after since, oldest first.
+// Failed trades are kept so the ledger can reverse them if already booked.
+func OurTrades(trades []types.Trade, makerAddress string, since time.Time) []strategy.TradeFills {
+	var out []strategy.TradeFills
+	for _, t := range trades {
+		matched := parseUnix(t.MatchTime)
+		if !matched.After(since) {
+			continue
+		}
+		fills := OurFills(t, makerAddress, matched)
+		if len(fills) == 0 {
+			continue
+		}
+		out = append(out, strategy.TradeFills{TradeID: t.ID, Status: t.Status, Fills: fills})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Fills[0].Timestamp.Before(out[j].Fills[0].Timestamp) })
+	return out
+}
+
+// OurFills returns our side of a trade. As taker that is the trade itself;
+// as maker it is each of our maker orders in the match. An empty
+// makerAddress treats every maker order as ours.
+func OurFills(t types.Trade, makerAddress string, at time.Time) []strategy.Fill {
+	if t.TraderSide != "MAKER" {
+		return []strategy.Fill{{
+			Timestamp: at,
+			Side:      types.Side(t.Side),
+			TokenID:   t.AssetID,
+			Price:     parseFloat(t.Price),
+			Size:      parseFloat(t.Size),
+			TradeID:   t.ID,
+			Fee:       strategy.FeeUSD(parseFloat(t.FeeRateBps), parseFloat(t.Price), parseFloat(t.Size)),
+		}}
+	}
+
+	var fills []strategy.Fill
+	for _, mo := range t.MakerOrders {
+		if makerAddress != "" && !strings.EqualFold(mo.MakerAddress, makerAddress) {
+			continue
+		}
+		fills = append(fills, strategy.Fill{
+			Timestamp: at,
+			Side:      types.Side(mo.Side),
+			TokenID:   mo.AssetID,
+			Price:     parseFloat(mo.Price),
+			Size:      parseFloat(mo.MatchedAmount),
+			TradeID:   t.ID,
+			Fee:       strategy.FeeUSD(parseFloat(mo.FeeRateBps), parseFloat(mo.Price), parseFloat(mo.MatchedAmount)),
+		})
+	}
+	return fills
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func parseUnix(s string) time.Time {
+	secs, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
@@ -0,0 +1,185 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/strategy"
+	"polymarket-mm/pkg/types"
+)
+
+const ourAddress = "0xAbC0000000000000000000000000000000000001"
+
+type fakeExchange struct {
+	orders     []types.OpenOrder
+	trades     []types.Trade
+	balances   map[string]float64
+	tradeAfter time.Time
+	err        error
+}
+
+func (f *fakeExchange) GetOpenOrders(context.Context, string) ([]types.OpenOrder, error) {
+	return f.orders, f.err
+}
+
+func (f *fakeExchange) GetTrades(_ context.Context, _ string, after time.Time) ([]types.Trade, error) {
+	f.tradeAfter = after
+	return f.trades, nil
+}
+
+func (f *fakeExchange) GetTokenBalance(_ context.Context, tokenID string) (float64, error) {
+	return f.balances[tokenID], nil
+}
+
+func testMarket() types.MarketInfo {
+	return types.MarketInfo{ConditionID: "cond-1", YesTokenID: "yes", NoTokenID: "no"}
+}
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestMarketAdoptsOrdersAndReplaysMissedFills(t *testing.T) {
+	t.Parallel()
+	saved := time.Unix(1_700_000_000, 0)
+	inv := strategy.NewInventory("cond-1", "yes", "no")
+	inv.Restore(strategy.InventoryState{
+		Position: strategy.Position{YesQty: 10, AvgEntryYes: 0.40, LastUpdated: saved},
+		Trades:   map[string]strategy.BookedTrade{"t0": {Status: strategy.TradeMatched}},
+	})
+
+	ex := &fakeExchange{
+		orders: []types.OpenOrder{
+			{ID: "o1", AssetID: "yes", Side: "BUY", Price: "0.45", OriginalSize: "10"},
+			{ID: "other", AssetID: "elsewhere"},
+		},
+		trades: []types.Trade{
+			// Just before the save and already in the ledger
+			{ID: "t0", MatchTime: "1699999999", TraderSide: "TAKER", Side: "BUY", AssetID: "yes", Size: "3", Price: "0.41"},
+			// Our maker order filled while we were down
+			{ID: "t1", MatchTime: "1700000100", TraderSide: "MAKER", MakerOrders: []types.MakerOrder{
+				{MakerAddress: "0xabc0000000000000000000000000000000000001", AssetID: "yes", Side: "SELL", MatchedAmount: "4", Price: "0.50"},
+				{MakerAddress: "0xsomeoneelse", AssetID: "yes", Side: "SELL", MatchedAmount: "7", Price: "0.50"},
+			}},
+			{ID: "t2", MatchTime: "1700000200", Status: "FAILED", TraderSide: "TAKER", Side: "BUY", AssetID: "no", Size: "5", Price: "0.5"},
+		},
+		balances: map[string]float64{"yes": 6, "no": 0},
+	}
+
+	report, err := Market(context.Background(), ex, testMarket(), inv, Options{MakerAddress: ourAddress, AdoptOrders: true})
+	if err != nil {
+		t.Fatalf("Market: %v", err)
+	}
+	if !ex.tradeAfter.Before(saved) {
+		t.Errorf("trades queried after %v, want some overlap before %v", ex.tradeAfter, saved)
+	}
+	if len(report.Adopted) != 1 || report.Adopted[0].ID != "o1" || len(report.ToCancel) != 0 {
+		t.Errorf("adopted = %+v, to cancel = %v", report.Adopted, report.ToCancel)
+	}
+	if len(report.MissedFills) != 1 || report.MissedFills[0].TradeID != "t1" || report.MissedFills[0].Size != 4 {
+		t.Fatalf("missed fills = %+v", report.MissedFills)
+	}
+	if report.Corrected {
+		t.Errorf("replay should have matched the balance, discrepancies = %v", report.Discrepancies)
+	}
+
+	pos := inv.Snapshot()
+	if !approx(pos.YesQty, 6) || !approx(pos.RealizedPnL, 4*(0.50-0.40)) {
+		t.Errorf("position = %+v, want 6 YES with 0.40 realized", pos)
+	}
+}
+
+func TestMarketCorrectsQuantitiesToWalletBalance(t *testing.T) {
+	t.Parallel()
+	inv := strategy.NewInventory("cond-1", "yes", "no")
+	inv.SetPosition(strategy.Position{YesQty: 10, NoQty: 2, AvgEntryYes: 0.40})
+
+	ex := &fakeExchange{
+		orders:   []types.OpenOrder{{ID: "o1", AssetID: "no"}},
+		balances: map[string]float64{"yes": 7.5, "no": 2.004},
+	}
+
+	report, err := Market(context.Background(), ex, testMarket(), inv, Options{})
+	if err != nil {
+		t.Fatalf("Market: %v", err)
+	}
+	if len(report.ToCancel) != 1 || report.ToCancel[0] != "o1" || len(report.Adopted) != 0 {
+		t.Errorf("to cancel = %v, adopted = %+v", report.ToCancel, report.Adopted)
+	}
+	if !report.Corrected || len(report.Discrepancies) != 1 || report.Clean() {
+		t.Errorf("report = %+v, want a single YES correction", report)
+	}
+
+	// NO differs by less than the default tolerance and is left alone
+	pos := inv.Snapshot()
+	if pos.YesQty != 7.5 || pos.NoQty != 2 || pos.AvgEntryYes != 0.40 {
+		t.Errorf("position = %+v", pos)
+	}
+}
+
+func TestMarketSeedsEntryOfWalletOnlyPosition(t *testing.T) {
+	t.Parallel()
+	inv := strategy.NewInventory("cond-1", "yes", "no")
+	info := testMarket()
+	info.BestBid, info.BestAsk = 0.58, 0.62
+
+	// Nothing saved locally, but the wallet holds both tokens. Our buys of
+	// YES explain its balance; nothing explains NO's.
+	ex := &fakeExchange{
+		trades: []types.Trade{
+			{ID: "t1", MatchTime: "1700000000", TraderSide: "TAKER", Side: "BUY", AssetID: "yes", Size: "10", Price: "0.30"},
+			{ID: "t2", MatchTime: "1700000100", TraderSide: "TAKER", Side: "BUY", AssetID: "yes", Size: "6", Price: "0.40"},
+			{ID: "t3", MatchTime: "1700000200", Status: "FAILED", TraderSide: "TAKER", Side: "BUY", AssetID: "yes", Size: "10", Price: "0.90"},
+		},
+		balances: map[string]float64{"yes": 10, "no": 5},
+	}
+
+	report, err := Market(context.Background(), ex, info, inv, Options{})
+	if err != nil {
+		t.Fatalf("Market: %v", err)
+	}
+	if !report.Corrected || len(report.Seeded) != 2 {
+		t.Fatalf("report = %+v, want both legs seeded", report)
+	}
+	if s := report.Seeded[0]; s.TokenID != "yes" || s.Source != "trades" || !approx(s.Price, (6*0.40+4*0.30)/10) {
+		t.Errorf("YES seed = %+v, want the latest 10 bought", s)
+	}
+	if s := report.Seeded[1]; s.TokenID != "no" || s.Source != "mark" || !approx(s.Price, 0.40) {
+		t.Errorf("NO seed = %+v, want the NO mark", s)
+	}
+
+	pos := inv.Snapshot()
+	if pos.YesQty != 10 || !approx(pos.AvgEntryYes, 0.36) || pos.NoQty != 5 || !approx(pos.AvgEntryNo, 0.40) {
+		t.Errorf("position = %+v", pos)
+	}
+}
+
+func TestMarketSeedsEntryOfExtraBalanceAtMark(t *testing.T) {
+	t.Parallel()
+	inv := strategy.NewInventory("cond-1", "yes", "no")
+	inv.SetPosition(strategy.Position{YesQty: 10, AvgEntryYes: 0.40, LastUpdated: time.Unix(1_700_000_000, 0)})
+	info := testMarket()
+	info.LastTradePrice = 0.55
+
+	ex := &fakeExchange{balances: map[string]float64{"yes": 15}}
+	report, err := Market(context.Background(), ex, info, inv, Options{})
+	if err != nil {
+		t.Fatalf("Market: %v", err)
+	}
+	if len(report.Seeded) != 1 || report.Seeded[0].Qty != 5 || report.Seeded[0].Source != "mark" {
+		t.Errorf("seeded = %+v, want the 5 extra at the mark", report.Seeded)
+	}
+	if pos := inv.Snapshot(); pos.YesQty != 15 || !approx(pos.AvgEntryYes, (10*0.40+5*0.55)/15) {
+		t.Errorf("position = %+v", pos)
+	}
+}
+
+func TestMarketReturnsExchangeErrors(t *testing.T) {
+	t.Parallel()
+	inv := strategy.NewInventory("cond-1", "yes", "no")
+	ex := &fakeExchange{err: errors.New("unavailable")}
+	if _, err := Market(context.Background(), ex, testMarket(), inv, Options{}); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -5,6 +5,7 @@
 //
 //   - Per-market exposure:  caps USD exposure in any single market
 //   - Global exposure:      caps total USD exposure across all markets
+//   - Event exposure:       caps USD exposure summed over a neg-risk event's outcomes
 //   - Daily loss:           triggers kill switch if realized+unrealized PnL exceeds threshold
 //   - Rapid price movement: triggers kill switch if mid-price moves more than
 //     KillSwitchDropPct within KillSwitchWindowSec seconds
@@ -65,9 +66,12 @@ type Manager struct {
 	killSwitchActive bool                      // true while in cooldown
 	killSwitchUntil  time.Time                 // when cooldown expires
 	priceAnchors     map[string]priceAnchor    // reference prices for movement detection
+	events           map[string]string         // marketID -> neg-risk event ID
 
 	reportCh chan PositionReport // strategy goroutines write here
 	killCh   chan KillSignal     // engine reads kill signals from here
+
+	now func() time.Time // clock (replaced by the backtester)
 }
 
 // NewManager creates a risk manager.
@@ -77,8 +81,31 @@ func NewManager(cfg config.RiskConfig, logger *slog.Logger) *Manager {
 		logger:       logger.With("component", "risk"),
 		positions:    make(map[string]PositionReport),
 		priceAnchors: make(map[string]priceAnchor),
+		events:       make(map[string]string),
 		reportCh:     make(chan PositionReport, 100),
 		killCh:       make(chan KillSignal, 10),
+		now:          time.Now,
+	}
+}
+
+// SetClock replaces the time source used for kill switch cooldowns.
+func (rm *Manager) SetClock(now func() time.Time) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.now = now
+}
+
+// Flush synchronously processes all queued position reports. The backtester
+// calls it after each simulated quote cycle in place of the Run loop.
+func (rm *Manager) Flush() {
+	for {
+		select {
+		case report := <-rm.reportCh:
+			rm.processReport(report)
+		default:
+			rm.clearExpiredKillSwitch()
+			return
+		}
 	}
 }
 
@@ -115,6 +142,14 @@ func (rm *Manager) KillCh() <-chan KillSignal {
 	return rm.killCh
 }
 
+// SetEvent records that a market is an outcome of a neg-risk event, so its
+// exposure counts towards the event's MaxEventExposure.
+func (rm *Manager) SetEvent(marketID, eventID string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.events[marketID] = eventID
+}
+
 // RemoveMarket cleans up state for a stopped market.
 func (rm *Manager) RemoveMarket(marketID string) {
 	rm.mu.Lock()
@@ -122,9 +157,21 @@ func (rm *Manager) RemoveMarket(marketID string) {
 
 	delete(rm.positions, marketID)
 	delete(rm.priceAnchors, marketID)
+	delete(rm.events, marketID)
 	rm.recomputeTotalsLocked()
 }
 
+// eventExposureLocked sums the exposure of every market in an event.
+func (rm *Manager) eventExposureLocked(eventID string) float64 {
+	var total float64
+	for marketID, ev := range rm.events {
+		if ev == eventID {
+			total += rm.positions[marketID].ExposureUSD
+		}
+	}
+	return total
+}
+
 // IsKillSwitchActive returns whether the kill switch is engaged.
 func (rm *Manager) IsKillSwitchActive() bool {
 	rm.mu.Lock()
@@ -133,7 +180,7 @@ func (rm *Manager) IsKillSwitchActive() bool {
 	if !rm.killSwitchActive {
 		return false
 	}
-	if time.Now().After(rm.killSwitchUntil) {
+	if rm.now().After(rm.killSwitchUntil) {
 		rm.killSwitchActive = false
 		rm.logger.Info("kill switch cooldown expired")
 		return false
@@ -145,6 +192,8 @@ func (rm *Manager) IsKillSwitchActive() bool {
 // the given market. It takes the minimum of:
 //   - per-market headroom: MaxPositionPerMarket − current market exposure
 //   - global headroom:     MaxGlobalExposure − total exposure across all markets
+//   - event headroom:      MaxEventExposure − exposure across the market's
+//     event, for outcomes of a neg-risk event when the limit is set
 //
 // Returns 0 if either limit is already exceeded (the strategy will skip quoting).
 func (rm *Manager) RemainingBudget(marketID string) float64 {
@@ -163,6 +212,11 @@ func (rm *Manager) RemainingBudget(marketID string) float64 {
 	if global < remaining {
 		remaining = global
 	}
+	if eventID, ok := rm.events[marketID]; ok && rm.cfg.MaxEventExposure > 0 {
+		if event := rm.cfg.MaxEventExposure - rm.eventExposureLocked(eventID); event < remaining {
+			remaining = event
+		}
+	}
 	if remaining < 0 {
 		return 0
 	}
@@ -239,6 +293,16 @@ func (rm *Manager) processReport(report PositionReport) {
 		rm.emitKill("", "global exposure limit breached")
 	}
 
+	// Check the event limit: every outcome of the event is stopped
+	if eventID, ok := rm.events[report.MarketID]; ok && rm.cfg.MaxEventExposure > 0 &&
+		rm.eventExposureLocked(eventID) > rm.cfg.MaxEventExposure {
+		for marketID, ev := range rm.events {
+			if ev == eventID {
+				rm.emitKill(marketID, "event exposure limit breached")
+			}
+		}
+	}
+
 	// Check daily loss
 	totalPnL := rm.totalRealizedPnL + totalUnrealizedPnL
 	if totalPnL < -rm.cfg.MaxDailyLoss {
@@ -300,7 +364,7 @@ func (rm *Manager) clearExpiredKillSwitch() {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
-	if rm.killSwitchActive && time.Now().After(rm.killSwitchUntil) {
+	if rm.killSwitchActive && rm.now().After(rm.killSwitchUntil) {
 		rm.killSwitchActive = false
 		rm.logger.Info("kill switch cooldown expired")
 	}
@@ -311,7 +375,7 @@ func (rm *Manager) clearExpiredKillSwitch() {
 // stale signal first to ensure the latest kill reason is always delivered.
 func (rm *Manager) emitKill(marketID, reason string) {
 	rm.killSwitchActive = true
-	rm.killSwitchUntil = time.Now().Add(rm.cfg.CooldownAfterKill)
+	rm.killSwitchUntil = rm.now().Add(rm.cfg.CooldownAfterKill)
 
 	rm.logger.Error("KILL SWITCH",
 		"market", marketID,
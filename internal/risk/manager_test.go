@@ -286,3 +286,34 @@ func TestRemoveMarketRecomputesTotals(t *testing.T) {
 		t.Fatalf("totalRealizedPnL after remove = %v, want 5", got)
 	}
 }
+
+func TestEventExposureLimit(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := testRiskConfig()
+	cfg.MaxEventExposure = 120
+	rm := NewManager(cfg, logger)
+	rm.SetEvent("a", "ev")
+	rm.SetEvent("b", "ev")
+
+	rm.processReport(PositionReport{MarketID: "a", ExposureUSD: 70, MidPrice: 0.3, Timestamp: time.Now()})
+	rm.processReport(PositionReport{MarketID: "other", ExposureUSD: 70, MidPrice: 0.3, Timestamp: time.Now()})
+
+	// b has its own 100 of headroom but only 50 left in the event
+	if got := rm.RemainingBudget("b"); got != 50 {
+		t.Errorf("event-constrained remaining = %v, want 50", got)
+	}
+	if got := rm.RemainingBudget("other"); got != 30 {
+		t.Errorf("market outside the event: remaining = %v, want 30", got)
+	}
+
+	// Breaching the event limit kills every outcome of the event
+	rm.processReport(PositionReport{MarketID: "b", ExposureUSD: 60, MidPrice: 0.3, Timestamp: time.Now()})
+	killed := map[string]bool{}
+	for len(rm.killCh) > 0 {
+		killed[(<-rm.killCh).MarketID] = true
+	}
+	if !killed["a"] || !killed["b"] || killed["other"] {
+		t.Errorf("killed = %v, want a and b", killed)
+	}
+}
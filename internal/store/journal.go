@@ -0,0 +1,277 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+// The order journal lives under <data_dir>/journal/<YYYY-MM-DD>.jsonl, one
+// JournalEntry per line, rotated by UTC day of the entry time. Files are only
+// ever appended to, so they double as an audit trail for post-mortems and
+// for reconciling realized PnL against the exchange's trade history.
+const journalDir = "journal"
+
+// Fsync policies for the journal.
+const (
+	FsyncAlways   = "always"   // fsync after every entry
+	FsyncInterval = "interval" // fsync in the background every interval (default)
+	FsyncNever    = "never"    // leave flushing to the OS
+)
+
+// defaultFsyncInterval bounds how much of the journal a power loss can take
+// with the interval policy.
+const defaultFsyncInterval = time.Second
+
+// Journal appends order lifecycle entries to daily JSONL files. Writes go
+// straight to the file (no user-space buffer), so a process crash loses
+// nothing; the fsync policy decides what survives a machine crash.
+// Journal is safe for concurrent use.
+type Journal struct {
+	dir    string
+	policy string
+
+	mu    sync.Mutex
+	day   string   // UTC day of the open file
+	file  *os.File // nil until the first entry
+	dirty bool     // written since the last fsync
+
+	logger *slog.Logger
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// OpenJournal opens the journal under <dataDir>/journal. interval is only
+// used by the "interval" policy; zero means one second.
+func OpenJournal(dataDir, policy string, interval time.Duration, logger *slog.Logger) (*Journal, error) {
+	switch policy {
+	case "":
+		policy = FsyncInterval
+	case FsyncAlways, FsyncInterval, FsyncNever:
+	default:
+		return nil, fmt.Errorf("unsupported journal fsync policy %q (use %q, %q or %q)", policy, FsyncAlways, FsyncInterval, FsyncNever)
+	}
+	if interval <= 0 {
+		interval = defaultFsyncInterval
+	}
+
+	dir := filepath.Join(dataDir, journalDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create journal dir: %w", err)
+	}
+
+	j := &Journal{
+		dir:    dir,
+		policy: policy,
+		logger: logger.With("component", "journal"),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if policy == FsyncInterval {
+		go j.syncLoop(interval)
+	} else {
+		close(j.done)
+	}
+	return j, nil
+}
+
+// Record appends one entry. Errors are logged rather than returned so the
+// trading path never stalls on the journal.
+func (j *Journal) Record(entry types.JournalEntry) {
+	if err := j.Append(entry); err != nil {
+		j.logger.Error("journal write failed", "type", entry.Type, "order_id", entry.OrderID, "error", err)
+	}
+}
+
+// Append writes one entry, rotating to a new file when the UTC day changes.
+func (j *Journal) Append(entry types.JournalEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal journal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	day := entry.Time.UTC().Format(time.DateOnly)
+	if j.file == nil || j.day != day {
+		if err := j.rotateLocked(day); err != nil {
+			return err
+		}
+	}
+	if _, err := j.file.Write(line); err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+
+	if j.policy == FsyncAlways {
+		return j.file.Sync()
+	}
+	j.dirty = true
+	return nil
+}
+
+// rotateLocked syncs and closes the current file and opens the one for day.
+// Must be called with lock held.
+func (j *Journal) rotateLocked(day string) error {
+	if j.file != nil {
+		j.syncLocked()
+		j.file.Close()
+		j.file = nil
+	}
+	path := filepath.Join(j.dir, day+".jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	if err := terminateTornLine(f); err != nil {
+		f.Close()
+		return fmt.Errorf("repair journal: %w", err)
+	}
+	j.file, j.day = f, day
+	return nil
+}
+
+// terminateTornLine appends a newline if a crash left the file ending
+// mid-entry, so the next entry isn't glued onto the torn one.
+func terminateTornLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	_, err = f.Write([]byte{'\n'})
+	return err
+}
+
+func (j *Journal) syncLocked() {
+	if j.file == nil || !j.dirty {
+		return
+	}
+	if err := j.file.Sync(); err != nil {
+		j.logger.Error("journal fsync failed", "error", err)
+		return
+	}
+	j.dirty = false
+}
+
+func (j *Journal) syncLoop(interval time.Duration) {
+	defer close(j.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.stop:
+			return
+		case <-ticker.C:
+			j.mu.Lock()
+			j.syncLocked()
+			j.mu.Unlock()
+		}
+	}
+}
+
+// Query returns entries for market (all markets if empty) with from <= Time
+// < to, in file order. A zero from or to leaves that end unbounded.
+func (j *Journal) Query(market string, from, to time.Time) ([]types.JournalEntry, error) {
+	return QueryJournal(filepath.Dir(j.dir), market, from, to)
+}
+
+// Close syncs and closes the journal.
+func (j *Journal) Close() error {
+	var err error
+	j.closeOnce.Do(func() {
+		close(j.stop)
+		<-j.done
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if j.file != nil {
+			j.dirty = true
+			j.syncLocked()
+			err = j.file.Close()
+			j.file = nil
+		}
+	})
+	return err
+}
+
+// QueryJournal reads journal entries from <dataDir>/journal without needing
+// an open Journal, for offline tools. See Journal.Query for the filters.
+func QueryJournal(dataDir, market string, from, to time.Time) ([]types.JournalEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dataDir, journalDir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var out []types.JournalEntry
+	for _, path := range paths {
+		day, err := time.Parse(time.DateOnly, strings.TrimSuffix(filepath.Base(path), ".jsonl"))
+		if err != nil {
+			continue
+		}
+		// Skip whole days outside the range
+		if !to.IsZero() && !day.Before(to) || !from.IsZero() && !day.Add(24*time.Hour).After(from) {
+			continue
+		}
+
+		entries, err := readJournalFile(path, market, from, to)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, entries...)
+	}
+	return out, nil
+}
+
+func readJournalFile(path, market string, from, to time.Time) ([]types.JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer f.Close()
+
+	var out []types.JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry types.JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A crash mid-write can leave a torn last line; skip it
+			continue
+		}
+		if market != "" && entry.Market != market {
+			continue
+		}
+		if !from.IsZero() && entry.Time.Before(from) || !to.IsZero() && !entry.Time.Before(to) {
+			continue
+		}
+		out = append(out, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal %s: %w", path, err)
+	}
+	return out, nil
+}
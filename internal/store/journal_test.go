@@ -0,0 +1,118 @@
+package store
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestJournalAppendAndQuery(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	j, err := OpenJournal(dir, FsyncAlways, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+
+	day1 := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+	entries := []types.JournalEntry{
+		{Time: day1, Type: types.JournalPlacement, Market: "mkt1", TokenID: "yes", Side: types.BUY, Price: 0.45, Size: 10},
+		{Time: day1.Add(time.Second), Type: types.JournalAck, Market: "mkt1", OrderID: "o1", Status: "live"},
+		{Time: day1.Add(2 * time.Second), Type: types.JournalAck, Market: "mkt2", OrderID: "o2"},
+		{Time: day2, Type: types.JournalTrade, Market: "mkt1", TradeID: "t1", Price: 0.45, Size: 4},
+		{Time: day2.Add(time.Second), Type: types.JournalCancel, OrderID: "o2", Reason: "shutdown"},
+	}
+	for _, e := range entries {
+		if err := j.Append(e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Entries rotate by UTC day
+	for _, day := range []string{"2025-06-01", "2025-06-02"} {
+		if _, err := os.Stat(filepath.Join(dir, "journal", day+".jsonl")); err != nil {
+			t.Errorf("missing journal file for %s: %v", day, err)
+		}
+	}
+
+	all, err := QueryJournal(dir, "", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("QueryJournal: %v", err)
+	}
+	if len(all) != len(entries) {
+		t.Fatalf("got %d entries, want %d", len(all), len(entries))
+	}
+
+	mkt1, err := j.Query("mkt1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(mkt1) != 3 || mkt1[2].Type != types.JournalTrade || mkt1[2].TradeID != "t1" {
+		t.Errorf("mkt1 entries = %+v", mkt1)
+	}
+
+	// Half-open range [from, to)
+	ranged, err := j.Query("", day1.Add(time.Second), day2)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(ranged) != 2 || ranged[0].OrderID != "o1" || ranged[1].OrderID != "o2" {
+		t.Errorf("ranged entries = %+v", ranged)
+	}
+}
+
+func TestJournalSkipsTornLineAndAppendsAcrossOpens(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ts := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	j, err := OpenJournal(dir, FsyncInterval, 10*time.Millisecond, logger)
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	j.Record(types.JournalEntry{Time: ts, Type: types.JournalAck, Market: "m", OrderID: "o1"})
+	j.Close()
+
+	// Simulate a crash mid-write
+	path := filepath.Join(dir, "journal", "2025-06-01.jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2025-06-01T12:00:01Z","type":"ack`)
+	f.Close()
+
+	j, err = OpenJournal(dir, FsyncNever, 0, logger)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	// The torn line is terminated so the next entry survives
+	j.Record(types.JournalEntry{Time: ts.Add(2 * time.Second), Type: types.JournalCancel, Market: "m", OrderID: "o1"})
+	j.Close()
+
+	got, err := QueryJournal(dir, "m", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("QueryJournal: %v", err)
+	}
+	if len(got) != 2 || got[0].Type != types.JournalAck || got[1].Type != types.JournalCancel {
+		t.Errorf("entries = %+v, want the ack and cancel around the torn line", got)
+	}
+}
+
+func TestOpenJournalRejectsUnknownPolicy(t *testing.T) {
+	t.Parallel()
+	if _, err := OpenJournal(t.TempDir(), "sometimes", 0, slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+		t.Error("expected error for unknown fsync policy")
+	}
+}
@@ -0,0 +1,594 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"polymarket-mm/pkg/types"
+)
+
+// Recorded feeds live under <data_dir>/recordings/<YYYY-MM-DD>/<market>.jsonl[.gz|.zst].
+// Each line is a Frame: one WS event as received, tagged with the channel and
+// the local receive time. Array frames are split so every line holds exactly
+// one event and lands in the file of the market it belongs to.
+//
+// A restart on the same day starts a new segment, <market>.<n>.jsonl[.gz|.zst],
+// rather than appending: after a crash the previous segment may end in a
+// truncated gzip member or zstd frame, or a torn line, and anything appended behind that
+// would be unreadable. Readers drop a torn tail and move on to the next
+// segment.
+const (
+	recordingsDir    = "recordings"
+	recorderBuffer   = 4096            // frames queued before the recorder starts dropping
+	recorderFlushInt = 5 * time.Second // bound on data lost to a crash
+	unknownMarket    = "_unknown"      // file for events with no market field
+)
+
+// Compression modes for recorded files. zstd compresses feed JSON better
+// and faster than gzip; gzip stays the default because any tool can read it.
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+	CompressionNone = "none"
+)
+
+// Frame is one recorded WS event.
+type Frame struct {
+	RecvNs  int64           `json:"recv_ns"` // local receive time, unix nanoseconds
+	Channel string          `json:"channel"` // "market" or "user"
+	Data    json.RawMessage `json:"data"`    // raw event JSON exactly as received
+}
+
+// RecvTime returns the local receive time of the frame.
+func (f Frame) RecvTime() time.Time { return time.Unix(0, f.RecvNs) }
+
+type pendingFrame struct {
+	channel string
+	recv    time.Time
+	data    []byte
+}
+
+// compressor is the part of gzip.Writer and zstd.Encoder the recorder uses.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// recordingFile is one open per-day, per-market output file.
+type recordingFile struct {
+	day  string
+	file *os.File
+	enc  compressor // nil when uncompressed
+	buf  *bufio.Writer
+}
+
+func (rf *recordingFile) flush() error {
+	if err := rf.buf.Flush(); err != nil {
+		return err
+	}
+	if rf.enc != nil {
+		return rf.enc.Flush()
+	}
+	return nil
+}
+
+func (rf *recordingFile) close() error {
+	err := rf.buf.Flush()
+	if rf.enc != nil {
+		if cerr := rf.enc.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := rf.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Recorder tees raw WS frames to rotated JSONL files. RecordFrame never
+// blocks the feed: frames are queued to a writer goroutine and dropped
+// (and counted) if the queue is full.
+type Recorder struct {
+	dir         string
+	compression string
+	frames      chan pendingFrame
+	files       map[string]*recordingFile // market → open file, owned by run
+	dropped     atomic.Int64
+	logger      *slog.Logger
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewRecorder creates a recorder writing under <dataDir>/recordings and
+// starts its writer goroutine. Call Close to flush and release files.
+func NewRecorder(dataDir, compression string, logger *slog.Logger) (*Recorder, error) {
+	switch compression {
+	case "":
+		compression = CompressionGzip
+	case CompressionGzip, CompressionZstd, CompressionNone:
+	default:
+		return nil, fmt.Errorf("unsupported recorder compression %q (use %q, %q or %q)", compression, CompressionGzip, CompressionZstd, CompressionNone)
+	}
+
+	dir := filepath.Join(dataDir, recordingsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create recordings dir: %w", err)
+	}
+
+	r := &Recorder{
+		dir:         dir,
+		compression: compression,
+		frames:      make(chan pendingFrame, recorderBuffer),
+		files:       make(map[string]*recordingFile),
+		logger:      logger.With("component", "recorder"),
+		done:        make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// RecordFrame queues a raw frame received on the given channel. The data
+// slice is retained, so callers must not reuse it.
+func (r *Recorder) RecordFrame(channel string, recv time.Time, data []byte) {
+	select {
+	case r.frames <- pendingFrame{channel: channel, recv: recv, data: data}:
+	default:
+		if r.dropped.Add(1)%1000 == 1 {
+			r.logger.Warn("recorder queue full, dropping frames", "dropped_total", r.dropped.Load())
+		}
+	}
+}
+
+// Dropped returns how many frames were discarded because the queue was full.
+func (r *Recorder) Dropped() int64 { return r.dropped.Load() }
+
+// Close drains queued frames, flushes, and closes all open files.
+// RecordFrame must not be called after Close.
+func (r *Recorder) Close() error {
+	r.closeOnce.Do(func() { close(r.frames) })
+	<-r.done
+	return nil
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(recorderFlushInt)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case f, ok := <-r.frames:
+			if !ok {
+				r.closeAll()
+				return
+			}
+			r.write(f)
+		case <-ticker.C:
+			for market, rf := range r.files {
+				if err := rf.flush(); err != nil {
+					r.logger.Error("flush recording", "market", market, "error", err)
+				}
+			}
+		}
+	}
+}
+
+func (r *Recorder) closeAll() {
+	for market, rf := range r.files {
+		if err := rf.close(); err != nil {
+			r.logger.Error("close recording", "market", market, "error", err)
+		}
+		delete(r.files, market)
+	}
+}
+
+// write splits a frame into single events and appends each to its market file.
+func (r *Recorder) write(f pendingFrame) {
+	data := bytes.TrimSpace(f.data)
+	if len(data) == 0 || (data[0] != '{' && data[0] != '[') {
+		return // PONG and other non-JSON keepalives
+	}
+
+	events := []json.RawMessage{data}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &events); err != nil {
+			r.logger.Debug("recorder: bad array frame", "error", err)
+			return
+		}
+	}
+
+	day := f.recv.UTC().Format(time.DateOnly)
+	for _, raw := range events {
+		var envelope struct {
+			Market string `json:"market"`
+		}
+		_ = json.Unmarshal(raw, &envelope)
+		market := sanitizeMarket(envelope.Market)
+
+		line, err := json.Marshal(Frame{RecvNs: f.recv.UnixNano(), Channel: f.channel, Data: raw})
+		if err != nil {
+			r.logger.Debug("recorder: marshal frame", "error", err)
+			continue
+		}
+
+		rf, err := r.fileFor(market, day)
+		if err != nil {
+			r.logger.Error("open recording", "market", market, "error", err)
+			continue
+		}
+		rf.buf.Write(line)
+		rf.buf.WriteByte('\n')
+	}
+}
+
+// fileFor returns the open file for a market, rotating when the UTC day changes.
+func (r *Recorder) fileFor(market, day string) (*recordingFile, error) {
+	if rf, ok := r.files[market]; ok {
+		if rf.day == day {
+			return rf, nil
+		}
+		if err := rf.close(); err != nil {
+			r.logger.Error("close recording", "market", market, "error", err)
+		}
+		delete(r.files, market)
+	}
+
+	dayDir := filepath.Join(r.dir, day)
+	if err := os.MkdirAll(dayDir, 0o755); err != nil {
+		return nil, err
+	}
+	file, err := createSegment(dayDir, market, recordingExt(r.compression))
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &recordingFile{day: day, file: file}
+	var w io.Writer = file
+	switch r.compression {
+	case CompressionGzip:
+		rf.enc = gzip.NewWriter(file)
+		w = rf.enc
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(file, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		rf.enc = enc
+		w = enc
+	}
+	rf.buf = bufio.NewWriterSize(w, 64*1024)
+	r.files[market] = rf
+	return rf, nil
+}
+
+// createSegment creates the first unused segment of a market's day file:
+// <market><ext>, then <market>.1<ext>, <market>.2<ext>, ...
+func createSegment(dayDir, market, ext string) (*os.File, error) {
+	for n := 0; ; n++ {
+		name := market + ext
+		if n > 0 {
+			name = fmt.Sprintf("%s.%d%s", market, n, ext)
+		}
+		file, err := os.OpenFile(filepath.Join(dayDir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		return file, err
+	}
+}
+
+// segmentIndex parses a file name as a segment of market's day file,
+// returning its position (0 for the first segment).
+func segmentIndex(fileName, market string) (int, bool) {
+	for _, ext := range []string{".jsonl.gz", ".jsonl.zst", ".jsonl"} {
+		rest, ok := strings.CutSuffix(fileName, ext)
+		if !ok {
+			continue
+		}
+		if rest == market {
+			return 0, true
+		}
+		num, ok := strings.CutPrefix(rest, market+".")
+		if !ok {
+			return 0, false
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil || n < 1 {
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+func recordingExt(compression string) string {
+	switch compression {
+	case CompressionGzip:
+		return ".jsonl.gz"
+	case CompressionZstd:
+		return ".jsonl.zst"
+	}
+	return ".jsonl"
+}
+
+// sanitizeMarket makes a condition ID safe to use as a file name.
+func sanitizeMarket(market string) string {
+	if market == "" {
+		return unknownMarket
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, market)
+}
+
+// RecordingFiles lists recorded files for a market within [from, to] (by
+// UTC day, inclusive), oldest first: by day, then by segment. Zero times
+// leave that end unbounded.
+func RecordingFiles(dataDir, market string, from, to time.Time) ([]string, error) {
+	root := filepath.Join(dataDir, recordingsDir)
+	days, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read recordings dir: %w", err)
+	}
+
+	var fromDay, toDay string
+	if !from.IsZero() {
+		fromDay = from.UTC().Format(time.DateOnly)
+	}
+	if !to.IsZero() {
+		toDay = to.UTC().Format(time.DateOnly)
+	}
+
+	name := sanitizeMarket(market)
+	var paths []string
+	for _, d := range days {
+		if !d.IsDir() {
+			continue
+		}
+		day := d.Name()
+		if (fromDay != "" && day < fromDay) || (toDay != "" && day > toDay) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(root, day))
+		if err != nil {
+			return nil, fmt.Errorf("read recordings for %s: %w", day, err)
+		}
+		type segment struct {
+			n    int
+			path string
+		}
+		var segments []segment
+		for _, f := range files {
+			if n, ok := segmentIndex(f.Name(), name); ok && !f.IsDir() {
+				segments = append(segments, segment{n, filepath.Join(root, day, f.Name())})
+			}
+		}
+		sort.Slice(segments, func(i, j int) bool {
+			if segments[i].n != segments[j].n {
+				return segments[i].n < segments[j].n
+			}
+			return segments[i].path < segments[j].path
+		})
+		for _, s := range segments {
+			paths = append(paths, s.path)
+		}
+	}
+	return paths, nil
+}
+
+// RecordedEvent is a decoded recorded frame. Exactly one of the typed
+// event fields is set for known event types; Raw always holds the original
+// JSON so unknown types can still be inspected.
+type RecordedEvent struct {
+	RecvTime  time.Time
+	Channel   string
+	EventType string
+	Raw       json.RawMessage
+
+	Book        *types.WSBookEvent
+	PriceChange *types.WSPriceChangeEvent
+	LastTrade   *types.WSLastTradePriceEvent
+	Trade       *types.WSTradeEvent
+	Order       *types.WSOrderEvent
+}
+
+// RecordingReader reads recorded frames back from one or more files in order.
+type RecordingReader struct {
+	paths   []string
+	file    *os.File
+	dec     func() // releases the decompressor, if any
+	tail    *tornTailReader
+	scanner *bufio.Scanner
+}
+
+// tornTailReader notes whether a file ends the way a crash leaves it: a
+// truncated gzip member or zstd frame, or a last line without its newline. The truncation
+// is reported as a clean EOF so the frames before it can still be read.
+type tornTailReader struct {
+	r    io.Reader
+	last byte
+	torn bool
+}
+
+func (t *tornTailReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		t.last = p[n-1]
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		t.torn = true
+		err = io.EOF
+	}
+	if err == io.EOF && t.last != 0 && t.last != '\n' {
+		t.torn = true
+	}
+	return n, err
+}
+
+// OpenRecording returns a reader over the given files, read sequentially.
+// Pass the result of RecordingFiles to replay a market across days.
+func OpenRecording(paths ...string) *RecordingReader {
+	return &RecordingReader{paths: paths}
+}
+
+// Next returns the next recorded event, or io.EOF when all files are exhausted.
+func (rr *RecordingReader) Next() (RecordedEvent, error) {
+	for {
+		if rr.scanner == nil {
+			if len(rr.paths) == 0 {
+				return RecordedEvent{}, io.EOF
+			}
+			if err := rr.openNext(); err != nil {
+				return RecordedEvent{}, err
+			}
+		}
+
+		if rr.scanner.Scan() {
+			line := bytes.TrimSpace(rr.scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			evt, err := decodeFrame(line)
+			if err != nil && rr.tail.torn && !rr.scanner.Scan() {
+				// The torn last line of a crashed segment
+				rr.closeCurrent()
+				continue
+			}
+			return evt, err
+		}
+		if err := rr.scanner.Err(); err != nil {
+			return RecordedEvent{}, fmt.Errorf("read recording: %w", err)
+		}
+		rr.closeCurrent()
+	}
+}
+
+// Close releases the file currently being read.
+func (rr *RecordingReader) Close() error {
+	rr.closeCurrent()
+	rr.paths = nil
+	return nil
+}
+
+func (rr *RecordingReader) openNext() error {
+	path := rr.paths[0]
+	rr.paths = rr.paths[1:]
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open recording: %w", err)
+	}
+	var src io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			// Crashed before the first flush: nothing was recorded
+			src = bytes.NewReader(nil)
+		case err != nil:
+			f.Close()
+			return fmt.Errorf("open gzip %s: %w", path, err)
+		default:
+			rr.dec = func() { gz.Close() }
+			src = gz
+		}
+	}
+	if strings.HasSuffix(path, ".zst") {
+		zr, err := zstd.NewReader(f, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("open zstd %s: %w", path, err)
+		}
+		rr.dec = zr.Close
+		src = zr
+	}
+	rr.file = f
+	rr.tail = &tornTailReader{r: src}
+	rr.scanner = bufio.NewScanner(rr.tail)
+	rr.scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return nil
+}
+
+func (rr *RecordingReader) closeCurrent() {
+	if rr.dec != nil {
+		rr.dec()
+		rr.dec = nil
+	}
+	if rr.file != nil {
+		rr.file.Close()
+		rr.file = nil
+	}
+	rr.tail = nil
+	rr.scanner = nil
+}
+
+func decodeFrame(line []byte) (RecordedEvent, error) {
+	var frame Frame
+	if err := json.Unmarshal(line, &frame); err != nil {
+		return RecordedEvent{}, fmt.Errorf("unmarshal frame: %w", err)
+	}
+
+	var envelope struct {
+		EventType string `json:"event_type"`
+	}
+	if err := json.Unmarshal(frame.Data, &envelope); err != nil {
+		return RecordedEvent{}, fmt.Errorf("unmarshal event envelope: %w", err)
+	}
+
+	evt := RecordedEvent{
+		RecvTime:  frame.RecvTime(),
+		Channel:   frame.Channel,
+		EventType: envelope.EventType,
+		Raw:       frame.Data,
+	}
+
+	var target any
+	switch envelope.EventType {
+	case "book":
+		evt.Book = &types.WSBookEvent{}
+		target = evt.Book
+	case "price_change":
+		evt.PriceChange = &types.WSPriceChangeEvent{}
+		target = evt.PriceChange
+	case "last_trade_price":
+		evt.LastTrade = &types.WSLastTradePriceEvent{}
+		target = evt.LastTrade
+	case "trade":
+		evt.Trade = &types.WSTradeEvent{}
+		target = evt.Trade
+	case "order":
+		evt.Order = &types.WSOrderEvent{}
+		target = evt.Order
+	default:
+		return evt, nil
+	}
+	if err := json.Unmarshal(frame.Data, target); err != nil {
+		return RecordedEvent{}, fmt.Errorf("unmarshal %s event: %w", envelope.EventType, err)
+	}
+	return evt, nil
+}
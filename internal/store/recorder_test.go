@@ -0,0 +1,225 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func readAll(t *testing.T, paths ...string) []RecordedEvent {
+	t.Helper()
+	rr := OpenRecording(paths...)
+	defer rr.Close()
+
+	var events []RecordedEvent
+	for {
+		evt, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		events = append(events, evt)
+	}
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(dir, CompressionGzip, testLogger())
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec.RecordFrame("market", t0, []byte(`[{"event_type":"book","asset_id":"y","market":"m1","buys":[{"price":"0.4","size":"10"}],"sells":[]},{"event_type":"book","asset_id":"z","market":"m2","buys":[],"sells":[]}]`))
+	rec.RecordFrame("market", t0.Add(time.Second), []byte(`PONG`))
+	rec.RecordFrame("market", t0.Add(2*time.Second), []byte(`{"event_type":"last_trade_price","asset_id":"y","market":"m1","price":"0.41","size":"5","side":"BUY"}`))
+	rec.RecordFrame("user", t0.Add(3*time.Second), []byte(`{"event_type":"trade","id":"t1","market":"m1","asset_id":"y","side":"BUY","size":"5","price":"0.41"}`))
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	paths, err := RecordingFiles(dir, "m1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("RecordingFiles: %v", err)
+	}
+	if len(paths) != 1 || filepath.Base(paths[0]) != "m1.jsonl.gz" {
+		t.Fatalf("paths = %v", paths)
+	}
+
+	events := readAll(t, paths...)
+	if len(events) != 3 {
+		t.Fatalf("got %d events for m1, want 3", len(events))
+	}
+	if events[0].Book == nil || events[0].Book.AssetID != "y" || !events[0].RecvTime.Equal(t0) {
+		t.Errorf("event 0 = %+v", events[0])
+	}
+	if events[1].LastTrade == nil || events[1].LastTrade.Price != "0.41" {
+		t.Errorf("event 1 = %+v", events[1])
+	}
+	if events[2].Trade == nil || events[2].Channel != "user" || events[2].Trade.ID != "t1" {
+		t.Errorf("event 2 = %+v", events[2])
+	}
+
+	// The second market in the array frame went to its own file
+	if paths, _ := RecordingFiles(dir, "m2", time.Time{}, time.Time{}); len(paths) != 1 {
+		t.Errorf("m2 paths = %v", paths)
+	}
+}
+
+func TestRecorderRotatesDailyAndSegments(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+	frame := []byte(`{"event_type":"price_change","market":"m1","price_changes":[]}`)
+
+	// Two recorder sessions on the same day write two segments
+	for i := 0; i < 2; i++ {
+		rec, err := NewRecorder(dir, CompressionGzip, testLogger())
+		if err != nil {
+			t.Fatalf("NewRecorder: %v", err)
+		}
+		rec.RecordFrame("market", day1, frame)
+		if i == 1 {
+			rec.RecordFrame("market", day2, frame)
+		}
+		rec.Close()
+	}
+
+	paths, err := RecordingFiles(dir, "m1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("RecordingFiles: %v", err)
+	}
+	if len(paths) != 3 || filepath.Base(paths[0]) != "m1.jsonl.gz" || filepath.Base(paths[1]) != "m1.1.jsonl.gz" {
+		t.Fatalf("expected two segments on day 1 and one on day 2, got %v", paths)
+	}
+	if n := len(readAll(t, paths[:2]...)); n != 2 {
+		t.Errorf("day 1 events = %d, want 2 (one per session)", n)
+	}
+
+	only2, _ := RecordingFiles(dir, "m1", day2, time.Time{})
+	if len(only2) != 1 || only2[0] != paths[2] {
+		t.Errorf("from filter: got %v", only2)
+	}
+	if n := len(readAll(t, paths...)); n != 3 {
+		t.Errorf("total events = %d, want 3", n)
+	}
+}
+
+func TestRecordingSurvivesCrashedSegment(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	frame := []byte(`{"event_type":"price_change","market":"m1","price_changes":[]}`)
+
+	for _, compression := range []string{CompressionGzip, CompressionZstd, CompressionNone} {
+		dir := t.TempDir()
+		rec, err := NewRecorder(dir, compression, testLogger())
+		if err != nil {
+			t.Fatalf("NewRecorder: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			rec.RecordFrame("market", now, frame)
+		}
+		rec.Close()
+
+		// A crash cuts the first session's file mid-line
+		first := filepath.Join(dir, "recordings", "2026-03-01", "m1"+recordingExt(compression))
+		info, err := os.Stat(first)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if err := os.Truncate(first, info.Size()-10); err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+
+		// The restart records into a new segment, still readable after the torn one
+		rec, err = NewRecorder(dir, compression, testLogger())
+		if err != nil {
+			t.Fatalf("NewRecorder: %v", err)
+		}
+		rec.RecordFrame("market", now.Add(time.Minute), frame)
+		rec.Close()
+
+		paths, err := RecordingFiles(dir, "m1", time.Time{}, time.Time{})
+		if err != nil || len(paths) != 2 {
+			t.Fatalf("%s: paths = %v, %v", compression, paths, err)
+		}
+		// zstd decodes whole blocks, so the torn block goes with its tail
+		want := 2
+		if compression == CompressionZstd {
+			want = 1
+		}
+		events := readAll(t, paths...)
+		if len(events) < want || !events[len(events)-1].RecvTime.Equal(now.Add(time.Minute)) {
+			t.Errorf("%s: read %d events, want the restart's frame last", compression, len(events))
+		}
+	}
+}
+
+func TestZstdRecordingKeepsFlushedBlocks(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	frame := []byte(`{"event_type":"price_change","market":"m1","price_changes":[]}`)
+
+	// Drive the writer directly so the process can "die" between flushes
+	r := &Recorder{dir: filepath.Join(dir, recordingsDir), compression: CompressionZstd, files: make(map[string]*recordingFile), logger: testLogger()}
+	r.write(pendingFrame{channel: "market", recv: now, data: frame})
+	r.write(pendingFrame{channel: "market", recv: now.Add(time.Second), data: frame})
+	rf := r.files["m1"]
+	if err := rf.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	r.write(pendingFrame{channel: "market", recv: now.Add(2 * time.Second), data: frame})
+	rf.buf.Flush()
+	rf.file.Close() // the frame is never finished
+
+	paths, err := RecordingFiles(dir, "m1", time.Time{}, time.Time{})
+	if err != nil || len(paths) != 1 || filepath.Ext(paths[0]) != ".zst" {
+		t.Fatalf("paths = %v, %v", paths, err)
+	}
+	if n := len(readAll(t, paths...)); n != 2 {
+		t.Errorf("read %d events, want the 2 flushed before the crash", n)
+	}
+}
+
+func TestRecorderUncompressed(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(dir, CompressionNone, testLogger())
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rec.RecordFrame("market", now, []byte(`{"event_type":"tick_size_change"}`))
+	rec.Close()
+
+	path := filepath.Join(dir, "recordings", "2026-03-01", unknownMarket+".jsonl")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected %s: %v", path, err)
+	}
+	events := readAll(t, path)
+	if len(events) != 1 || events[0].EventType != "tick_size_change" || len(events[0].Raw) == 0 {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestNewRecorderRejectsUnknownCompression(t *testing.T) {
+	t.Parallel()
+	if _, err := NewRecorder(t.TempDir(), "lz4", testLogger()); err == nil {
+		t.Fatal("expected error for unsupported compression")
+	}
+}
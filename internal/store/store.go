@@ -2,9 +2,16 @@
 //
 // Each market's position is stored as a separate file: pos_<marketID>.json.
 // Writes use atomic file replacement (write to .tmp, then rename) to prevent
-// corruption from partial writes or crashes mid-save. The strategy layer
-// calls SavePosition after each fill, and LoadPosition on startup to restore
-// inventory state.
+// corruption from partial writes or crashes mid-save. The file holds the
+// position plus the ledger of trades booked into it (strategy.InventoryState),
+// so trade dedup survives restarts. The strategy layer calls SaveState after
+// each booked trade, and the engine calls LoadState on startup to restore
+// inventory state. Post-fill markout statistics are kept alongside in
+// markout_<marketID>.json.
+//
+// The package also holds the optional market data Recorder (recorder.go),
+// which tees raw WS frames to compressed JSONL files under the same data
+// directory for research and backtesting.
 package store
 
 import (
@@ -37,14 +44,14 @@ func (s *Store) Close() error {
 	return nil
 }
 
-// SavePosition atomically persists the current position for a market.
+// SaveState atomically persists a market's position and trade ledger.
 // It writes to a .tmp file first, then renames over the target to ensure
 // the file is never left in a partial state (crash-safe).
-func (s *Store) SavePosition(marketID string, pos strategy.Position) error {
+func (s *Store) SaveState(marketID string, state strategy.InventoryState) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	data, err := json.Marshal(pos)
+	data, err := json.Marshal(state)
 	if err != nil {
 		return fmt.Errorf("marshal position: %w", err)
 	}
@@ -57,9 +64,10 @@ func (s *Store) SavePosition(marketID string, pos strategy.Position) error {
 	return os.Rename(tmp, path)
 }
 
-// LoadPosition restores position for a market from disk.
+// LoadState restores a market's position and trade ledger from disk.
+// Files written before the ledger existed load with an empty ledger.
 // Returns nil, nil if no saved position exists (fresh market).
-func (s *Store) LoadPosition(marketID string) (*strategy.Position, error) {
+func (s *Store) LoadState(marketID string) (*strategy.InventoryState, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -72,9 +80,49 @@ func (s *Store) LoadPosition(marketID string) (*strategy.Position, error) {
 		return nil, fmt.Errorf("read position: %w", err)
 	}
 
-	var pos strategy.Position
-	if err := json.Unmarshal(data, &pos); err != nil {
+	var state strategy.InventoryState
+	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, fmt.Errorf("unmarshal position: %w", err)
 	}
-	return &pos, nil
+	return &state, nil
+}
+
+// SaveMarkouts atomically persists a market's markout statistics.
+func (s *Store) SaveMarkouts(marketID string, state strategy.MarkoutState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal markouts: %w", err)
+	}
+
+	path := filepath.Join(s.dir, "markout_"+marketID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write markouts: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadMarkouts restores a market's markout statistics from disk.
+// Returns nil, nil if none were saved.
+func (s *Store) LoadMarkouts(marketID string) (*strategy.MarkoutState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, "markout_"+marketID+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read markouts: %w", err)
+	}
+
+	var state strategy.MarkoutState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("unmarshal markouts: %w", err)
+	}
+	return &state, nil
 }
@@ -1,12 +1,16 @@
 package store
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"polymarket-mm/internal/strategy"
+	"polymarket-mm/pkg/types"
 )
 
-func TestSaveAndLoadPosition(t *testing.T) {
+func TestSaveAndLoadState(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
 
@@ -24,16 +28,16 @@ func TestSaveAndLoadPosition(t *testing.T) {
 		RealizedPnL: 1.23,
 	}
 
-	if err := s.SavePosition("mkt1", pos); err != nil {
-		t.Fatalf("SavePosition: %v", err)
+	if err := s.SaveState("mkt1", strategy.InventoryState{Position: pos}); err != nil {
+		t.Fatalf("SaveState: %v", err)
 	}
 
-	loaded, err := s.LoadPosition("mkt1")
+	loaded, err := s.LoadState("mkt1")
 	if err != nil {
-		t.Fatalf("LoadPosition: %v", err)
+		t.Fatalf("LoadState: %v", err)
 	}
 	if loaded == nil {
-		t.Fatal("LoadPosition returned nil")
+		t.Fatal("LoadState returned nil")
 	}
 
 	if loaded.YesQty != pos.YesQty {
@@ -47,7 +51,7 @@ func TestSaveAndLoadPosition(t *testing.T) {
 	}
 }
 
-func TestLoadPositionMissing(t *testing.T) {
+func TestLoadStateMissing(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
 
@@ -57,16 +61,16 @@ func TestLoadPositionMissing(t *testing.T) {
 	}
 	defer s.Close()
 
-	loaded, err := s.LoadPosition("nonexistent")
+	loaded, err := s.LoadState("nonexistent")
 	if err != nil {
-		t.Fatalf("LoadPosition: %v", err)
+		t.Fatalf("LoadState: %v", err)
 	}
 	if loaded != nil {
 		t.Errorf("expected nil for missing position, got %+v", loaded)
 	}
 }
 
-func TestSavePositionOverwrites(t *testing.T) {
+func TestSaveStateOverwrites(t *testing.T) {
 	t.Parallel()
 	dir := t.TempDir()
 
@@ -76,17 +80,84 @@ func TestSavePositionOverwrites(t *testing.T) {
 	}
 	defer s.Close()
 
-	pos1 := strategy.Position{YesQty: 10}
-	pos2 := strategy.Position{YesQty: 20}
+	state1 := strategy.InventoryState{Position: strategy.Position{YesQty: 10}}
+	state2 := strategy.InventoryState{Position: strategy.Position{YesQty: 20}}
 
-	_ = s.SavePosition("mkt1", pos1)
-	_ = s.SavePosition("mkt1", pos2)
+	_ = s.SaveState("mkt1", state1)
+	_ = s.SaveState("mkt1", state2)
 
-	loaded, err := s.LoadPosition("mkt1")
+	loaded, err := s.LoadState("mkt1")
 	if err != nil {
-		t.Fatalf("LoadPosition: %v", err)
+		t.Fatalf("LoadState: %v", err)
 	}
 	if loaded.YesQty != 20 {
 		t.Errorf("YesQty = %v, want 20 (latest save)", loaded.YesQty)
 	}
 }
+
+func TestSaveAndLoadStateKeepsTradeLedger(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	// Files written before the ledger existed still load
+	legacy := []byte(`{"yes_qty":5}`)
+	if err := os.WriteFile(filepath.Join(dir, "pos_mkt1.json"), legacy, 0o600); err != nil {
+		t.Fatalf("write legacy position: %v", err)
+	}
+	state, err := s.LoadState("mkt1")
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if state.YesQty != 5 || len(state.Trades) != 0 {
+		t.Errorf("state = %+v", state)
+	}
+
+	state.Trades = map[string]strategy.BookedTrade{
+		"t1": {Status: strategy.TradeMatched, Fills: []strategy.BookedFill{{Fill: strategy.Fill{TradeID: "t1", Size: 5}, EntryBefore: 0.4}}},
+	}
+	if err := s.SaveState("mkt1", *state); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+	loaded, err := s.LoadState("mkt1")
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	if got := loaded.Trades["t1"]; got.Status != strategy.TradeMatched || len(got.Fills) != 1 || got.Fills[0].EntryBefore != 0.4 {
+		t.Errorf("ledger entry = %+v", got)
+	}
+}
+
+func TestSaveAndLoadMarkouts(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	if loaded, err := s.LoadMarkouts("market-1"); err != nil || loaded != nil {
+		t.Fatalf("LoadMarkouts before save = %v, %v; want nil, nil", loaded, err)
+	}
+
+	state := strategy.MarkoutState{Stats: []strategy.MarkoutStat{
+		{Side: types.BUY, Horizon: 30 * time.Second, Count: 4, Size: 40, Sum: -0.4},
+	}}
+	if err := s.SaveMarkouts("market-1", state); err != nil {
+		t.Fatalf("SaveMarkouts: %v", err)
+	}
+	loaded, err := s.LoadMarkouts("market-1")
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadMarkouts: %v, %v", loaded, err)
+	}
+	if len(loaded.Stats) != 1 || loaded.Stats[0] != state.Stats[0] {
+		t.Errorf("loaded = %+v, want %+v", loaded.Stats, state.Stats)
+	}
+}
@@ -0,0 +1,147 @@
+package strategy
+
+import (
+	"math"
+
+	"polymarket-mm/pkg/types"
+)
+
+// Cross-book quoting.
+//
+// A binary market has two tokens whose prices sum to 1, so every economic
+// position can be expressed on either book:
+//
+//	buy YES  @ p  ==  sell NO @ 1-p   (economic bid)
+//	sell YES @ p  ==  buy NO  @ 1-p   (economic ask)
+//
+// Selling a token on Polymarket requires holding it. When QuoteBothTokens is
+// enabled the Maker computes its A-S quotes in YES terms from the synthetic
+// mid, then routes each side to whichever token gives the better resting
+// price. Sells are only candidates when we hold enough of the token, so a
+// flat book asks by buying NO rather than selling YES it doesn't have.
+
+// quoteCandidate is one way to express an economic quote side.
+type quoteCandidate struct {
+	order   types.UserOrder
+	edge    float64 // how far the order improves on its token's own touch
+	reduces bool    // sells existing inventory
+}
+
+// routeQuotes rewrites a YES-denominated quote pair into the best token and
+// side for each economic leg. A leg with no non-crossing candidate is pulled.
+func (m *Maker) routeQuotes(quotes *types.QuotePair, remainingBudget float64) *types.QuotePair {
+	pos := m.inventory.Snapshot()
+	yes, no := m.marketInfo.YesTokenID, m.marketInfo.NoTokenID
+
+	routed := *quotes
+	if quotes.Bid != nil {
+		bid := *quotes.Bid
+		routed.Bid = m.pickCandidate([]quoteCandidate{
+			m.candidate(yes, types.BUY, bid.Price, bid.Size, remainingBudget, 0),
+			m.candidate(no, types.SELL, m.complement(bid.Price), bid.Size, remainingBudget, pos.NoQty),
+		}, bid)
+	}
+	if quotes.Ask != nil {
+		ask := *quotes.Ask
+		routed.Ask = m.pickCandidate([]quoteCandidate{
+			m.candidate(yes, types.SELL, ask.Price, ask.Size, remainingBudget, pos.YesQty),
+			m.candidate(no, types.BUY, m.complement(ask.Price), ask.Size, remainingBudget, 0),
+		}, ask)
+	}
+	return &routed
+}
+
+// candidate builds one routing option. Buys are capped by the remaining risk
+// budget; sells by the quantity held. A zero-size candidate is never chosen.
+func (m *Maker) candidate(tokenID string, side types.Side, price, size, remainingBudget, held float64) quoteCandidate {
+	if side == types.BUY {
+		if price > 0 {
+			size = math.Min(size, remainingBudget/price)
+		}
+	} else {
+		size = math.Min(size, held)
+	}
+	if size < m.marketInfo.MinOrderSize || price <= 0 || price >= 1 {
+		return quoteCandidate{}
+	}
+
+	bestBid, bestAsk, _ := m.book.TokenBestBidAsk(tokenID)
+	c := quoteCandidate{
+		order: types.UserOrder{
+			TokenID:   tokenID,
+			Price:     price,
+			Size:      size,
+			Side:      side,
+			OrderType: types.OrderTypeGTC,
+			TickSize:  m.marketInfo.TickSize,
+		},
+		reduces: side == types.SELL,
+	}
+
+	// A resting quote must not cross its own book, or it would take liquidity
+	if side == types.BUY {
+		if bestAsk > 0 && price >= bestAsk-priceEpsilon {
+			return quoteCandidate{}
+		}
+		c.edge = price - bestBid
+	} else {
+		if bestBid > 0 && price <= bestBid+priceEpsilon {
+			return quoteCandidate{}
+		}
+		if bestAsk == 0 {
+			bestAsk = 1
+		}
+		c.edge = bestAsk - price
+	}
+	return c
+}
+
+// pickCandidate returns the candidate with the most edge over its touch,
+// preferring inventory-reducing sells on ties. Returns nil if none is valid.
+func (m *Maker) pickCandidate(candidates []quoteCandidate, original types.UserOrder) *types.UserOrder {
+	var best *quoteCandidate
+	for i := range candidates {
+		c := &candidates[i]
+		if c.order.TokenID == "" {
+			continue
+		}
+		if best == nil || c.edge > best.edge+priceEpsilon ||
+			math.Abs(c.edge-best.edge) <= priceEpsilon && c.reduces && !best.reduces {
+			best = c
+		}
+	}
+	if best == nil {
+		m.logger.Debug("no routable quote",
+			"side", original.Side,
+			"yes_price", original.Price,
+		)
+		return nil
+	}
+	order := best.order
+	return &order
+}
+
+// economicFill expresses a fill in YES terms for flow and markout tracking:
+// selling NO at p fills our economic bid at 1-p, and buying NO our economic
+// ask.
+func (m *Maker) economicFill(fill Fill) Fill {
+	if fill.TokenID != m.marketInfo.NoTokenID {
+		return fill
+	}
+	fill.Price = 1 - fill.Price
+	if fill.Side == types.BUY {
+		fill.Side = types.SELL
+	} else {
+		fill.Side = types.BUY
+	}
+	return fill
+}
+
+// complement converts a YES price to the equivalent NO price on the tick grid.
+func (m *Maker) complement(price float64) float64 {
+	pow := math.Pow(10, float64(m.marketInfo.TickSize.Decimals()))
+	return math.Round((1-price)*pow) / pow
+}
+
+// priceEpsilon absorbs float error when comparing tick-aligned prices.
+const priceEpsilon = 1e-9
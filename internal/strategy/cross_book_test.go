@@ -0,0 +1,98 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"polymarket-mm/pkg/types"
+)
+
+func setupCrossBookMaker(t *testing.T, yesBid, yesAsk, noBid, noAsk string) *Maker {
+	t.Helper()
+	cfg := testStrategyConfig()
+	cfg.QuoteBothTokens = true
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: info.YesTokenID,
+		Buys:    []types.PriceLevel{{Price: yesBid, Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: yesAsk, Size: "100"}},
+	})
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: info.NoTokenID,
+		Buys:    []types.PriceLevel{{Price: noBid, Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: noAsk, Size: "100"}},
+	})
+	return m
+}
+
+// yesQuotes is a YES-denominated quote pair as computeQuotes would emit.
+func yesQuotes(bid, ask, size float64) *types.QuotePair {
+	info := testMarketInfo()
+	return &types.QuotePair{
+		MarketID:   info.ConditionID,
+		YesTokenID: info.YesTokenID,
+		NoTokenID:  info.NoTokenID,
+		Bid:        &types.UserOrder{TokenID: info.YesTokenID, Price: bid, Size: size, Side: types.BUY},
+		Ask:        &types.UserOrder{TokenID: info.YesTokenID, Price: ask, Size: size, Side: types.SELL},
+	}
+}
+
+func assertLeg(t *testing.T, name string, got *types.UserOrder, token string, side types.Side, price, size float64) {
+	t.Helper()
+	if got == nil {
+		t.Fatalf("%s: expected an order, got nil", name)
+	}
+	if got.TokenID != token || got.Side != side || math.Abs(got.Price-price) > 1e-9 || math.Abs(got.Size-size) > 1e-9 {
+		t.Errorf("%s = %s %s %v x %v, want %s %s %v x %v",
+			name, got.Side, got.TokenID, got.Price, got.Size, side, token, price, size)
+	}
+}
+
+func TestRouteQuotesFlatAsksByBuyingNo(t *testing.T) {
+	t.Parallel()
+	m := setupCrossBookMaker(t, "0.45", "0.55", "0.45", "0.55")
+
+	routed := m.routeQuotes(yesQuotes(0.48, 0.52, 10), 1000)
+
+	// No tokens held: the bid stays on YES, the ask becomes a NO buy at 1-p
+	assertLeg(t, "bid", routed.Bid, "yes-token", types.BUY, 0.48, 10)
+	assertLeg(t, "ask", routed.Ask, "no-token", types.BUY, 0.48, 10)
+}
+
+func TestRouteQuotesSellsHeldInventory(t *testing.T) {
+	t.Parallel()
+	m := setupCrossBookMaker(t, "0.45", "0.55", "0.45", "0.55")
+	m.inventory.OnFill(Fill{Side: types.BUY, TokenID: "yes-token", Price: 0.50, Size: 100})
+	m.inventory.OnFill(Fill{Side: types.BUY, TokenID: "no-token", Price: 0.50, Size: 4})
+
+	routed := m.routeQuotes(yesQuotes(0.48, 0.52, 10), 1000)
+
+	// Equal edge on both books: prefer unwinding what we hold
+	assertLeg(t, "bid", routed.Bid, "no-token", types.SELL, 0.52, 4)
+	assertLeg(t, "ask", routed.Ask, "yes-token", types.SELL, 0.52, 10)
+}
+
+func TestRouteQuotesPicksBetterBook(t *testing.T) {
+	t.Parallel()
+	// YES ask is tight at 0.53; the NO book is wide, so buying NO at 0.48
+	// improves its touch by 0.08 versus 0.01 for selling YES at 0.52
+	m := setupCrossBookMaker(t, "0.45", "0.53", "0.40", "0.56")
+	m.inventory.OnFill(Fill{Side: types.BUY, TokenID: "yes-token", Price: 0.50, Size: 100})
+
+	routed := m.routeQuotes(yesQuotes(0.48, 0.52, 10), 1000)
+	assertLeg(t, "ask", routed.Ask, "no-token", types.BUY, 0.48, 10)
+}
+
+func TestRouteQuotesSkipsCrossingCandidates(t *testing.T) {
+	t.Parallel()
+	// NO ask at 0.47 means a NO buy at 0.48 would take liquidity
+	m := setupCrossBookMaker(t, "0.45", "0.55", "0.40", "0.47")
+
+	routed := m.routeQuotes(yesQuotes(0.48, 0.52, 10), 1000)
+	if routed.Ask != nil {
+		t.Errorf("ask should be pulled when the only candidate crosses, got %+v", routed.Ask)
+	}
+	assertLeg(t, "bid", routed.Bid, "yes-token", types.BUY, 0.48, 10)
+}
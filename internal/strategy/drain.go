@@ -0,0 +1,181 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Draining.
+//
+// A market that is deselected or shut down is wound down rather than
+// abandoned. Once Drain is called the Maker opens no new risk: it quotes only
+// the side that shrinks net exposure, sized to the whole position, and steps
+// that quote from its normal A-S price towards one tick inside the opposite
+// touch as the deadline approaches, so it gets progressively easier to hit.
+// The drain finishes, closing Drained(), only once the position is flat. A
+// market still holding inventory at the deadline keeps quoting at the touch,
+// or with DrainFlatten sells what's left with Flatten once per refresh
+// interval, until it is.
+
+// Drain starts winding the market down, to finish by deadline. It is safe
+// to call from any goroutine; calls after the first are ignored.
+func (m *Maker) Drain(deadline time.Time) {
+	m.drainMu.Lock()
+	defer m.drainMu.Unlock()
+	if !m.drainDeadline.IsZero() {
+		return
+	}
+	m.drainStart = m.now()
+	m.drainDeadline = deadline
+	m.logger.Info("draining market", "deadline", deadline)
+}
+
+// Draining reports whether Drain has been called.
+func (m *Maker) Draining() bool {
+	m.drainMu.Lock()
+	defer m.drainMu.Unlock()
+	return !m.drainDeadline.IsZero()
+}
+
+// Drained is closed once a drain has finished, which is when the position
+// is flat.
+func (m *Maker) Drained() <-chan struct{} {
+	return m.drained
+}
+
+// drainWindow returns the drain's start and deadline, and false if the
+// Maker isn't draining.
+func (m *Maker) drainWindow() (start, deadline time.Time, ok bool) {
+	m.drainMu.Lock()
+	defer m.drainMu.Unlock()
+	return m.drainStart, m.drainDeadline, !m.drainDeadline.IsZero()
+}
+
+// drainOverdue handles a drain whose deadline has passed: a flat market
+// finishes, one still holding inventory is flattened if DrainFlatten is set
+// and otherwise left quoting at the touch. It reports true if the cycle must
+// not quote. It runs before any other check so a stale book can't hold a
+// flat market open.
+func (m *Maker) drainOverdue(ctx context.Context) bool {
+	if m.drainDone {
+		return true
+	}
+	_, deadline, ok := m.drainWindow()
+	if !ok || m.now().Before(deadline) {
+		return false
+	}
+	net := m.netQty()
+	if math.Abs(net) < m.dustSize() {
+		m.finishDrain(ctx, "deadline")
+		return true
+	}
+	if !m.drainLate {
+		m.drainLate = true
+		m.logger.Warn("drain deadline passed with inventory held, still draining",
+			"net_qty", net,
+			"flatten", m.cfg.DrainFlatten,
+		)
+	}
+	if !m.cfg.DrainFlatten {
+		return false
+	}
+
+	// Fills from the last Flatten arrive on the user channel; give them a
+	// cycle before selling again
+	if m.now().Sub(m.lastFlatten) < m.cfg.RefreshInterval {
+		return true
+	}
+	m.lastFlatten = m.now()
+	m.cancelAllMyOrders(ctx)
+	if err := m.Flatten(ctx); err != nil {
+		m.logger.Error("flatten past drain deadline failed", "error", err)
+	}
+	return true
+}
+
+// drainQuotes runs one draining cycle: finish if flat, otherwise quote the
+// reducing side at a price that moves from the A-S quote to the touch over
+// the drain window.
+func (m *Maker) drainQuotes(ctx context.Context, mid float64, start, deadline time.Time) {
+	net := m.netQty()
+	if math.Abs(net) < m.dustSize() {
+		m.finishDrain(ctx, "flat")
+		return
+	}
+
+	quotes, err := m.computeQuotes(mid, math.Inf(1))
+	if err != nil {
+		m.logger.Error("compute quotes failed", "error", err)
+		return
+	}
+	quotes.Bid, quotes.Ask = m.reduceOnly(quotes.Bid, quotes.Ask)
+
+	progress := 1.0
+	if window := deadline.Sub(start); window > 0 {
+		progress = clamp(float64(m.now().Sub(start))/float64(window), 0, 1)
+	}
+	tickDec := m.marketInfo.TickSize.Decimals()
+	tick := math.Pow(10, -float64(tickDec))
+	size := math.Floor(math.Abs(net)*100) / 100
+	bestBid, bestAsk := m.drainTouch()
+
+	if ask := quotes.Ask; ask != nil {
+		ask.Size = size
+		if target := bestBid + tick; bestBid > 0 && ask.Price > target {
+			ask.Price = math.Max(roundUpToTick(ask.Price-progress*(ask.Price-target)-1e-9, tickDec), target)
+		}
+	}
+	if bid := quotes.Bid; bid != nil {
+		bid.Size = size
+		if target := bestAsk - tick; bestAsk > 0 && bid.Price < target {
+			bid.Price = math.Min(roundDownToTick(bid.Price+progress*(target-bid.Price)+1e-9, tickDec), target)
+		}
+	}
+	if m.cfg.QuoteBothTokens {
+		quotes = m.routeQuotes(quotes, math.Inf(1))
+	}
+
+	m.logger.Debug("drain quotes",
+		"net", net,
+		"progress", progress,
+		"bid", quotes.Bid,
+		"ask", quotes.Ask,
+	)
+	if err := m.reconcileOrders(ctx, quotes); err != nil {
+		m.logger.Error("reconcile orders failed", "error", err)
+	}
+}
+
+// finishDrain pulls a flat market's quotes and signals the engine that the
+// market can be removed.
+func (m *Maker) finishDrain(ctx context.Context, reason string) {
+	m.cancelAllMyOrders(ctx)
+	m.logger.Info("market drained", "reason", reason, "net_qty", m.netQty())
+	m.drainDone = true
+	close(m.drained)
+}
+
+// drainTouch is the YES-denominated touch the drain steps towards: the
+// synthetic touch when quoting both tokens, the YES book otherwise. A
+// missing side is 0.
+func (m *Maker) drainTouch() (bid, ask float64) {
+	if m.cfg.QuoteBothTokens {
+		bid, ask, _ = m.book.SyntheticBidAsk()
+		return bid, ask
+	}
+	bid, ask, _ = m.book.TokenBestBidAsk(m.marketInfo.YesTokenID)
+	return bid, ask
+}
+
+// netQty is the net YES exposure: YES held minus NO held.
+func (m *Maker) netQty() float64 {
+	pos := m.inventory.Snapshot()
+	return pos.YesQty - pos.NoQty
+}
+
+// dustSize is the smallest position worth reducing; anything less can't be
+// sold in one order anyway.
+func (m *Maker) dustSize() float64 {
+	return math.Max(m.marketInfo.MinOrderSize, 1e-6)
+}
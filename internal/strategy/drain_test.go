@@ -0,0 +1,159 @@
+package strategy
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func setupDrainMaker(t *testing.T) (*Maker, *fakeGateway, *time.Time) {
+	t.Helper()
+	m := setupMaker(testStrategyConfig(), testMarketInfo())
+	gw := &fakeGateway{}
+	m.client = gw
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "yes-token",
+		Buys:    []types.PriceLevel{{Price: "0.45", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.55", Size: "100"}},
+	})
+	return m, gw, &now
+}
+
+func TestDrainStepsReducingQuoteToTheTouch(t *testing.T) {
+	t.Parallel()
+	m, _, now := setupDrainMaker(t)
+	m.inventory.SetPosition(Position{YesQty: 20, AvgEntryYes: 0.50})
+	start := *now
+	m.Drain(start.Add(100 * time.Second))
+
+	askAt := func(elapsed time.Duration) *types.OpenOrder {
+		t.Helper()
+		*now = start.Add(elapsed)
+		_, deadline, _ := m.drainWindow()
+		m.drainQuotes(context.Background(), 0.50, start, deadline)
+		var ask *types.OpenOrder
+		for _, o := range m.activeOrders {
+			if o.Side == string(types.BUY) {
+				t.Fatalf("draining a long position placed a bid: %+v", o)
+			}
+			o := o
+			ask = &o
+		}
+		if ask == nil {
+			t.Fatal("no ask while draining a long position")
+		}
+		return ask
+	}
+
+	first := askAt(0)
+	if size, _ := strconv.ParseFloat(first.OriginalSize, 64); size != 20 {
+		t.Errorf("drain ask size = %s, want the whole position", first.OriginalSize)
+	}
+	mid := askAt(50 * time.Second)
+	last := askAt(100 * time.Second)
+	p0, _ := strconv.ParseFloat(first.Price, 64)
+	p1, _ := strconv.ParseFloat(mid.Price, 64)
+	p2, _ := strconv.ParseFloat(last.Price, 64)
+	if !(p0 > p1 && p1 > p2) {
+		t.Errorf("ask not stepping down: %v, %v, %v", p0, p1, p2)
+	}
+	if p2 != 0.46 {
+		t.Errorf("ask at deadline = %v, want one tick above the bid (0.46)", p2)
+	}
+
+	select {
+	case <-m.Drained():
+		t.Fatal("drained while still holding inventory")
+	default:
+	}
+}
+
+func TestDrainFinishesWhenFlat(t *testing.T) {
+	t.Parallel()
+	m, gw, now := setupDrainMaker(t)
+	m.Drain(now.Add(time.Minute))
+
+	m.drainQuotes(context.Background(), 0.50, *now, now.Add(time.Minute))
+	select {
+	case <-m.Drained():
+	default:
+		t.Fatal("flat market should finish draining")
+	}
+	if len(gw.posted) != 0 {
+		t.Errorf("flat drain posted orders: %+v", gw.posted)
+	}
+	if !m.drainOverdue(context.Background()) {
+		t.Error("a finished drain must not quote again")
+	}
+}
+
+func TestDrainFlattensAtDeadline(t *testing.T) {
+	t.Parallel()
+	m, gw, now := setupDrainMaker(t)
+	m.cfg.DrainFlatten = true
+	m.inventory.SetPosition(Position{YesQty: 20, AvgEntryYes: 0.50})
+	m.Drain(now.Add(time.Minute))
+
+	if m.drainOverdue(context.Background()) {
+		t.Fatal("drain finished before its deadline")
+	}
+	*now = now.Add(time.Minute)
+	if !m.drainOverdue(context.Background()) {
+		t.Fatal("cycle quoted while flattening at the deadline")
+	}
+	if len(gw.posted) != 1 || gw.posted[0].OrderType != types.OrderTypeFAK || gw.posted[0].Price != 0.45 {
+		t.Errorf("deadline flatten = %+v, want one FAK sell into the bid", gw.posted)
+	}
+
+	// Only part of it filled: the drain goes on, selling again a cycle later
+	m.inventory.SetPosition(Position{YesQty: 8, AvgEntryYes: 0.50})
+	m.drainOverdue(context.Background())
+	if len(gw.posted) != 1 {
+		t.Errorf("flattened again before the last fills could arrive: %+v", gw.posted)
+	}
+	*now = now.Add(m.cfg.RefreshInterval)
+	m.drainOverdue(context.Background())
+	if len(gw.posted) != 2 || gw.posted[1].Size != 8 {
+		t.Errorf("second flatten = %+v, want the 8 left", gw.posted)
+	}
+	select {
+	case <-m.Drained():
+		t.Fatal("drain finished while still holding inventory")
+	default:
+	}
+
+	m.inventory.SetPosition(Position{})
+	if !m.drainOverdue(context.Background()) {
+		t.Fatal("flat market still draining")
+	}
+	select {
+	case <-m.Drained():
+	default:
+		t.Fatal("Drained not closed once flat")
+	}
+}
+
+func TestDrainKeepsQuotingPastDeadlineWithoutFlatten(t *testing.T) {
+	t.Parallel()
+	m, gw, now := setupDrainMaker(t)
+	m.inventory.SetPosition(Position{YesQty: 20, AvgEntryYes: 0.50})
+	m.Drain(now.Add(time.Minute))
+
+	*now = now.Add(2 * time.Minute)
+	if m.drainOverdue(context.Background()) {
+		t.Fatal("overdue drain holding inventory stopped quoting")
+	}
+	select {
+	case <-m.Drained():
+		t.Fatal("deadline ended a drain that still holds inventory")
+	default:
+	}
+	if len(gw.posted) != 0 {
+		t.Errorf("flattened without drain_flatten: %+v", gw.posted)
+	}
+}
@@ -0,0 +1,110 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+// ExchangeView is the exchange's record of one market at AsOf, fetched over
+// REST by the engine's periodic reconciler.
+type ExchangeView struct {
+	AsOf       time.Time
+	OpenOrders []types.OpenOrder
+	Trades     []TradeFills // our side of recent trades, oldest first
+}
+
+// SyncResult describes the drift a Sync corrected.
+type SyncResult struct {
+	AppliedFills   []Fill   // fills the WS feed never delivered
+	ReversedTrades []string // booked trades whose settlement failed
+	DroppedOrders  []string // tracked orders no longer resting on the exchange
+	OrphanOrders   []string // resting orders we weren't tracking, now cancelled
+}
+
+// Drifted reports whether the local state had to be corrected.
+func (r SyncResult) Drifted() bool {
+	return len(r.AppliedFills) > 0 || len(r.ReversedTrades) > 0 ||
+		len(r.DroppedOrders) > 0 || len(r.OrphanOrders) > 0
+}
+
+type syncRequest struct {
+	view  ExchangeView
+	reply chan SyncResult
+}
+
+// Sync hands an exchange snapshot to the Run goroutine, which books any
+// trades not yet in the inventory ledger and brings activeOrders in line
+// with the exchange. It blocks until the snapshot is applied or ctx is done.
+func (m *Maker) Sync(ctx context.Context, view ExchangeView) (SyncResult, error) {
+	req := syncRequest{view: view, reply: make(chan SyncResult, 1)}
+	select {
+	case m.syncCh <- req:
+	case <-ctx.Done():
+		return SyncResult{}, fmt.Errorf("sync: %w", ctx.Err())
+	}
+	select {
+	case result := <-req.reply:
+		return result, nil
+	case <-ctx.Done():
+		return SyncResult{}, fmt.Errorf("sync: %w", ctx.Err())
+	}
+}
+
+func (m *Maker) applySync(ctx context.Context, view ExchangeView) SyncResult {
+	var result SyncResult
+
+	// Book trades the WS feed dropped, and undo any whose settlement failed
+	for _, t := range view.Trades {
+		switch m.applyTrade(t) {
+		case TradeBooked:
+			result.AppliedFills = append(result.AppliedFills, t.Fills...)
+		case TradeReversed:
+			result.ReversedTrades = append(result.ReversedTrades, t.TradeID)
+		}
+	}
+
+	resting := make(map[string]bool, len(view.OpenOrders))
+	for _, o := range view.OpenOrders {
+		resting[o.ID] = true
+	}
+
+	// Orders placed after the snapshot was taken can't be in it yet
+	for id := range m.activeOrders {
+		if resting[id] || m.placedAt[id].After(view.AsOf) {
+			continue
+		}
+		m.journalCancel(id, "missing_on_exchange")
+		m.forgetOrder(id)
+		result.DroppedOrders = append(result.DroppedOrders, id)
+	}
+
+	// Resting orders we don't know about are in an unknown state; pull them
+	// and let the next quote cycle replace them. Orders we cancelled after
+	// the snapshot show up here too, and cancelling them again is harmless.
+	var orphans []string
+	for _, o := range view.OpenOrders {
+		if _, ok := m.activeOrders[o.ID]; ok {
+			continue
+		}
+		if o.AssetID != m.marketInfo.YesTokenID && o.AssetID != m.marketInfo.NoTokenID {
+			continue
+		}
+		orphans = append(orphans, o.ID)
+	}
+	if len(orphans) > 0 {
+		resp, err := m.client.CancelOrders(ctx, orphans)
+		if err != nil {
+			m.logger.Error("cancel orphaned orders failed", "count", len(orphans), "error", err)
+		} else {
+			for _, id := range resp.Canceled {
+				m.record(types.JournalEntry{Type: types.JournalCancel, OrderID: id, Reason: "orphan"})
+			}
+			result.OrphanOrders = resp.Canceled
+		}
+	}
+
+	return result
+}
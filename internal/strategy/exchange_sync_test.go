@@ -0,0 +1,66 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestApplySyncBooksMissingFillsAndFixesOrders(t *testing.T) {
+	t.Parallel()
+	m := setupMaker(testStrategyConfig(), testMarketInfo())
+	m.client = &fakeGateway{}
+
+	asOf := time.Now()
+	m.activeOrders["gone"] = types.OpenOrder{ID: "gone", AssetID: "yes-token"}
+	m.placedAt["gone"] = asOf.Add(-time.Minute)
+	m.activeOrders["new"] = types.OpenOrder{ID: "new", AssetID: "yes-token"}
+	m.placedAt["new"] = asOf.Add(time.Second) // placed after the snapshot
+
+	// t1 already arrived over WS
+	m.handleFill(types.WSTradeEvent{ID: "t1", AssetID: "yes-token", Side: "BUY", Price: "0.40", Size: "5"})
+
+	view := ExchangeView{
+		AsOf:       asOf,
+		OpenOrders: []types.OpenOrder{{ID: "stray", AssetID: "no-token"}},
+		Trades: []TradeFills{
+			{TradeID: "t1", Status: TradeMatched, Fills: []Fill{
+				{TradeID: "t1", TokenID: "yes-token", Side: types.BUY, Price: 0.40, Size: 5},
+			}},
+			// One trade filling two of our orders
+			{TradeID: "t2", Status: TradeMatched, Fills: []Fill{
+				{TradeID: "t2", TokenID: "yes-token", Side: types.BUY, Price: 0.42, Size: 3},
+				{TradeID: "t2", TokenID: "yes-token", Side: types.BUY, Price: 0.42, Size: 2},
+			}},
+		},
+	}
+	result := m.applySync(context.Background(), view)
+
+	if len(result.AppliedFills) != 2 || result.AppliedFills[0].TradeID != "t2" {
+		t.Errorf("applied fills = %+v, want both t2 fills", result.AppliedFills)
+	}
+	if len(result.DroppedOrders) != 1 || result.DroppedOrders[0] != "gone" {
+		t.Errorf("dropped = %v, want [gone]", result.DroppedOrders)
+	}
+	if len(result.OrphanOrders) != 1 || result.OrphanOrders[0] != "stray" {
+		t.Errorf("orphans = %v, want [stray]", result.OrphanOrders)
+	}
+	if _, ok := m.activeOrders["new"]; !ok {
+		t.Error("order placed after the snapshot was dropped")
+	}
+
+	// A late WS delivery of t2 must not double-count
+	m.handleFill(types.WSTradeEvent{ID: "t2", AssetID: "yes-token", Side: "BUY", Price: "0.42", Size: "5"})
+	if pos := m.inventory.Snapshot(); pos.YesQty != 10 {
+		t.Errorf("YesQty = %v, want 10", pos.YesQty)
+	}
+
+	// Nothing left to correct
+	view.OpenOrders = nil
+	view.AsOf = time.Now().Add(-time.Hour)
+	if again := m.applySync(context.Background(), view); again.Drifted() {
+		t.Errorf("second sync drifted: %+v", again)
+	}
+}
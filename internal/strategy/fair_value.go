@@ -0,0 +1,56 @@
+package strategy
+
+// defaultImbalanceLevels is how many book levels per side count towards
+// imbalance and the weighted mid when StrategyConfig.ImbalanceLevels is
+// unset.
+const defaultImbalanceLevels = 3
+
+func (m *Maker) imbalanceLevels() int {
+	if m.cfg.ImbalanceLevels > 0 {
+		return m.cfg.ImbalanceLevels
+	}
+	return defaultImbalanceLevels
+}
+
+// Imbalance is the YES book's depth imbalance over the levels the Maker
+// skews on. Safe to call from any goroutine.
+func (m *Maker) Imbalance() (float64, bool) {
+	return m.book.Imbalance(m.imbalanceLevels())
+}
+
+// fairValueOffset is how far the configured fair-value estimator sits from
+// the plain YES mid. It is applied as an offset so it composes with the
+// synthetic mid when quoting both tokens. Zero for "mid" or when the book
+// can't support the estimate.
+func (m *Maker) fairValueOffset() float64 {
+	var est float64
+	var ok bool
+	switch m.cfg.FairValue {
+	case "microprice":
+		est, ok = m.book.Microprice()
+	case "weighted_mid":
+		est, ok = m.book.WeightedMid(m.imbalanceLevels())
+	default:
+		return 0
+	}
+	mid, midOK := m.book.MidPrice()
+	if !ok || !midOK {
+		return 0
+	}
+	return est - mid
+}
+
+// imbalanceSkew returns how far to widen the bid and the ask given the
+// current book imbalance. Only the side the book leans against moves: with
+// heavy bids the price is more likely to tick up, so the ask is pulled back
+// while the bid keeps its place.
+func (m *Maker) imbalanceSkew() (bidWiden, askWiden, imbalance float64) {
+	imbalance, ok := m.Imbalance()
+	if !ok || m.cfg.ImbalanceSkew <= 0 {
+		return 0, 0, imbalance
+	}
+	if imbalance > 0 {
+		return 0, m.cfg.ImbalanceSkew * imbalance, imbalance
+	}
+	return -m.cfg.ImbalanceSkew * imbalance, 0, imbalance
+}
@@ -0,0 +1,34 @@
+package strategy
+
+import (
+	"math"
+	"strconv"
+)
+
+// FeeUSD is the fee in USDC on a fill of size tokens at price. Polymarket
+// charges feeRateBps on the cheaper side of the binary payout, so fees are
+// symmetric between YES and NO and shrink toward the extremes:
+//
+//	fee = rate * min(price, 1-price) * size
+//
+// Fees on buys are taken in tokens on-chain; they are booked here at their
+// USDC value so realized PnL matches the wallet.
+func FeeUSD(feeRateBps, price, size float64) float64 {
+	if feeRateBps <= 0 || size <= 0 {
+		return 0
+	}
+	return feeRateBps / 10000 * math.Min(price, 1-price) * size
+}
+
+// tradeFee parses a trade's fee_rate_bps and returns the fill's fee.
+func tradeFee(feeRateBps string, price, size float64) float64 {
+	rate, _ := strconv.ParseFloat(feeRateBps, 64)
+	return FeeUSD(rate, price, size)
+}
+
+// roundTripFee is the fee per token for buying and selling one token near
+// mid: the break-even spread before any edge.
+func (m *Maker) roundTripFee(mid float64) float64 {
+	rate := max(m.marketInfo.YesFeeRateBps, m.marketInfo.NoFeeRateBps)
+	return 2 * FeeUSD(float64(rate), mid, 1)
+}
@@ -0,0 +1,72 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestFeeUSD(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rate, price, size, want float64
+	}{
+		{200, 0.50, 10, 0.10},
+		{200, 0.90, 10, 0.02}, // charged on the cheaper side
+		{200, 0.10, 10, 0.02},
+		{0, 0.50, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := FeeUSD(tt.rate, tt.price, tt.size); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("FeeUSD(%v, %v, %v) = %v, want %v", tt.rate, tt.price, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestComputeQuotesSpreadCoversFees(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.Sigma = 0.01
+	cfg.K = 1e6 // negligible model spread: the floor binds
+	info := testMarketInfo()
+	info.YesFeeRateBps = 200
+	info.NoFeeRateBps = 200
+	m := setupMaker(cfg, info)
+
+	quotes, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	// 1% configured edge plus 2 * 2% * 0.50 round-trip fees
+	if spread := quotes.Ask.Price - quotes.Bid.Price; spread < 0.03-1e-9 {
+		t.Errorf("spread = %v, want >= 0.03", spread)
+	}
+
+	order := *quotes.Bid
+	m.stampQuote(&order)
+	if order.FeeRateBps != 200 {
+		t.Errorf("quote FeeRateBps = %d, want 200", order.FeeRateBps)
+	}
+}
+
+func TestFeesAreBookedAndReversed(t *testing.T) {
+	t.Parallel()
+	m := setupMaker(testStrategyConfig(), testMarketInfo())
+
+	m.handleFill(types.WSTradeEvent{ID: "b", AssetID: "yes-token", Side: "BUY", Price: "0.40", Size: "10", FeeRateBps: "100"})
+	m.handleFill(types.WSTradeEvent{ID: "s", AssetID: "yes-token", Side: "SELL", Price: "0.60", Size: "10", FeeRateBps: "100"})
+
+	// 2.00 gross, less 0.04 on the buy and 0.04 on the sell
+	pos := m.inventory.Snapshot()
+	if math.Abs(pos.RealizedPnL-1.92) > 1e-9 || math.Abs(pos.FeesPaid-0.08) > 1e-9 {
+		t.Errorf("position = %+v, want 1.92 realized after 0.08 fees", pos)
+	}
+
+	m.handleFill(types.WSTradeEvent{ID: "s", AssetID: "yes-token", Side: "SELL", Price: "0.60", Size: "10", FeeRateBps: "100", Status: TradeFailed})
+	m.handleFill(types.WSTradeEvent{ID: "b", AssetID: "yes-token", Side: "BUY", Price: "0.40", Size: "10", FeeRateBps: "100", Status: TradeFailed})
+	pos = m.inventory.Snapshot()
+	if math.Abs(pos.RealizedPnL) > 1e-9 || math.Abs(pos.FeesPaid) > 1e-9 || pos.YesQty != 0 {
+		t.Errorf("after reversals = %+v, want flat with no fees", pos)
+	}
+}
@@ -16,6 +16,15 @@ type ToxicityMetrics struct {
 	FillVelocity         float64 // Fills per minute
 	ToxicityScore        float64 // [0, 1]: Composite toxicity score
 	IsAverse             bool    // True if likely getting adversely selected
+
+	// Direction is the side of our quotes filled most: BUY means our bids
+	// are being hit (informed flow is selling). Empty when balanced.
+	Direction types.Side
+
+	// Spread multipliers per side, set by Assess: the side being picked
+	// off widens, the other stays tight to unwind
+	BidMultiplier float64
+	AskMultiplier float64
 }
 
 // FlowTracker tracks recent fills in a rolling time window to detect toxic flow patterns.
@@ -32,8 +41,16 @@ type FlowTracker struct {
 	cooldownPeriod     time.Duration // Stay wide after toxicity detected
 	maxSpreadMultiple  float64       // Max spread multiplier (e.g., 3.0x)
 
+	// Optional markout feed replacing the imbalance/velocity heuristic
+	markouts     MarkoutSource
+	markoutScale float64 // adverse markout that scores 1.0
+
 	// State
-	lastToxicTime time.Time // Last time toxicity was detected
+	lastToxicTime      time.Time  // Last time toxicity was detected
+	lastToxicDirection types.Side // Direction of the flow when last toxic
+	lastToxicImbalance float64    // Directional imbalance when last toxic
+
+	now func() time.Time // clock (replaced by the backtester)
 }
 
 // NewFlowTracker creates a flow tracker with the given configuration.
@@ -44,9 +61,33 @@ func NewFlowTracker(windowDuration time.Duration, toxicityThreshold float64, coo
 		toxicityThreshold: toxicityThreshold,
 		cooldownPeriod:    cooldownPeriod,
 		maxSpreadMultiple: maxSpreadMultiple,
+		now:               time.Now,
 	}
 }
 
+// MarkoutSource supplies the recent average markout of each side of our
+// quotes. *MarkoutTracker satisfies it.
+type MarkoutSource interface {
+	RecentMarkout(side types.Side) (float64, bool)
+}
+
+// SetMarkoutSource makes CalculateToxicity score each side from its recent
+// average markout instead of fill imbalance and velocity: 0 when the mid
+// moved our way, rising linearly to 1.0 at an adverse markout of scale.
+func (ft *FlowTracker) SetMarkoutSource(src MarkoutSource, scale float64) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.markouts = src
+	ft.markoutScale = scale
+}
+
+// SetClock replaces the time source used for window eviction and cooldowns.
+func (ft *FlowTracker) SetClock(now func() time.Time) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ft.now = now
+}
+
 // AddFill adds a new fill to the tracker and evicts stale entries outside the window.
 func (ft *FlowTracker) AddFill(fill Fill) {
 	ft.mu.Lock()
@@ -63,7 +104,7 @@ func (ft *FlowTracker) evictStaleLocked() {
 		return
 	}
 
-	cutoff := time.Now().Add(-ft.windowDuration)
+	cutoff := ft.now().Add(-ft.windowDuration)
 	validIdx := -1
 	for i, fill := range ft.fills {
 		if fill.Timestamp.After(cutoff) {
@@ -93,6 +134,10 @@ func (ft *FlowTracker) CalculateToxicity() ToxicityMetrics {
 	ft.mu.RLock()
 	defer ft.mu.RUnlock()
 
+	if ft.markouts != nil {
+		return ft.markoutToxicityLocked()
+	}
+
 	if len(ft.fills) == 0 {
 		return ToxicityMetrics{}
 	}
@@ -112,6 +157,13 @@ func (ft *FlowTracker) CalculateToxicity() ToxicityMetrics {
 	// Directional imbalance: % of fills in the dominant direction
 	dominant := math.Max(float64(buyCount), float64(sellCount))
 	directionalImbalance := dominant / float64(totalFills)
+	var direction types.Side
+	switch {
+	case buyCount > sellCount:
+		direction = types.BUY
+	case sellCount > buyCount:
+		direction = types.SELL
+	}
 
 	// Fill velocity: fills per minute
 	if len(ft.fills) < 2 {
@@ -120,6 +172,7 @@ func (ft *FlowTracker) CalculateToxicity() ToxicityMetrics {
 			FillVelocity:         0,
 			ToxicityScore:        directionalImbalance * 0.6, // Only directional component
 			IsAverse:             directionalImbalance > ft.toxicityThreshold,
+			Direction:            direction,
 		}
 	}
 
@@ -140,24 +193,90 @@ func (ft *FlowTracker) CalculateToxicity() ToxicityMetrics {
 		FillVelocity:         fillVelocity,
 		ToxicityScore:        toxicityScore,
 		IsAverse:             toxicityScore > ft.toxicityThreshold,
+		Direction:            direction,
 	}
 }
 
+// markoutToxicityLocked scores toxicity from markouts. The score is the
+// worse side's; DirectionalImbalance is that side's share of the two scores,
+// so Assess widens the other side in proportion to its own score.
+// Must be called with lock held.
+func (ft *FlowTracker) markoutToxicityLocked() ToxicityMetrics {
+	score := func(side types.Side) float64 {
+		avg, ok := ft.markouts.RecentMarkout(side)
+		if !ok || ft.markoutScale <= 0 {
+			return 0
+		}
+		return math.Min(math.Max(-avg/ft.markoutScale, 0), 1)
+	}
+	bidScore, askScore := score(types.BUY), score(types.SELL)
+
+	metrics := ToxicityMetrics{FillVelocity: float64(len(ft.fills)) / ft.windowDuration.Minutes()}
+	dominant := math.Max(bidScore, askScore)
+	if dominant == 0 {
+		return metrics
+	}
+	metrics.ToxicityScore = dominant
+	metrics.DirectionalImbalance = dominant / (bidScore + askScore)
+	metrics.IsAverse = dominant > ft.toxicityThreshold
+	switch {
+	case bidScore > askScore:
+		metrics.Direction = types.BUY
+	case askScore > bidScore:
+		metrics.Direction = types.SELL
+	}
+	return metrics
+}
+
+// Assess returns the current toxicity metrics with the spread multiplier
+// for each side of our quotes. The side being filled most takes the full
+// widening; the other side widens in proportion to its fills relative to the
+// dominant side's, so one-way flow leaves it at 1.0x to unwind. Through the
+// cooldown the direction of the last toxic flow is kept.
+func (ft *FlowTracker) Assess() ToxicityMetrics {
+	metrics := ft.CalculateToxicity()
+	multiplier := ft.spreadMultiplier(metrics)
+
+	ft.mu.RLock()
+	direction, imbalance := ft.lastToxicDirection, ft.lastToxicImbalance
+	ft.mu.RUnlock()
+
+	metrics.BidMultiplier, metrics.AskMultiplier = multiplier, multiplier
+	if direction != "" && imbalance > 0 {
+		other := 1.0 + (multiplier-1.0)*(1.0-imbalance)/imbalance
+		if direction == types.BUY {
+			metrics.AskMultiplier = other
+		} else {
+			metrics.BidMultiplier = other
+		}
+	}
+	return metrics
+}
+
 // GetSpreadMultiplier returns the spread multiplier to apply based on current toxicity.
 // Returns 1.0 (no change) under normal conditions, up to maxSpreadMultiple when toxic.
+// It is the wider of the two per-side multipliers from Assess.
 func (ft *FlowTracker) GetSpreadMultiplier() float64 {
-	metrics := ft.CalculateToxicity()
+	metrics := ft.Assess()
+	return math.Max(metrics.BidMultiplier, metrics.AskMultiplier)
+}
 
+// spreadMultiplier returns the symmetric spread multiplier for metrics,
+// recording toxicity for the cooldown.
+func (ft *FlowTracker) spreadMultiplier(metrics ToxicityMetrics) float64 {
 	// Update last toxic time if currently toxic
 	if metrics.IsAverse {
 		ft.mu.Lock()
-		ft.lastToxicTime = time.Now()
+		ft.lastToxicTime = ft.now()
+		ft.lastToxicDirection = metrics.Direction
+		ft.lastToxicImbalance = metrics.DirectionalImbalance
 		ft.mu.Unlock()
 	}
 
 	// Check if in cooldown period
 	ft.mu.RLock()
-	inCooldown := time.Since(ft.lastToxicTime) < ft.cooldownPeriod
+	now := ft.now()
+	inCooldown := now.Sub(ft.lastToxicTime) < ft.cooldownPeriod
 	ft.mu.RUnlock()
 
 	if !metrics.IsAverse && !inCooldown {
@@ -168,7 +287,7 @@ func (ft *FlowTracker) GetSpreadMultiplier() float64 {
 	// Linear interpolation between 1.0x and maxSpreadMultiple based on toxicity score
 	if metrics.ToxicityScore < ft.toxicityThreshold {
 		// In cooldown but not currently toxic: gradually return to normal
-		timeSinceToxic := time.Since(ft.lastToxicTime).Seconds()
+		timeSinceToxic := now.Sub(ft.lastToxicTime).Seconds()
 		cooldownSeconds := ft.cooldownPeriod.Seconds()
 		cooldownProgress := math.Min(timeSinceToxic/cooldownSeconds, 1.0)
 
@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -297,3 +298,37 @@ func TestFlowTracker_Threshold(t *testing.T) {
 		t.Errorf("expected no widening when not adverse, got multiplier %f", multiplier)
 	}
 }
+
+func TestFlowTracker_DirectionalMultipliers(t *testing.T) {
+	ft := NewFlowTracker(60*time.Second, 0.6, 120*time.Second, 3.0)
+
+	// Our bids hit 4 times, our ask once: the flow is selling into us
+	now := time.Now()
+	for i, side := range []types.Side{types.BUY, types.BUY, types.SELL, types.BUY, types.BUY} {
+		ft.AddFill(Fill{
+			Timestamp: now.Add(time.Duration(i) * time.Second),
+			Side:      side,
+			TokenID:   "token1",
+			Price:     0.5,
+			Size:      10.0,
+			TradeID:   string(rune('A' + i)),
+		})
+	}
+
+	metrics := ft.Assess()
+	if metrics.Direction != types.BUY {
+		t.Fatalf("expected direction BUY, got %q", metrics.Direction)
+	}
+	if metrics.BidMultiplier <= 1.0 {
+		t.Errorf("expected the bid to widen, got %f", metrics.BidMultiplier)
+	}
+
+	// The ask gets 1 fill for the bid's 4, so a quarter of the widening
+	want := 1.0 + (metrics.BidMultiplier-1.0)*0.25
+	if math.Abs(metrics.AskMultiplier-want) > 1e-9 {
+		t.Errorf("expected ask multiplier %f, got %f", want, metrics.AskMultiplier)
+	}
+	if m := ft.GetSpreadMultiplier(); m != metrics.BidMultiplier {
+		t.Errorf("expected spread multiplier to be the wider side %f, got %f", metrics.BidMultiplier, m)
+	}
+}
@@ -15,8 +15,10 @@ type Position struct {
 	NoQty         float64   `json:"no_qty"`
 	AvgEntryYes   float64   `json:"avg_entry_yes"`
 	AvgEntryNo    float64   `json:"avg_entry_no"`
-	RealizedPnL   float64   `json:"realized_pnl"`
+	RealizedPnL   float64   `json:"realized_pnl"` // net of fees
 	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	FeesPaid      float64   `json:"fees_paid"`
+	Volume        float64   `json:"volume"` // USDC notional bought and sold
 	LastUpdated   time.Time `json:"last_updated"`
 }
 
@@ -28,6 +30,7 @@ type Fill struct {
 	Price     float64    `json:"price"`
 	Size      float64    `json:"size"`
 	TradeID   string     `json:"trade_id"`
+	Fee       float64    `json:"fee,omitempty"` // USDC, see FeeUSD
 }
 
 // Inventory tracks the position for one market. Thread-safe via RWMutex.
@@ -40,9 +43,14 @@ type Inventory struct {
 	noToken  string
 	pos      Position
 
+	// Trades booked into pos, keyed by trade ID (see ApplyTrade)
+	trades map[string]BookedTrade
+
 	// Fill history for flow analytics (bounded circular buffer)
 	fillHistory []Fill
 	maxHistory  int
+
+	now func() time.Time // injectable clock for tests and backtests
 }
 
 // NewInventory creates inventory tracking for a market.
@@ -51,24 +59,41 @@ func NewInventory(marketID, yesToken, noToken string) *Inventory {
 		marketID:    marketID,
 		yesToken:    yesToken,
 		noToken:     noToken,
+		trades:      make(map[string]BookedTrade),
 		fillHistory: make([]Fill, 0, 1000),
 		maxHistory:  1000,
+		now:         time.Now,
 	}
 }
 
+// SetClock replaces the time source used to stamp updates and booked
+// trades and to age the trade ledger.
+func (inv *Inventory) SetClock(now func() time.Time) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.now = now
+}
+
 // OnFill processes a fill event. Updates quantities and average entry prices.
 // When a position is reduced, realized PnL is calculated.
 func (inv *Inventory) OnFill(fill Fill) {
 	inv.mu.Lock()
 	defer inv.mu.Unlock()
+	inv.onFillLocked(fill)
+}
 
+// onFillLocked applies a fill. Must be called with lock held.
+func (inv *Inventory) onFillLocked(fill Fill) {
 	if fill.TokenID == inv.yesToken {
 		inv.applyYesFill(fill)
 	} else {
 		inv.applyNoFill(fill)
 	}
+	inv.pos.RealizedPnL -= fill.Fee
+	inv.pos.FeesPaid += fill.Fee
+	inv.pos.Volume += fill.Price * fill.Size
 
-	inv.pos.LastUpdated = time.Now()
+	inv.pos.LastUpdated = inv.now()
 
 	// Retain fill history for flow analytics (bounded)
 	inv.fillHistory = append(inv.fillHistory, fill)
@@ -0,0 +1,104 @@
+package strategy
+
+import (
+	"math"
+
+	"polymarket-mm/pkg/types"
+)
+
+// Quote ladders.
+//
+// With LadderLevels above 1 each side rests several orders: the A-S quote at
+// the front and LadderLevels-1 more behind it, LadderSpacingTicks apart.
+// Deeper levels keep queue priority for when the touch moves to them and
+// catch part of a sweep at better prices than the front quote. Levels are
+// built from the final, routed front quote, so they share its token and
+// side. Reduce-only and draining markets quote a single level.
+
+// defaultLadderSpacingTicks is the gap between ladder levels when
+// StrategyConfig.LadderSpacingTicks is unset.
+const defaultLadderSpacingTicks = 1
+
+// ladderSide is one side's ladder under construction.
+type ladderSide struct {
+	front  *types.UserOrder
+	levels *[]*types.UserOrder
+	held   float64 // size that may still be sold, +Inf for buys
+	done   bool
+}
+
+// buildLadder fills in quotes.BidLevels and AskLevels behind the front
+// quotes. Levels are added one depth at a time on both sides, so a tight
+// budget thins the ladder evenly. Their notional counts against the
+// remaining risk budget along with the front quotes', and when quoting both
+// tokens sells are capped by the quantity held.
+func (m *Maker) buildLadder(quotes *types.QuotePair, remainingBudget float64) {
+	if m.cfg.LadderLevels <= 1 {
+		return
+	}
+	if _, stage := m.horizon(); stage >= stageReduceOnly {
+		return
+	}
+
+	tickDec := m.marketInfo.TickSize.Decimals()
+	pow := math.Pow(10, float64(tickDec))
+	tick := 1 / pow
+	spacing := m.cfg.LadderSpacingTicks
+	if spacing <= 0 {
+		spacing = defaultLadderSpacingTicks
+	}
+	ratio := m.cfg.LadderSizeRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+
+	pos := m.inventory.Snapshot()
+	notional := 0.0
+	sides := []*ladderSide{
+		{front: quotes.Bid, levels: &quotes.BidLevels},
+		{front: quotes.Ask, levels: &quotes.AskLevels},
+	}
+	for _, s := range sides {
+		if s.front == nil {
+			s.done = true
+			continue
+		}
+		notional += s.front.Price * s.front.Size
+		s.held = math.Inf(1)
+		if s.front.Side == types.SELL && m.cfg.QuoteBothTokens {
+			held := pos.YesQty
+			if s.front.TokenID == m.marketInfo.NoTokenID {
+				held = pos.NoQty
+			}
+			s.held = held - s.front.Size
+		}
+	}
+
+	for i := 1; i < m.cfg.LadderLevels; i++ {
+		for _, s := range sides {
+			if s.done {
+				continue
+			}
+			step := float64(i*spacing) * tick
+			if s.front.Side == types.BUY {
+				step = -step
+			}
+			price := math.Round((s.front.Price+step)*pow) / pow
+			size := math.Min(s.front.Size*math.Pow(ratio, float64(i)), s.held)
+			if headroom := remainingBudget - notional; price*size > headroom {
+				size = headroom / price
+			}
+			if price < tick-priceEpsilon || price > 1-tick+priceEpsilon || size < m.marketInfo.MinOrderSize {
+				s.done = true
+				continue
+			}
+
+			level := *s.front
+			level.Price = price
+			level.Size = size
+			*s.levels = append(*s.levels, &level)
+			notional += price * size
+			s.held -= size
+		}
+	}
+}
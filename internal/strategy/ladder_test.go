@@ -0,0 +1,115 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"polymarket-mm/pkg/types"
+)
+
+func ladderQuotes(info types.MarketInfo) *types.QuotePair {
+	return &types.QuotePair{
+		Bid: &types.UserOrder{TokenID: info.YesTokenID, Side: types.BUY, Price: 0.45, Size: 10, TickSize: info.TickSize},
+		Ask: &types.UserOrder{TokenID: info.YesTokenID, Side: types.SELL, Price: 0.55, Size: 10, TickSize: info.TickSize},
+	}
+}
+
+func TestBuildLadderSpacingAndSizeCurve(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.LadderLevels = 3
+	cfg.LadderSpacingTicks = 2
+	cfg.LadderSizeRatio = 1.5
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+
+	quotes := ladderQuotes(info)
+	m.buildLadder(quotes, 1000)
+
+	check := func(name string, levels []*types.UserOrder, prices, sizes []float64) {
+		t.Helper()
+		if len(levels) != len(prices) {
+			t.Fatalf("%s: %d levels, want %d", name, len(levels), len(prices))
+		}
+		for i, l := range levels {
+			if math.Abs(l.Price-prices[i]) > 1e-9 || math.Abs(l.Size-sizes[i]) > 1e-9 {
+				t.Errorf("%s level %d = %v @ %v, want %v @ %v", name, i+1, l.Size, l.Price, sizes[i], prices[i])
+			}
+		}
+	}
+	check("bid", quotes.BidLevels, []float64{0.43, 0.41}, []float64{15, 22.5})
+	check("ask", quotes.AskLevels, []float64{0.57, 0.59}, []float64{15, 22.5})
+
+	// The budget is shared by both sides, one depth at a time: the front
+	// quotes (10) and the first depth (15) use up all 25, so the second
+	// depth is dropped on both sides rather than given to the bid alone
+	quotes = ladderQuotes(info)
+	m.buildLadder(quotes, 25)
+	if len(quotes.BidLevels) != 1 || len(quotes.AskLevels) != 1 {
+		t.Fatalf("levels = %d/%d, want 1/1", len(quotes.BidLevels), len(quotes.AskLevels))
+	}
+}
+
+func TestReconcileLadderLevelByLevel(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.LadderLevels = 3
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	gw := &fakeGateway{}
+	m.client = gw
+	ctx := context.Background()
+
+	quotes := ladderQuotes(info)
+	m.buildLadder(quotes, 1000)
+	if err := m.reconcileOrders(ctx, quotes); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(gw.posted) != 6 || len(m.activeOrders) != 6 {
+		t.Fatalf("posted %d, active %d; want 6 each", len(gw.posted), len(m.activeOrders))
+	}
+
+	// Unchanged ladder: every level keeps its order
+	if err := m.reconcileOrders(ctx, quotes); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(gw.posted) != 6 {
+		t.Fatalf("unchanged ladder posted %d more orders", len(gw.posted)-6)
+	}
+
+	// Moving only the deepest bid replaces only that order
+	quotes.BidLevels[1].Price = 0.40
+	if err := m.reconcileOrders(ctx, quotes); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(gw.posted) != 7 || gw.posted[6].Price != 0.40 || len(m.activeOrders) != 6 {
+		t.Fatalf("posted %v, active %d", gw.posted[6:], len(m.activeOrders))
+	}
+}
+
+func TestReconcileChunksLargeLadders(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.LadderLevels = 10
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	gw := &fakeGateway{}
+	m.client = gw
+
+	quotes := ladderQuotes(info)
+	m.buildLadder(quotes, 1000)
+	if err := m.reconcileOrders(context.Background(), quotes); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(gw.batches) != 2 || gw.batches[0] != types.MaxOrdersPerBatch || gw.batches[1] != 5 {
+		t.Fatalf("batches = %v, want [15 5]", gw.batches)
+	}
+	// The front quotes go out in the first batch
+	if gw.posted[0].Price != 0.45 || gw.posted[1].Price != 0.55 {
+		t.Errorf("first orders = %v, %v", gw.posted[0], gw.posted[1])
+	}
+	if len(m.activeOrders) != 20 {
+		t.Errorf("active orders = %d, want 20", len(m.activeOrders))
+	}
+}
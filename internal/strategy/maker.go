@@ -20,18 +20,46 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"math"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"polymarket-mm/internal/api"
 	"polymarket-mm/internal/config"
-	"polymarket-mm/internal/exchange"
 	"polymarket-mm/internal/market"
 	"polymarket-mm/internal/risk"
 	"polymarket-mm/pkg/types"
 )
 
+// OrderGateway is the subset of the exchange API the Maker needs to manage
+// its quotes. *exchange.Client satisfies it in live trading; dry-run mode
+// uses the paper exchange and the backtester a simulated exchange.
+type OrderGateway interface {
+	PostOrders(ctx context.Context, orders []types.UserOrder, negRisk bool) ([]types.OrderResponse, error)
+	CancelOrders(ctx context.Context, orderIDs []string) (*types.CancelResponse, error)
+	CancelMarketOrders(ctx context.Context, conditionID string) (*types.CancelResponse, error)
+}
+
+// OrderJournal receives order lifecycle records. *store.Journal satisfies
+// it; Record must not block for long since it runs on the quoting path.
+type OrderJournal interface {
+	Record(entry types.JournalEntry)
+}
+
+// StateStore persists a market's inventory state. *store.Store satisfies it.
+type StateStore interface {
+	SaveState(marketID string, state InventoryState) error
+}
+
+// MarkoutStore persists a market's markout statistics. *store.Store
+// satisfies it.
+type MarkoutStore interface {
+	SaveMarkouts(marketID string, state MarkoutState) error
+}
+
 // Maker runs the Avellaneda-Stoikov strategy for a single market.
 // It maintains a map of its own active orders and reconciles them each tick.
 type Maker struct {
@@ -39,18 +67,64 @@ type Maker struct {
 	marketInfo types.MarketInfo
 	book       *market.Book
 	inventory  *Inventory
-	client     *exchange.Client
+	client     OrderGateway
 	riskMgr    *risk.Manager
 
 	// Flow detection (Phase 1)
 	flowTracker *FlowTracker
 
+	// Realized volatility, fed from book updates
+	vol *VolEstimator
+
+	// Post-fill markouts, sampled on book updates and quote cycles
+	markouts *MarkoutTracker
+
+	// Cluster and sweep detectors over the public trade tape (Phase 2)
+	tape *TapeTracker
+
+	// Optional view across the outcomes of this market's neg-risk event
+	event *market.EventBook
+
+	// Resolution taper stage, for logging transitions
+	stage resolutionStage
+
+	// Latest liquidity-rewards estimate, read by the dashboard
+	rewards   RewardEstimate
+	rewardsMu sync.Mutex
+
+	// Wind-down (see drain.go): the window is set by Drain from the engine,
+	// drainDone is owned by Run
+	drainStart    time.Time
+	drainDeadline time.Time
+	drainMu       sync.Mutex
+	drainDone     bool
+	drainLate     bool      // deadline passed with inventory still held
+	lastFlatten   time.Time // last Flatten sent past the deadline
+	drained       chan struct{}
+
 	// Track our outstanding orders
 	activeOrders map[string]types.OpenOrder // orderID -> order
+	placedAt     map[string]time.Time       // orderID -> when we started tracking it
+
+	// Exchange snapshots from the engine's reconciler, applied in Run
+	syncCh chan syncRequest
+
+	// Out-of-cycle requotes (trade tape alerts), run by Run
+	requoteCh chan struct{}
 
 	// Optional dashboard event channel
 	dashboardEvents chan<- api.DashboardEvent
 
+	// Optional order lifecycle journal
+	journal OrderJournal
+
+	// Optional persistence for the position and trade ledger
+	states StateStore
+
+	// Optional persistence for markout statistics
+	markoutStore MarkoutStore
+
+	now    func() time.Time // clock (replaced by the backtester)
 	logger *slog.Logger
 }
 
@@ -60,12 +134,12 @@ func NewMaker(
 	info types.MarketInfo,
 	book *market.Book,
 	inventory *Inventory,
-	client *exchange.Client,
+	client OrderGateway,
 	riskMgr *risk.Manager,
 	logger *slog.Logger,
 	dashboardEvents chan<- api.DashboardEvent,
 ) *Maker {
-	return &Maker{
+	m := &Maker{
 		cfg:             cfg,
 		marketInfo:      info,
 		book:            book,
@@ -73,13 +147,131 @@ func NewMaker(
 		client:          client,
 		riskMgr:         riskMgr,
 		flowTracker:     NewFlowTracker(cfg.FlowWindow, cfg.FlowToxicityThreshold, cfg.FlowCooldownPeriod, cfg.FlowMaxSpreadMultiplier),
+		vol:             NewVolEstimator(cfg.Sigma, cfg.VolHalfLife, cfg.VolSampleInterval, cfg.VolWarmupSamples, cfg.VolFloor, cfg.VolCap),
+		markouts:        NewMarkoutTracker(cfg.MarkoutHorizons, cfg.MarkoutWindow),
+		tape:            NewTapeTracker(cfg),
 		activeOrders:    make(map[string]types.OpenOrder),
+		placedAt:        make(map[string]time.Time),
+		syncCh:          make(chan syncRequest),
+		requoteCh:       make(chan struct{}, 1),
+		drained:         make(chan struct{}),
 		dashboardEvents: dashboardEvents,
+		now:             time.Now,
 		logger: logger.With(
 			"component", "maker",
 			"market", info.Slug,
 		),
 	}
+	if cfg.MarkoutToxicity {
+		m.flowTracker.SetMarkoutSource(m.markouts, markoutToxicScale(cfg))
+	}
+	return m
+}
+
+// SetClock replaces the time source used for fills, reports, flow
+// tracking, and the inventory's trade ledger. The backtester uses it to
+// drive the Maker on simulated time.
+func (m *Maker) SetClock(now func() time.Time) {
+	m.now = now
+	m.inventory.SetClock(now)
+	m.flowTracker.SetClock(now)
+	m.markouts.SetClock(now)
+	m.tape.SetClock(now)
+}
+
+// SetJournal attaches an order lifecycle journal. Call before Run.
+func (m *Maker) SetJournal(j OrderJournal) {
+	m.journal = j
+}
+
+// SetStateStore makes the Maker persist its inventory state whenever a
+// trade is booked or reversed, so the trade ledger survives a crash.
+// Call before Run.
+func (m *Maker) SetStateStore(s StateStore) {
+	m.states = s
+}
+
+// SetMarkoutStore makes the Maker persist its markout statistics on the
+// quote cycle after a markout resolves. Call before Run.
+func (m *Maker) SetMarkoutStore(s MarkoutStore) {
+	m.markoutStore = s
+}
+
+// RestoreMarkouts loads markout statistics saved by a previous run. Call
+// before Run.
+func (m *Maker) RestoreMarkouts(state MarkoutState) {
+	m.markouts.Restore(state)
+}
+
+// SetEventBook attaches the view of the market's neg-risk event, used to
+// keep quotes coherent with the event's other outcomes. Call before Run.
+func (m *Maker) SetEventBook(eb *market.EventBook) {
+	m.event = eb
+}
+
+// AdoptOrders starts tracking orders that were already resting on the
+// exchange (found by startup reconciliation), so the first quote cycle
+// keeps or cancels them like any other order. Call before Run.
+func (m *Maker) AdoptOrders(orders []types.OpenOrder) {
+	for _, o := range orders {
+		m.activeOrders[o.ID] = o
+		m.placedAt[o.ID] = m.now()
+	}
+}
+
+// Step runs a single quote cycle synchronously. The backtester calls it on
+// each simulated RefreshInterval instead of running the ticker in Run.
+func (m *Maker) Step(ctx context.Context) {
+	m.quoteUpdate(ctx)
+}
+
+// HandleTrade applies a fill outside of Run (used by the backtester).
+func (m *Maker) HandleTrade(trade types.WSTradeEvent) {
+	m.handleFill(trade)
+}
+
+// HandleOrderEvent applies an order lifecycle event outside of Run
+// (used by the backtester).
+func (m *Maker) HandleOrderEvent(event types.WSOrderEvent) {
+	m.handleOrderEvent(event)
+}
+
+// ObserveBook samples the market mid into the volatility estimator and the
+// markout tracker. The engine and backtester call it after every book
+// update; it only reads the book, so it is safe to call from outside the
+// Run goroutine.
+//
+// Volatility is measured on the market mid, not fairPrice: the fair-value
+// offset moves with queue sizes at the touch and event coherence with each
+// scan's reference prices, neither of which is a price move.
+func (m *Maker) ObserveBook() {
+	if mid, ok := m.marketMid(); ok {
+		m.vol.Observe(m.now(), mid)
+	}
+	m.observeMarkouts()
+}
+
+// Volatility returns the current volatility estimate for this market.
+func (m *Maker) Volatility() VolEstimate {
+	return m.vol.Estimate()
+}
+
+// Markouts returns the lifetime markout statistics per side and horizon.
+func (m *Maker) Markouts() []MarkoutStat {
+	return m.markouts.Stats()
+}
+
+// Rewards returns the latest liquidity-rewards estimate (zero if the
+// market has no rewards program).
+func (m *Maker) Rewards() RewardEstimate {
+	m.rewardsMu.Lock()
+	defer m.rewardsMu.Unlock()
+	return m.rewards
+}
+
+// Toxicity returns the current flow toxicity metrics for this market.
+func (m *Maker) Toxicity() ToxicityMetrics {
+	return m.flowTracker.CalculateToxicity()
 }
 
 // Run is the main loop for this market. Blocks until ctx is cancelled.
@@ -96,6 +288,7 @@ func (m *Maker) Run(ctx context.Context, tradeCh <-chan types.WSTradeEvent, orde
 		select {
 		case <-ctx.Done():
 			m.cancelAllMyOrders(context.Background())
+			m.saveMarkouts()
 			m.logger.Info("strategy stopped")
 			return
 
@@ -105,6 +298,12 @@ func (m *Maker) Run(ctx context.Context, tradeCh <-chan types.WSTradeEvent, orde
 		case order := <-orderCh:
 			m.handleOrderEvent(order)
 
+		case req := <-m.syncCh:
+			req.reply <- m.applySync(ctx, req.view)
+
+		case <-m.requoteCh:
+			m.quoteUpdate(ctx)
+
 		case <-ticker.C:
 			m.quoteUpdate(ctx)
 		}
@@ -113,6 +312,12 @@ func (m *Maker) Run(ctx context.Context, tradeCh <-chan types.WSTradeEvent, orde
 
 // quoteUpdate is the core per-tick logic.
 func (m *Maker) quoteUpdate(ctx context.Context) {
+	if m.drainOverdue(ctx) {
+		return
+	}
+	m.observeMarkouts()
+	m.saveMarkouts()
+
 	// 1. Check if book is stale
 	if m.book.IsStale(m.cfg.StaleBookTimeout) {
 		m.logger.Warn("book is stale, cancelling all orders")
@@ -121,7 +326,7 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 	}
 
 	// 2. Check risk limits
-	mid, ok := m.book.MidPrice()
+	mid, ok := m.fairPrice()
 	if !ok {
 		m.logger.Debug("no mid price available")
 		return
@@ -140,7 +345,7 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 		ExposureUSD:   exposureUSD,
 		UnrealizedPnL: pos.UnrealizedPnL,
 		RealizedPnL:   pos.RealizedPnL,
-		Timestamp:     time.Now(),
+		Timestamp:     m.now(),
 	})
 
 	// Emit position event to dashboard
@@ -157,7 +362,7 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 	}
 	m.emitDashboardEvent(api.DashboardEvent{
 		Type:      "position",
-		Timestamp: time.Now(),
+		Timestamp: m.now(),
 		MarketID:  m.marketInfo.ConditionID,
 		Data:      api.NewPositionEvent(posSnapshot, m.marketInfo.Slug, mid),
 	})
@@ -168,6 +373,26 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 		return
 	}
 
+	_, stage := m.horizon()
+	if stage != m.stage {
+		m.logger.Info("resolution stage changed",
+			"from", m.stage,
+			"to", stage,
+			"end_date", m.marketInfo.EndDate,
+		)
+		m.stage = stage
+	}
+	if stage == stageStopped {
+		m.cancelAllMyOrders(ctx)
+		return
+	}
+
+	// A draining market only reduces, so the risk budget doesn't apply
+	if start, deadline, ok := m.drainWindow(); ok {
+		m.drainQuotes(ctx, mid, start, deadline)
+		return
+	}
+
 	remaining := m.riskMgr.RemainingBudget(m.marketInfo.ConditionID)
 	if remaining <= 0 {
 		m.logger.Info("risk budget exhausted")
@@ -181,6 +406,23 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 		m.logger.Error("compute quotes failed", "error", err)
 		return
 	}
+	m.applyRewards(quotes, mid, remaining)
+	if m.rewardsMaxSpread() > 0 {
+		est := m.estimateRewards(quotes, mid)
+		m.rewardsMu.Lock()
+		m.rewards = est
+		m.rewardsMu.Unlock()
+		m.logger.Debug("liquidity rewards estimate",
+			"share", est.Share,
+			"expected_daily", est.ExpectedDaily,
+			"our_score", est.OurScore,
+			"book_score", est.BookScore,
+		)
+	}
+	if m.cfg.QuoteBothTokens {
+		quotes = m.routeQuotes(quotes, remaining)
+	}
+	m.buildLadder(quotes, remaining)
 
 	// 4. Reconcile orders (cancel stale, place new)
 	if err := m.reconcileOrders(ctx, quotes); err != nil {
@@ -188,15 +430,41 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 	}
 }
 
+// fairPrice returns the YES reference price for quoting: the YES mid, or
+// the synthetic mid across both books when quoting both tokens, shifted by
+// the configured fair-value estimator (see fair_value.go). With
+// EventCoherence, an outcome of a neg-risk event is rescaled so the event's
+// implied probabilities sum to 1; quotes around coherent fair prices then
+// never sum to an arbitrage across outcomes.
+func (m *Maker) fairPrice() (float64, bool) {
+	mid, ok := m.marketMid()
+	if ok {
+		mid += m.fairValueOffset()
+	}
+	if ok && m.cfg.EventCoherence && m.event != nil {
+		mid, _ = m.event.CoherentPrice(m.marketInfo.ConditionID, mid)
+	}
+	return mid, ok
+}
+
+// marketMid is the book's YES mid: the synthetic mid across both books when
+// quoting both tokens, the YES book's mid otherwise.
+func (m *Maker) marketMid() (float64, bool) {
+	if m.cfg.QuoteBothTokens {
+		return m.book.SyntheticMidPrice()
+	}
+	return m.book.MidPrice()
+}
+
 // computeQuotes implements the Avellaneda-Stoikov model for binary markets.
 //
 // Variables:
 //
 //	q     = inventory skew in [-1, 1] from NetDelta()
 //	gamma = risk aversion (higher = tighter spread, less inventory risk)
-//	sigma = estimated volatility
+//	sigma = realized volatility from the online estimator (cfg.Sigma until warm)
 //	k     = order arrival intensity
-//	T     = time horizon
+//	T     = time to resolution in years, capped at cfg.T (see horizon)
 //
 // Formulas:
 //
@@ -207,35 +475,51 @@ func (m *Maker) quoteUpdate(ctx context.Context) {
 func (m *Maker) computeQuotes(mid, remainingBudget float64) (*types.QuotePair, error) {
 	q := m.inventory.NetDelta() // [-1, 1]
 	gamma := m.cfg.Gamma
-	sigma := m.cfg.Sigma
+	sigma := m.vol.Sigma()
 	k := m.cfg.K
-	T := m.cfg.T
+	T, stage := m.horizon()
 	minSpread := float64(m.cfg.DefaultSpreadBps) / 10000.0
 	tickDec := m.marketInfo.TickSize.Decimals()
 	tick := math.Pow(10, -float64(tickDec))
 
-	// Phase 1: Apply flow toxicity adjustment
-	flowMultiplier := m.flowTracker.GetSpreadMultiplier()
-	minSpread *= flowMultiplier
+	// Phase 1: Flow toxicity widens each side by its own multiplier
+	flow := m.flowTracker.Assess()
+
+	// Phase 2: the trade tape widens a side flow is heading into
+	tapeBid, tapeAsk := m.tapeMultipliers()
+	bidMult := flow.BidMultiplier * tapeBid
+	askMult := flow.AskMultiplier * tapeAsk
+
+	// Widen and shrink as resolution approaches
+	taperSpread, taperSize := m.taperMultipliers(stage)
+	minSpread *= taperSpread
+
+	// The configured spread is edge on top of fees: never quote below
+	// break-even on fee-bearing markets
+	fee := m.roundTripFee(mid)
 
 	// Step 1: Reservation price
 	// r = mid - q * gamma * sigma^2 * T
 	reservationPrice := mid - q*gamma*sigma*sigma*T
 
-	// Step 2: Optimal spread (with toxicity adjustment)
+	// Step 2: Optimal spread
 	// delta = gamma * sigma^2 * T + (2/gamma) * ln(1 + gamma/k)
 	optSpread := gamma*sigma*sigma*T + (2.0/gamma)*math.Log(1+gamma/k)
-	optSpread *= flowMultiplier // Widen spread when flow is toxic
+	optSpread *= taperSpread
 
-	// Step 3: Raw bid/ask
-	bidRaw := reservationPrice - optSpread/2
-	askRaw := reservationPrice + optSpread/2
+	// Step 3: Half-spread per side, enforcing the minimum spread, widened
+	// by that side's flow and tape multipliers (the fee floor is not widened)
+	bidHalf := math.Max(optSpread*bidMult, minSpread*bidMult+fee) / 2
+	askHalf := math.Max(optSpread*askMult, minSpread*askMult+fee) / 2
 
-	// Step 4: Enforce minimum spread
-	if (askRaw - bidRaw) < minSpread {
-		bidRaw = reservationPrice - minSpread/2
-		askRaw = reservationPrice + minSpread/2
-	}
+	// Step 4: Raw bid/ask
+	bidRaw := reservationPrice - bidHalf
+	askRaw := reservationPrice + askHalf
+
+	// Widen the side the book is leaning against
+	bidWiden, askWiden, imbalance := m.imbalanceSkew()
+	bidRaw -= bidWiden
+	askRaw += askWiden
 
 	// Step 5: Clamp to valid price range [tick, 1-tick]
 	bidRaw = clamp(bidRaw, tick, 1-tick)
@@ -261,6 +545,7 @@ func (m *Maker) computeQuotes(mid, remainingBudget float64) (*types.QuotePair, e
 	// Step 7: Compute size
 	absQ := math.Abs(q)
 	sizeFactor := 1.0 - 0.5*absQ // reduce size when heavily positioned
+	sizeFactor *= taperSize
 	baseSize := m.cfg.OrderSizeUSD / mid
 	bidSize := math.Max(baseSize*sizeFactor, m.marketInfo.MinOrderSize)
 	askSize := math.Max(baseSize*sizeFactor, m.marketInfo.MinOrderSize)
@@ -303,22 +588,50 @@ func (m *Maker) computeQuotes(mid, remainingBudget float64) (*types.QuotePair, e
 		}
 	}
 
-	// Get toxicity metrics for logging
-	toxicity := m.flowTracker.CalculateToxicity()
+	// Pull a side that toxic flow is picking off hard enough
+	if pull := m.cfg.FlowPullMultiplier; pull > 0 {
+		if flow.BidMultiplier >= pull {
+			bid = nil
+		}
+		if flow.AskMultiplier >= pull {
+			ask = nil
+		}
+	}
+	// Pull a side the trade tape is defending
+	if m.cfg.TapePull {
+		if m.tape.Threatened(types.BUY) {
+			bid = nil
+		}
+		if m.tape.Threatened(types.SELL) {
+			ask = nil
+		}
+	}
+
+	if stage >= stageReduceOnly {
+		bid, ask = m.reduceOnly(bid, ask)
+	}
 
 	m.logger.Debug("quotes computed",
 		"mid", mid,
 		"q", q,
 		"reservation", reservationPrice,
+		"sigma", sigma,
 		"bid", bidPrice,
 		"ask", askPrice,
 		"bid_size", bidSize,
 		"ask_size", askSize,
 		"spread", askPrice-bidPrice,
-		"toxicity_score", toxicity.ToxicityScore,
-		"directional_imbalance", toxicity.DirectionalImbalance,
-		"fill_velocity", toxicity.FillVelocity,
-		"flow_spread_multiplier", flowMultiplier,
+		"toxicity_score", flow.ToxicityScore,
+		"directional_imbalance", flow.DirectionalImbalance,
+		"flow_direction", flow.Direction,
+		"fill_velocity", flow.FillVelocity,
+		"flow_bid_multiplier", flow.BidMultiplier,
+		"flow_ask_multiplier", flow.AskMultiplier,
+		"tape_bid_multiplier", tapeBid,
+		"tape_ask_multiplier", tapeAsk,
+		"imbalance", imbalance,
+		"horizon", T,
+		"stage", stage,
 	)
 
 	return &types.QuotePair{
@@ -327,13 +640,15 @@ func (m *Maker) computeQuotes(mid, remainingBudget float64) (*types.QuotePair, e
 		NoTokenID:   m.marketInfo.NoTokenID,
 		Bid:         bid,
 		Ask:         ask,
-		GeneratedAt: time.Now(),
+		GeneratedAt: m.now(),
 	}, nil
 }
 
-// reconcileOrders diffs desired quotes against active orders.
-// An existing order is kept if its price is within one tick and its remaining
-// size is within 10% of the desired size. Everything else is cancelled.
+// reconcileOrders diffs desired quotes against active orders, level by
+// level. Each desired order, nearest the touch first, keeps the closest
+// unclaimed active order on the same token and side whose price is within
+// one tick and whose remaining size is within 10% of the desired size.
+// Everything left unclaimed is cancelled.
 // New orders are placed via the batch POST /orders endpoint.
 func (m *Maker) reconcileOrders(ctx context.Context, desired *types.QuotePair) error {
 	tick := math.Pow(10, -float64(m.marketInfo.TickSize.Decimals()))
@@ -341,40 +656,47 @@ func (m *Maker) reconcileOrders(ctx context.Context, desired *types.QuotePair) e
 
 	var toCancel []string
 	var toPlace []types.UserOrder
-	matchedBid := false
-	matchedAsk := false
-
-	// Check each active order against desired quotes
-	for id, order := range m.activeOrders {
-		orderPrice, _ := strconv.ParseFloat(order.Price, 64)
-		orderSizeOrig, _ := strconv.ParseFloat(order.OriginalSize, 64)
-		orderSizeMatched, _ := strconv.ParseFloat(order.SizeMatched, 64)
-		remainingSize := orderSizeOrig - orderSizeMatched
-
-		if order.Side == "BUY" && desired.Bid != nil {
-			if math.Abs(orderPrice-desired.Bid.Price) <= tick &&
-				math.Abs(remainingSize-desired.Bid.Size)/desired.Bid.Size <= sizeTolerance {
-				matchedBid = true
-				continue
-			}
+	claimed := make(map[string]bool)
+	ids := slices.Sorted(maps.Keys(m.activeOrders))
+
+	// GTD quotes are replaced before they lapse
+	for _, id := range ids {
+		if m.expiresSoon(m.activeOrders[id]) {
+			claimed[id] = true
+			toCancel = append(toCancel, id)
 		}
-		if order.Side == "SELL" && desired.Ask != nil {
-			if math.Abs(orderPrice-desired.Ask.Price) <= tick &&
-				math.Abs(remainingSize-desired.Ask.Size)/desired.Ask.Size <= sizeTolerance {
-				matchedAsk = true
+	}
+
+	for _, want := range desired.Orders() {
+		match, bestDist := "", math.Inf(1)
+		for _, id := range ids {
+			order := m.activeOrders[id]
+			if claimed[id] || !sameLeg(order, want) {
 				continue
 			}
+			orderPrice, _ := strconv.ParseFloat(order.Price, 64)
+			orderSizeOrig, _ := strconv.ParseFloat(order.OriginalSize, 64)
+			orderSizeMatched, _ := strconv.ParseFloat(order.SizeMatched, 64)
+			remainingSize := orderSizeOrig - orderSizeMatched
+
+			dist := math.Abs(orderPrice - want.Price)
+			if dist <= tick && dist < bestDist &&
+				math.Abs(remainingSize-want.Size)/want.Size <= sizeTolerance {
+				match, bestDist = id, dist
+			}
 		}
-
-		// Order doesn't match any desired quote, cancel it
-		toCancel = append(toCancel, id)
+		if match != "" {
+			claimed[match] = true
+			continue
+		}
+		toPlace = append(toPlace, *want)
 	}
 
-	if !matchedBid && desired.Bid != nil {
-		toPlace = append(toPlace, *desired.Bid)
-	}
-	if !matchedAsk && desired.Ask != nil {
-		toPlace = append(toPlace, *desired.Ask)
+	// Orders that don't match any desired quote are cancelled
+	for _, id := range ids {
+		if !claimed[id] {
+			toCancel = append(toCancel, id)
+		}
 	}
 
 	// Cancel stale orders
@@ -384,57 +706,143 @@ func (m *Maker) reconcileOrders(ctx context.Context, desired *types.QuotePair) e
 			return fmt.Errorf("cancel orders: %w", err)
 		}
 		for _, id := range resp.Canceled {
-			delete(m.activeOrders, id)
+			m.journalCancel(id, "requote")
+			m.forgetOrder(id)
 		}
 	}
 
-	// Place new orders
-	if len(toPlace) > 0 {
-		results, err := m.client.PostOrders(ctx, toPlace, m.marketInfo.NegRisk)
-		if err != nil {
-			return fmt.Errorf("place orders: %w", err)
+	// Place new orders, front levels first, in batches the exchange accepts
+	for batch := range slices.Chunk(toPlace, types.MaxOrdersPerBatch) {
+		if err := m.placeQuotes(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// placeQuotes posts one batch of resting quotes and tracks the accepted ones.
+func (m *Maker) placeQuotes(ctx context.Context, orders []types.UserOrder) error {
+	for i := range orders {
+		m.stampQuote(&orders[i])
+		m.journalOrder(types.JournalPlacement, "", orders[i], "", "")
+	}
+	results, err := m.client.PostOrders(ctx, orders, m.marketInfo.NegRisk)
+	if err != nil {
+		for _, order := range orders {
+			m.journalOrder(types.JournalRejection, "", order, "", err.Error())
 		}
-		for i, result := range results {
-			if result.Success && result.OrderID != "" {
-				m.activeOrders[result.OrderID] = types.OpenOrder{
-					ID:           result.OrderID,
-					Status:       result.Status,
-					Market:       m.marketInfo.ConditionID,
-					AssetID:      toPlace[i].TokenID,
-					Side:         string(toPlace[i].Side),
-					Price:        fmt.Sprintf("%.4f", toPlace[i].Price),
-					OriginalSize: fmt.Sprintf("%.2f", toPlace[i].Size),
-					SizeMatched:  "0",
-				}
-			} else if result.ErrorMsg != "" {
-				m.logger.Error("order rejected",
-					"error", result.ErrorMsg,
-					"side", toPlace[i].Side,
-					"price", toPlace[i].Price,
-				)
+		return fmt.Errorf("place orders: %w", err)
+	}
+	for i, result := range results {
+		if result.Success && result.OrderID != "" {
+			m.journalOrder(types.JournalAck, result.OrderID, orders[i], result.Status, "")
+			m.activeOrders[result.OrderID] = types.OpenOrder{
+				ID:           result.OrderID,
+				Status:       result.Status,
+				Market:       m.marketInfo.ConditionID,
+				AssetID:      orders[i].TokenID,
+				Side:         string(orders[i].Side),
+				Price:        fmt.Sprintf("%.4f", orders[i].Price),
+				OriginalSize: fmt.Sprintf("%.2f", orders[i].Size),
+				SizeMatched:  "0",
+				Expiration:   strconv.FormatInt(orders[i].Expiration, 10),
 			}
+			m.placedAt[result.OrderID] = m.now()
+		} else if result.ErrorMsg != "" || result.Err != nil {
+			m.journalOrder(types.JournalRejection, "", orders[i], result.Status, result.ErrorMsg)
+			m.logRejection(orders[i], rejection(result))
 		}
 	}
-
 	return nil
 }
 
+// outcome names the token's outcome for fill reporting.
+func (m *Maker) outcome(tokenID string) string {
+	if tokenID == m.marketInfo.YesTokenID {
+		return "Yes"
+	}
+	return "No"
+}
+
+// sameLeg reports whether a resting order is on the same token and side as a
+// desired quote.
+func sameLeg(order types.OpenOrder, want *types.UserOrder) bool {
+	return order.AssetID == want.TokenID && order.Side == string(want.Side)
+}
+
 // handleFill processes a trade event from the user WS channel.
 func (m *Maker) handleFill(trade types.WSTradeEvent) {
 	price, _ := strconv.ParseFloat(trade.Price, 64)
 	size, _ := strconv.ParseFloat(trade.Size, 64)
 
 	fill := Fill{
-		Timestamp: time.Now(),
+		Timestamp: m.now(),
 		Side:      types.Side(trade.Side),
 		TokenID:   trade.AssetID,
 		Price:     price,
 		Size:      size,
 		TradeID:   trade.ID,
+		Fee:       tradeFee(trade.FeeRateBps, price, size),
 	}
 
-	m.inventory.OnFill(fill)
-	m.flowTracker.AddFill(fill) // Track for toxicity detection
+	m.applyTrade(TradeFills{TradeID: trade.ID, Status: trade.Status, Fills: []Fill{fill}})
+}
+
+// applyTrade books a trade through the inventory ledger, which ignores
+// status updates and redeliveries (after WS reconnects, or a trade the
+// reconciler already booked) and reverses trades whose settlement failed.
+func (m *Maker) applyTrade(t TradeFills) TradeAction {
+	action := m.inventory.ApplyTrade(t)
+	switch action {
+	case TradeBooked:
+		for _, fill := range t.Fills {
+			m.reportFill(fill, t.Status)
+		}
+		m.persistState()
+	case TradeReversed:
+		m.logger.Warn("trade settlement failed, fill reversed", "trade_id", t.TradeID)
+		m.record(types.JournalEntry{
+			Type:    types.JournalTrade,
+			TradeID: t.TradeID,
+			Status:  t.Status,
+			Reason:  "settlement_failed",
+		})
+		m.persistState()
+	case TradeUpdated:
+		m.record(types.JournalEntry{Type: types.JournalTrade, TradeID: t.TradeID, Status: t.Status})
+	default:
+		m.logger.Debug("trade already booked", "trade_id", t.TradeID, "status", t.Status)
+	}
+	return action
+}
+
+// persistState saves the inventory state, if a store is attached.
+func (m *Maker) persistState() {
+	if m.states == nil {
+		return
+	}
+	if err := m.states.SaveState(m.marketInfo.ConditionID, m.inventory.State()); err != nil {
+		m.logger.Error("failed to save inventory state", "error", err)
+	}
+}
+
+// reportFill tracks, journals, logs and publishes a newly booked fill.
+func (m *Maker) reportFill(fill Fill, status string) {
+	price, size := fill.Price, fill.Size
+	outcome := m.outcome(fill.TokenID)
+	economic := m.economicFill(fill)
+	m.flowTracker.AddFill(economic) // Track for toxicity detection
+	m.markouts.AddFill(economic)
+	m.record(types.JournalEntry{
+		Type:    types.JournalTrade,
+		TradeID: fill.TradeID,
+		TokenID: fill.TokenID,
+		Side:    fill.Side,
+		Price:   price,
+		Size:    size,
+		Status:  status,
+	})
 
 	pos := m.inventory.Snapshot()
 
@@ -442,7 +850,8 @@ func (m *Maker) handleFill(trade types.WSTradeEvent) {
 	toxicity := m.flowTracker.CalculateToxicity()
 	if toxicity.IsAverse {
 		m.logger.Warn("toxic flow detected",
-			"side", trade.Side,
+			"side", fill.Side,
+			"direction", toxicity.Direction,
 			"toxicity_score", toxicity.ToxicityScore,
 			"directional_imbalance", toxicity.DirectionalImbalance,
 			"fill_velocity", toxicity.FillVelocity,
@@ -451,17 +860,18 @@ func (m *Maker) handleFill(trade types.WSTradeEvent) {
 	}
 
 	m.logger.Info("fill",
-		"side", trade.Side,
+		"side", fill.Side,
+		"trade_id", fill.TradeID,
 		"price", price,
 		"size", size,
-		"outcome", trade.Outcome,
+		"outcome", outcome,
 		"yes_qty", pos.YesQty,
 		"no_qty", pos.NoQty,
 		"realized_pnl", pos.RealizedPnL,
 	)
 
 	// Emit fill event to dashboard
-	mid, _ := m.book.MidPrice()
+	mid, _ := m.fairPrice()
 	unrealizedPnL := pos.YesQty*(mid-pos.AvgEntryYes) + pos.NoQty*((1-mid)-pos.AvgEntryNo)
 
 	posSnapshot := api.PositionSnapshot{
@@ -476,9 +886,12 @@ func (m *Maker) handleFill(trade types.WSTradeEvent) {
 
 	m.emitDashboardEvent(api.DashboardEvent{
 		Type:      "fill",
-		Timestamp: time.Now(),
+		Timestamp: m.now(),
 		MarketID:  m.marketInfo.ConditionID,
-		Data:      api.NewFillEvent(trade, posSnapshot, m.marketInfo.Slug, price, size),
+		Data: api.NewFillEvent(
+			types.WSTradeEvent{ID: fill.TradeID, Side: string(fill.Side), Outcome: outcome},
+			posSnapshot, m.marketInfo.Slug, price, size,
+		),
 	})
 }
 
@@ -486,11 +899,37 @@ func (m *Maker) handleFill(trade types.WSTradeEvent) {
 func (m *Maker) handleOrderEvent(event types.WSOrderEvent) {
 	switch event.Type {
 	case "CANCELLATION":
-		delete(m.activeOrders, event.ID)
+		// Our own cancels are journaled when the cancel request succeeds
+		if _, ok := m.activeOrders[event.ID]; ok {
+			m.journalCancel(event.ID, "exchange")
+		}
+		m.forgetOrder(event.ID)
 	case "UPDATE":
 		if order, ok := m.activeOrders[event.ID]; ok {
 			order.SizeMatched = event.SizeMatched
+			if event.OriginalSize != "" {
+				order.OriginalSize = event.OriginalSize // exchange-truncated size is authoritative
+			}
 			m.activeOrders[event.ID] = order
+
+			// Fully matched orders no longer rest on the book
+			orig, _ := strconv.ParseFloat(order.OriginalSize, 64)
+			matched, _ := strconv.ParseFloat(event.SizeMatched, 64)
+			price, _ := strconv.ParseFloat(order.Price, 64)
+			entryType := types.JournalPartialFill
+			if orig > 0 && matched >= orig {
+				entryType = types.JournalFill
+				m.forgetOrder(event.ID)
+			}
+			m.record(types.JournalEntry{
+				Type:        entryType,
+				OrderID:     event.ID,
+				TokenID:     order.AssetID,
+				Side:        types.Side(order.Side),
+				Price:       price,
+				Size:        orig,
+				SizeMatched: matched,
+			})
 		}
 	case "PLACEMENT":
 		if _, ok := m.activeOrders[event.ID]; !ok {
@@ -503,6 +942,7 @@ func (m *Maker) handleOrderEvent(event types.WSOrderEvent) {
 				OriginalSize: event.OriginalSize,
 				SizeMatched:  event.SizeMatched,
 			}
+			m.placedAt[event.ID] = m.now()
 		}
 	}
 }
@@ -520,12 +960,61 @@ func (m *Maker) cancelAllMyOrders(ctx context.Context) {
 	}
 
 	for _, id := range resp.Canceled {
-		delete(m.activeOrders, id)
+		m.journalCancel(id, "pull_quotes")
+		m.forgetOrder(id)
 	}
 
 	m.logger.Info("cancelled orders", "count", len(resp.Canceled))
 }
 
+// forgetOrder stops tracking an order that no longer rests on the book.
+func (m *Maker) forgetOrder(id string) {
+	delete(m.activeOrders, id)
+	delete(m.placedAt, id)
+}
+
+// record stamps and writes a journal entry for this market, if journaling.
+func (m *Maker) record(entry types.JournalEntry) {
+	if m.journal == nil {
+		return
+	}
+	entry.Time = m.now()
+	entry.Market = m.marketInfo.ConditionID
+	m.journal.Record(entry)
+}
+
+// journalOrder records a placement, ack or rejection for a desired order.
+func (m *Maker) journalOrder(t types.JournalEventType, orderID string, order types.UserOrder, status, reason string) {
+	m.record(types.JournalEntry{
+		Type:    t,
+		OrderID: orderID,
+		TokenID: order.TokenID,
+		Side:    order.Side,
+		Price:   order.Price,
+		Size:    order.Size,
+		Status:  status,
+		Reason:  reason,
+	})
+}
+
+// journalCancel records the removal of one of our resting orders.
+func (m *Maker) journalCancel(orderID, reason string) {
+	order := m.activeOrders[orderID]
+	price, _ := strconv.ParseFloat(order.Price, 64)
+	size, _ := strconv.ParseFloat(order.OriginalSize, 64)
+	matched, _ := strconv.ParseFloat(order.SizeMatched, 64)
+	m.record(types.JournalEntry{
+		Type:        types.JournalCancel,
+		OrderID:     orderID,
+		TokenID:     order.AssetID,
+		Side:        types.Side(order.Side),
+		Price:       price,
+		Size:        size,
+		SizeMatched: matched,
+		Reason:      reason,
+	})
+}
+
 func clamp(v, lo, hi float64) float64 {
 	if v < lo {
 		return lo
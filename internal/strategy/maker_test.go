@@ -1,6 +1,8 @@
 package strategy
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"math"
 	"os"
@@ -51,7 +53,14 @@ func setupMaker(cfg config.StrategyConfig, info types.MarketInfo) *Maker {
 		book:         b,
 		inventory:    inv,
 		flowTracker:  NewFlowTracker(cfg.FlowWindow, cfg.FlowToxicityThreshold, cfg.FlowCooldownPeriod, cfg.FlowMaxSpreadMultiplier),
+		vol:          NewVolEstimator(cfg.Sigma, cfg.VolHalfLife, cfg.VolSampleInterval, cfg.VolWarmupSamples, cfg.VolFloor, cfg.VolCap),
+		markouts:     NewMarkoutTracker(cfg.MarkoutHorizons, cfg.MarkoutWindow),
+		tape:         NewTapeTracker(cfg),
 		activeOrders: make(map[string]types.OpenOrder),
+		placedAt:     make(map[string]time.Time),
+		requoteCh:    make(chan struct{}, 1),
+		drained:      make(chan struct{}),
+		now:          time.Now,
 		logger:       logger,
 	}
 }
@@ -242,3 +251,307 @@ func TestComputeQuotesBidBelowAsk(t *testing.T) {
 		}
 	}
 }
+
+// fakeGateway accepts every order and cancel, assigning sequential IDs.
+type fakeGateway struct {
+	next    int
+	reject  string // if set, every order is rejected with this message
+	posted  []types.UserOrder
+	batches []int // size of each PostOrders call
+}
+
+func (g *fakeGateway) PostOrders(_ context.Context, orders []types.UserOrder, _ bool) ([]types.OrderResponse, error) {
+	if len(orders) > types.MaxOrdersPerBatch {
+		return nil, fmt.Errorf("batch limit is %d orders, got %d", types.MaxOrdersPerBatch, len(orders))
+	}
+	g.posted = append(g.posted, orders...)
+	g.batches = append(g.batches, len(orders))
+	out := make([]types.OrderResponse, len(orders))
+	for i := range orders {
+		if g.reject != "" {
+			out[i] = types.OrderResponse{ErrorMsg: g.reject}
+			continue
+		}
+		g.next++
+		out[i] = types.OrderResponse{Success: true, OrderID: fmt.Sprintf("o%d", g.next), Status: "live"}
+	}
+	return out, nil
+}
+
+func (g *fakeGateway) CancelOrders(_ context.Context, ids []string) (*types.CancelResponse, error) {
+	return &types.CancelResponse{Canceled: ids}, nil
+}
+
+func (g *fakeGateway) CancelMarketOrders(_ context.Context, _ string) (*types.CancelResponse, error) {
+	return &types.CancelResponse{}, nil
+}
+
+type memJournal struct{ entries []types.JournalEntry }
+
+func (j *memJournal) Record(e types.JournalEntry) { j.entries = append(j.entries, e) }
+
+func (j *memJournal) kinds() []types.JournalEventType {
+	out := make([]types.JournalEventType, len(j.entries))
+	for i, e := range j.entries {
+		out[i] = e.Type
+	}
+	return out
+}
+
+func TestPlacedAtForgetsOrdersThatLeave(t *testing.T) {
+	t.Parallel()
+	m := setupMaker(testStrategyConfig(), testMarketInfo())
+	m.client = &fakeGateway{}
+	ctx := context.Background()
+
+	// Requotes at new prices cancel the old orders
+	for i := 0; i < 5; i++ {
+		bid := &types.UserOrder{TokenID: "yes-token", Price: 0.40 + 0.01*float64(i), Size: 10, Side: types.BUY}
+		ask := &types.UserOrder{TokenID: "yes-token", Price: 0.60 + 0.01*float64(i), Size: 10, Side: types.SELL}
+		if err := m.reconcileOrders(ctx, &types.QuotePair{Bid: bid, Ask: ask}); err != nil {
+			t.Fatalf("reconcileOrders: %v", err)
+		}
+	}
+	if len(m.placedAt) != 2 {
+		t.Fatalf("placedAt tracks %d orders after requotes, want 2", len(m.placedAt))
+	}
+
+	// An exchange cancel and a full fill
+	m.handleOrderEvent(types.WSOrderEvent{ID: "o9", Type: "CANCELLATION"})
+	m.handleOrderEvent(types.WSOrderEvent{ID: "o10", Type: "UPDATE", SizeMatched: "10"})
+	if len(m.activeOrders) != 0 || len(m.placedAt) != 0 {
+		t.Fatalf("active %v, placedAt %v; want both empty", m.activeOrders, m.placedAt)
+	}
+
+	// Pulling quotes
+	m.handleOrderEvent(types.WSOrderEvent{ID: "x1", Type: "PLACEMENT", AssetID: "yes-token"})
+	m.client = &cancelAllGateway{}
+	m.cancelAllMyOrders(ctx)
+	if len(m.activeOrders) != 0 || len(m.placedAt) != 0 {
+		t.Errorf("active %v, placedAt %v after pulling quotes; want both empty", m.activeOrders, m.placedAt)
+	}
+}
+
+// cancelAllGateway cancels every order id it is asked about, and reports
+// x1 for market-wide cancels.
+type cancelAllGateway struct{ fakeGateway }
+
+func (g *cancelAllGateway) CancelMarketOrders(_ context.Context, _ string) (*types.CancelResponse, error) {
+	return &types.CancelResponse{Canceled: []string{"x1"}}, nil
+}
+
+func TestMakerJournalsOrderLifecycle(t *testing.T) {
+	t.Parallel()
+	m := setupMaker(testStrategyConfig(), testMarketInfo())
+	gw := &fakeGateway{}
+	journal := &memJournal{}
+	m.client = gw
+	m.SetJournal(journal)
+
+	bid := &types.UserOrder{TokenID: "yes-token", Price: 0.45, Size: 10, Side: types.BUY}
+	if err := m.reconcileOrders(context.Background(), &types.QuotePair{Bid: bid}); err != nil {
+		t.Fatalf("reconcileOrders: %v", err)
+	}
+	m.handleOrderEvent(types.WSOrderEvent{ID: "o1", Type: "UPDATE", SizeMatched: "4"})
+	m.handleFill(types.WSTradeEvent{ID: "t1", AssetID: "yes-token", Side: "BUY", Price: "0.45", Size: "4", Status: "MATCHED"})
+
+	// Requote at a new price: the old order is cancelled
+	bid = &types.UserOrder{TokenID: "yes-token", Price: 0.40, Size: 10, Side: types.BUY}
+	if err := m.reconcileOrders(context.Background(), &types.QuotePair{Bid: bid}); err != nil {
+		t.Fatalf("reconcileOrders: %v", err)
+	}
+
+	gw.reject = "not enough balance / allowance"
+	ask := &types.UserOrder{TokenID: "yes-token", Price: 0.60, Size: 10, Side: types.SELL}
+	if err := m.reconcileOrders(context.Background(), &types.QuotePair{Bid: bid, Ask: ask}); err != nil {
+		t.Fatalf("reconcileOrders: %v", err)
+	}
+
+	want := []types.JournalEventType{
+		types.JournalPlacement, types.JournalAck,
+		types.JournalPartialFill, types.JournalTrade,
+		types.JournalCancel, types.JournalPlacement, types.JournalAck,
+		types.JournalPlacement, types.JournalRejection,
+	}
+	got := journal.kinds()
+	if len(got) != len(want) {
+		t.Fatalf("journal = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("journal = %v, want %v", got, want)
+		}
+	}
+
+	cancel := journal.entries[4]
+	if cancel.OrderID != "o1" || cancel.Reason != "requote" || cancel.SizeMatched != 4 || cancel.Market != "cond-1" {
+		t.Errorf("cancel entry = %+v", cancel)
+	}
+	if rej := journal.entries[8]; rej.Reason != gw.reject || rej.Side != types.SELL {
+		t.Errorf("rejection entry = %+v", rej)
+	}
+}
+
+func TestFairPriceIsCoherentAcrossEventOutcomes(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+	})
+	m.SetEventBook(market.NewEventBook(types.EventInfo{ID: "ev", Outcomes: []types.EventOutcome{
+		{ConditionID: info.ConditionID, YesPrice: 0.50},
+		{ConditionID: "other", YesPrice: 0.75},
+	}}))
+
+	// Off by default: the raw mid
+	if mid, _ := m.fairPrice(); mid != 0.50 {
+		t.Errorf("fair price = %v, want raw mid 0.50", mid)
+	}
+
+	// The outcomes sum to 1.25, so our 0.50 is rescaled to 0.40
+	m.cfg.EventCoherence = true
+	if mid, _ := m.fairPrice(); math.Abs(mid-0.40) > 1e-9 {
+		t.Errorf("coherent fair price = %v, want 0.40", mid)
+	}
+}
+
+func TestFairPriceEstimators(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "300"}, {Price: "0.48", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}, {Price: "0.52", Size: "400"}},
+	})
+
+	tests := []struct {
+		estimator string
+		levels    int
+		want      float64
+	}{
+		{"", 0, 0.50},
+		{"mid", 0, 0.50},
+		{"microprice", 0, 0.505},
+		{"weighted_mid", 2, (0.49*500 + 0.51*400) / 900},
+	}
+	for _, tt := range tests {
+		m.cfg.FairValue, m.cfg.ImbalanceLevels = tt.estimator, tt.levels
+		if mid, ok := m.fairPrice(); !ok || math.Abs(mid-tt.want) > 1e-9 {
+			t.Errorf("%q fair price = %v, %v; want %v", tt.estimator, mid, ok, tt.want)
+		}
+	}
+}
+
+func TestVolatilityIgnoresFairValueOffset(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.FairValue = "microprice"
+	cfg.VolSampleInterval = time.Second
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	m.SetClock(func() time.Time { return now })
+
+	// Only the size at the touch changes: the microprice moves, the mid doesn't
+	for i, bidSize := range []string{"100", "900", "100"} {
+		m.book.ApplyBookResponse(&types.BookResponse{
+			AssetID: info.YesTokenID,
+			Bids:    []types.PriceLevel{{Price: "0.49", Size: bidSize}},
+			Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+		})
+		m.ObserveBook()
+		now = now.Add(10 * time.Second)
+		if i == 1 {
+			if fair, _ := m.fairPrice(); fair == 0.50 {
+				t.Fatal("microprice should differ from the mid on an imbalanced touch")
+			}
+		}
+	}
+	if est := m.vol.Estimate(); est.Samples != 2 || est.Realized != 0 {
+		t.Errorf("vol estimate = %+v, want two zero returns", est)
+	}
+}
+
+func TestImbalanceSkewWidensOneSide(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "900"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+	})
+
+	plain, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	m.cfg.ImbalanceSkew = 0.05
+	skewed, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+
+	// Imbalance is +0.8: the ask backs off 0.04, the bid stays put
+	if skewed.Bid.Price != plain.Bid.Price {
+		t.Errorf("bid moved from %v to %v", plain.Bid.Price, skewed.Bid.Price)
+	}
+	if math.Abs(skewed.Ask.Price-plain.Ask.Price-0.04) > 1e-9 {
+		t.Errorf("ask = %v, want %v + 0.04", skewed.Ask.Price, plain.Ask.Price)
+	}
+}
+
+func TestToxicFlowWidensOnlyTheHitSide(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+	})
+	plain, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+
+	// Selling NO fills our economic bid, the same as buying YES
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		token, side := info.YesTokenID, types.BUY
+		if i%2 == 1 {
+			token, side = info.NoTokenID, types.SELL
+		}
+		m.flowTracker.AddFill(m.economicFill(Fill{Timestamp: now, Side: side, TokenID: token, Price: 0.5, Size: 10}))
+	}
+
+	toxic, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	if toxic.Bid.Price >= plain.Bid.Price {
+		t.Errorf("bid %v should back off from %v", toxic.Bid.Price, plain.Bid.Price)
+	}
+	if toxic.Ask.Price != plain.Ask.Price {
+		t.Errorf("ask moved from %v to %v", plain.Ask.Price, toxic.Ask.Price)
+	}
+
+	// Past the pull multiplier the bid is pulled instead
+	m.cfg.FlowPullMultiplier = 1.5
+	pulled, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	if pulled.Bid != nil || pulled.Ask == nil {
+		t.Errorf("quotes = %+v / %+v, want the bid pulled and the ask kept", pulled.Bid, pulled.Ask)
+	}
+}
@@ -0,0 +1,272 @@
+package strategy
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/pkg/types"
+)
+
+// Markouts.
+//
+// A fill's markout at horizon h is how far the mid moved our way h after
+// the fill, in YES terms: mid − price for a bid fill, price − mid for an ask
+// fill. Consistently negative markouts are adverse selection: whoever fills
+// us knows where the price is going. The tracker samples the mid at each
+// horizon after every fill, keeps lifetime statistics per side and horizon
+// (persisted across restarts) and a recent window that can drive the
+// FlowTracker's toxicity score. Statistics are saved at most once per quote
+// cycle, from the Maker's goroutine; fills still waiting on a horizon when
+// the bot stops are not persisted.
+
+// defaultMarkoutHorizons are sampled when StrategyConfig.MarkoutHorizons is
+// unset.
+var defaultMarkoutHorizons = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute}
+
+// defaultMarkoutWindow is how long resolved markouts count towards
+// RecentMarkout when StrategyConfig.MarkoutWindow is unset.
+const defaultMarkoutWindow = 10 * time.Minute
+
+// MarkoutStat accumulates the markouts of one side at one horizon.
+type MarkoutStat struct {
+	Side    types.Side    `json:"side"` // BUY: our bids, SELL: our asks
+	Horizon time.Duration `json:"horizon"`
+	Count   int           `json:"count"`
+	Size    float64       `json:"size"`
+	Sum     float64       `json:"sum"` // size-weighted markouts
+}
+
+// Average is the size-weighted mean markout in price units; positive means
+// the mid moved our way.
+func (s MarkoutStat) Average() float64 {
+	if s.Size <= 0 {
+		return 0
+	}
+	return s.Sum / s.Size
+}
+
+// MarkoutState is the persisted part of a MarkoutTracker.
+type MarkoutState struct {
+	Stats []MarkoutStat `json:"stats"`
+}
+
+type markoutKey struct {
+	side    types.Side
+	horizon time.Duration
+}
+
+// pendingMarkout is a fill waiting on its later horizons.
+type pendingMarkout struct {
+	fill Fill // YES terms
+	next int  // index of the next horizon to sample
+}
+
+// recentMarkout is one resolved markout, kept for the recent window.
+type recentMarkout struct {
+	at    time.Time
+	side  types.Side
+	value float64
+	size  float64
+}
+
+// MarkoutTracker measures post-fill markouts for one market. Safe for
+// concurrent use: fills arrive on the Maker's goroutine, book samples on the
+// engine's.
+type MarkoutTracker struct {
+	mu       sync.Mutex
+	horizons []time.Duration // ascending
+	window   time.Duration
+	pending  []pendingMarkout
+	stats    map[markoutKey]*MarkoutStat
+	recent   []recentMarkout
+	dirty    bool // stats changed since the last Unsaved
+
+	now func() time.Time // clock (replaced by the backtester)
+}
+
+// NewMarkoutTracker creates a tracker sampling at the given horizons
+// (defaults if empty) and averaging recent markouts over window (default
+// 10m).
+func NewMarkoutTracker(horizons []time.Duration, window time.Duration) *MarkoutTracker {
+	if len(horizons) == 0 {
+		horizons = defaultMarkoutHorizons
+	}
+	horizons = slices.Sorted(slices.Values(horizons))
+	if window <= 0 {
+		window = defaultMarkoutWindow
+	}
+	t := &MarkoutTracker{
+		horizons: horizons,
+		window:   window,
+		stats:    make(map[markoutKey]*MarkoutStat),
+		now:      time.Now,
+	}
+	for _, side := range []types.Side{types.BUY, types.SELL} {
+		for _, h := range horizons {
+			t.stats[markoutKey{side, h}] = &MarkoutStat{Side: side, Horizon: h}
+		}
+	}
+	return t
+}
+
+// SetClock replaces the time source used to resolve horizons.
+func (t *MarkoutTracker) SetClock(now func() time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = now
+}
+
+// AddFill starts tracking a fill, expressed in YES terms.
+func (t *MarkoutTracker) AddFill(fill Fill) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, pendingMarkout{fill: fill})
+}
+
+// Observe samples mid for every pending fill whose next horizon has passed
+// and returns how many markouts it resolved.
+func (t *MarkoutTracker) Observe(mid float64) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	resolved := 0
+	kept := t.pending[:0]
+	for _, p := range t.pending {
+		for p.next < len(t.horizons) && !now.Before(p.fill.Timestamp.Add(t.horizons[p.next])) {
+			value := mid - p.fill.Price
+			if p.fill.Side == types.SELL {
+				value = -value
+			}
+			stat := t.stats[markoutKey{p.fill.Side, t.horizons[p.next]}]
+			stat.Count++
+			stat.Size += p.fill.Size
+			stat.Sum += value * p.fill.Size
+			t.recent = append(t.recent, recentMarkout{at: now, side: p.fill.Side, value: value, size: p.fill.Size})
+			p.next++
+			resolved++
+		}
+		if p.next < len(t.horizons) {
+			kept = append(kept, p)
+		}
+	}
+	t.pending = kept
+	t.evictLocked(now)
+	if resolved > 0 {
+		t.dirty = true
+	}
+	return resolved
+}
+
+// RecentMarkout is the size-weighted mean of one side's markouts resolved
+// within the window, across all horizons. Returns false if there are none.
+func (t *MarkoutTracker) RecentMarkout(side types.Side) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.evictLocked(t.now())
+	var sum, size float64
+	for _, r := range t.recent {
+		if r.side == side {
+			sum += r.value * r.size
+			size += r.size
+		}
+	}
+	if size <= 0 {
+		return 0, false
+	}
+	return sum / size, true
+}
+
+// evictLocked drops recent markouts older than the window.
+func (t *MarkoutTracker) evictLocked(now time.Time) {
+	cutoff := now.Add(-t.window)
+	i := 0
+	for i < len(t.recent) && t.recent[i].at.Before(cutoff) {
+		i++
+	}
+	t.recent = t.recent[i:]
+}
+
+// Stats returns the lifetime statistics, bids then asks, by horizon.
+func (t *MarkoutTracker) Stats() []MarkoutStat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]MarkoutStat, 0, len(t.stats))
+	for _, side := range []types.Side{types.BUY, types.SELL} {
+		for _, h := range t.horizons {
+			out = append(out, *t.stats[markoutKey{side, h}])
+		}
+	}
+	return out
+}
+
+// State returns the statistics to persist.
+func (t *MarkoutTracker) State() MarkoutState {
+	return MarkoutState{Stats: t.Stats()}
+}
+
+// Unsaved returns the statistics to persist if any markout has resolved
+// since the last call.
+func (t *MarkoutTracker) Unsaved() (MarkoutState, bool) {
+	t.mu.Lock()
+	dirty := t.dirty
+	t.dirty = false
+	t.mu.Unlock()
+	if !dirty {
+		return MarkoutState{}, false
+	}
+	return t.State(), true
+}
+
+// Restore loads persisted statistics. Stats for horizons no longer
+// configured are dropped.
+func (t *MarkoutTracker) Restore(state MarkoutState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, s := range state.Stats {
+		if stat, ok := t.stats[markoutKey{s.Side, s.Horizon}]; ok {
+			*stat = s
+		}
+	}
+}
+
+// defaultMarkoutToxicScale is the adverse markout scoring full toxicity
+// when StrategyConfig.MarkoutToxicScale is unset.
+const defaultMarkoutToxicScale = 0.02
+
+func markoutToxicScale(cfg config.StrategyConfig) float64 {
+	if cfg.MarkoutToxicScale > 0 {
+		return cfg.MarkoutToxicScale
+	}
+	return defaultMarkoutToxicScale
+}
+
+// observeMarkouts samples the book mid for fills whose markout horizons
+// have passed. The mid is the market's (marketMid), not our fair value. It
+// never touches disk, as the engine calls it from the market-data
+// dispatcher.
+func (m *Maker) observeMarkouts() {
+	if mid, ok := m.marketMid(); ok {
+		m.markouts.Observe(mid)
+	}
+}
+
+// saveMarkouts persists the markout statistics if any resolved since the
+// last save. Called from the Maker's own goroutine.
+func (m *Maker) saveMarkouts() {
+	if m.markoutStore == nil {
+		return
+	}
+	state, ok := m.markouts.Unsaved()
+	if !ok {
+		return
+	}
+	if err := m.markoutStore.SaveMarkouts(m.marketInfo.ConditionID, state); err != nil {
+		m.logger.Error("failed to save markouts", "error", err)
+	}
+}
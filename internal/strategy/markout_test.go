@@ -0,0 +1,127 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestMarkoutTrackerResolvesHorizons(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	mt := NewMarkoutTracker([]time.Duration{30 * time.Second, 5 * time.Second}, time.Minute)
+	mt.SetClock(func() time.Time { return now })
+
+	mt.AddFill(Fill{Timestamp: start, Side: types.BUY, Price: 0.50, Size: 10})
+	mt.AddFill(Fill{Timestamp: start, Side: types.SELL, Price: 0.50, Size: 30})
+
+	if n := mt.Observe(0.60); n != 0 {
+		t.Fatalf("resolved %d markouts before any horizon", n)
+	}
+	now = start.Add(5 * time.Second)
+	if n := mt.Observe(0.52); n != 2 {
+		t.Fatalf("resolved %d markouts at 5s, want 2", n)
+	}
+	now = start.Add(time.Minute)
+	if n := mt.Observe(0.47); n != 2 {
+		t.Fatalf("resolved %d markouts at 30s, want 2", n)
+	}
+
+	// Bids then asks, by horizon: the bid made 2c then lost 3c, the ask the reverse
+	want := []float64{0.02, -0.03, -0.02, 0.03}
+	stats := mt.Stats()
+	for i, s := range stats {
+		if s.Count != 1 || math.Abs(s.Average()-want[i]) > 1e-9 {
+			t.Errorf("%s %v: count %d avg %v, want 1 and %v", s.Side, s.Horizon, s.Count, s.Average(), want[i])
+		}
+	}
+
+	// Recent markouts are size-weighted across horizons and age out
+	if avg, ok := mt.RecentMarkout(types.BUY); !ok || math.Abs(avg-(-0.005)) > 1e-9 {
+		t.Errorf("recent bid markout = %v, %v; want -0.005", avg, ok)
+	}
+	now = start.Add(3 * time.Minute)
+	if _, ok := mt.RecentMarkout(types.BUY); ok {
+		t.Error("recent markouts should have aged out of the window")
+	}
+
+	// Lifetime stats survive a restart
+	restored := NewMarkoutTracker([]time.Duration{5 * time.Second, 30 * time.Second}, time.Minute)
+	restored.Restore(mt.State())
+	if got := restored.Stats(); got[3].Count != 1 || got[3].Sum != stats[3].Sum {
+		t.Errorf("restored stats = %+v", got)
+	}
+}
+
+func TestMarkoutToxicityWidensTheAdverseSide(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	mt := NewMarkoutTracker([]time.Duration{5 * time.Second}, time.Minute)
+	mt.SetClock(func() time.Time { return now })
+	ft := NewFlowTracker(60*time.Second, 0.6, 120*time.Second, 3.0)
+	ft.SetClock(func() time.Time { return now })
+	ft.SetMarkoutSource(mt, 0.02)
+
+	// Bids lose 2c (score 1.0), asks 0.5c (score 0.25)
+	mt.AddFill(Fill{Timestamp: start, Side: types.BUY, Price: 0.50, Size: 10})
+	now = start.Add(5 * time.Second)
+	mt.Observe(0.48)
+	mt.AddFill(Fill{Timestamp: now, Side: types.SELL, Price: 0.50, Size: 10})
+	now = now.Add(5 * time.Second)
+	mt.Observe(0.505)
+
+	metrics := ft.Assess()
+	if !metrics.IsAverse || metrics.Direction != types.BUY || metrics.ToxicityScore != 1.0 {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if metrics.BidMultiplier != 3.0 {
+		t.Errorf("bid multiplier = %v, want 3.0", metrics.BidMultiplier)
+	}
+	if math.Abs(metrics.AskMultiplier-1.5) > 1e-9 {
+		t.Errorf("ask multiplier = %v, want 1.5", metrics.AskMultiplier)
+	}
+}
+
+type countingMarkoutStore struct{ saves int }
+
+func (s *countingMarkoutStore) SaveMarkouts(string, MarkoutState) error {
+	s.saves++
+	return nil
+}
+
+func TestMarkoutsSaveOffTheBookPath(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.MarkoutHorizons = []time.Duration{5 * time.Second}
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	store := &countingMarkoutStore{}
+	m.SetMarkoutStore(store)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	m.SetClock(func() time.Time { return now })
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+	})
+	m.markouts.AddFill(Fill{Timestamp: start, Side: types.BUY, Price: 0.50, Size: 10})
+
+	// Book updates resolve the markout but leave saving to the quote cycle
+	now = start.Add(5 * time.Second)
+	m.ObserveBook()
+	m.ObserveBook()
+	if store.saves != 0 {
+		t.Fatalf("book updates saved %d times, want 0", store.saves)
+	}
+	m.saveMarkouts()
+	m.saveMarkouts()
+	if store.saves != 1 {
+		t.Errorf("saved %d times, want once for the resolved markout", store.saves)
+	}
+}
@@ -0,0 +1,144 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+// defaultGTDLifetimeCycles is how many refresh intervals a GTD quote lives
+// when StrategyConfig.GTDLifetimeCycles is unset.
+const defaultGTDLifetimeCycles = 3
+
+// gtdRenewCycles: a GTD quote with less than this many refresh intervals of
+// life left is replaced, so it never lapses between two cycles. Kept off a
+// whole number so ticker jitter can't flip the decision.
+const gtdRenewCycles = 1.5
+
+// stampQuote sets a resting quote's fee rate, order type, post-only flag
+// and, for GTD, its expiration, just before it is posted.
+func (m *Maker) stampQuote(order *types.UserOrder) {
+	order.FeeRateBps = m.marketInfo.FeeRateBps(order.TokenID)
+	order.PostOnly = m.cfg.PostOnly
+	order.OrderType = types.OrderTypeGTC
+	order.Expiration = 0
+	if m.cfg.QuoteOrderType != string(types.OrderTypeGTD) {
+		return
+	}
+	cycles := m.cfg.GTDLifetimeCycles
+	if cycles <= 0 {
+		cycles = defaultGTDLifetimeCycles
+	}
+	order.OrderType = types.OrderTypeGTD
+	order.Expiration = m.now().Add(types.GTDSecurityWindow + time.Duration(cycles)*m.cfg.RefreshInterval).Unix()
+}
+
+// expiresSoon reports whether a resting GTD order will lapse before it can
+// be renewed on a later cycle.
+func (m *Maker) expiresSoon(order types.OpenOrder) bool {
+	exp, _ := strconv.ParseInt(order.Expiration, 10, 64)
+	if exp <= 0 {
+		return false
+	}
+	liveUntil := time.Unix(exp, 0).Add(-types.GTDSecurityWindow)
+	return liveUntil.Sub(m.now()) < time.Duration(gtdRenewCycles*float64(m.cfg.RefreshInterval))
+}
+
+// logRejection logs a rejected order at a level matching its reason. A
+// post-only quote that would have crossed is routine when the book moves
+// between computing and posting quotes; the next cycle requotes.
+func (m *Maker) logRejection(order types.UserOrder, err error) {
+	attrs := []any{"error", err, "side", order.Side, "price", order.Price, "size", order.Size}
+	switch {
+	case errors.Is(err, types.ErrWouldCross):
+		m.logger.Debug("post-only quote would cross, requoting next cycle", attrs...)
+	case errors.Is(err, types.ErrInsufficientBalance), errors.Is(err, types.ErrOrderExpired):
+		m.logger.Warn("order rejected", attrs...)
+	default:
+		m.logger.Error("order rejected", attrs...)
+	}
+}
+
+// Flatten sells the market's YES and NO holdings into the best bids with
+// FAK (or FOK, per FlattenOrderType) orders. Nothing rests: unfilled size
+// is cancelled by the exchange, and fills arrive on the user channel like
+// any other. It returns an error if any order was rejected.
+func (m *Maker) Flatten(ctx context.Context) error {
+	orderType := types.OrderTypeFAK
+	if m.cfg.FlattenOrderType == string(types.OrderTypeFOK) {
+		orderType = types.OrderTypeFOK
+	}
+
+	pos := m.inventory.Snapshot()
+	var orders []types.UserOrder
+	for _, leg := range []struct {
+		tokenID string
+		qty     float64
+	}{
+		{m.marketInfo.YesTokenID, pos.YesQty},
+		{m.marketInfo.NoTokenID, pos.NoQty},
+	} {
+		size := math.Floor(leg.qty*100) / 100
+		if size < m.marketInfo.MinOrderSize {
+			continue
+		}
+		bid, _, _ := m.book.TokenBestBidAsk(leg.tokenID)
+		if bid <= 0 {
+			continue
+		}
+		orders = append(orders, types.UserOrder{
+			TokenID:    leg.tokenID,
+			Price:      bid,
+			Size:       size,
+			Side:       types.SELL,
+			OrderType:  orderType,
+			TickSize:   m.marketInfo.TickSize,
+			FeeRateBps: m.marketInfo.FeeRateBps(leg.tokenID),
+		})
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+
+	for _, order := range orders {
+		m.journalOrder(types.JournalPlacement, "", order, "", "flatten")
+	}
+	results, err := m.client.PostOrders(ctx, orders, m.marketInfo.NegRisk)
+	if err != nil {
+		for _, order := range orders {
+			m.journalOrder(types.JournalRejection, "", order, "", err.Error())
+		}
+		return fmt.Errorf("flatten: %w", err)
+	}
+
+	var errs []error
+	for i, result := range results {
+		if result.Success {
+			m.journalOrder(types.JournalAck, result.OrderID, orders[i], result.Status, "flatten")
+			continue
+		}
+		m.journalOrder(types.JournalRejection, "", orders[i], result.Status, result.ErrorMsg)
+		err := rejection(result)
+		m.logRejection(orders[i], err)
+		errs = append(errs, err)
+	}
+	m.logger.Info("flatten orders sent", "orders", len(orders), "rejected", len(errs), "type", orderType)
+	if len(errs) > 0 {
+		return fmt.Errorf("flatten: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// rejection returns a failed order's typed error. Gateways that don't
+// classify rejections get the generic reason.
+func rejection(result types.OrderResponse) error {
+	if result.Err != nil {
+		return result.Err
+	}
+	return &types.RejectionError{Reason: types.ErrOrderRejected, Msg: result.ErrorMsg}
+}
@@ -0,0 +1,161 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func TestGTDQuotesAreRenewedBeforeExpiry(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.QuoteOrderType = string(types.OrderTypeGTD)
+	cfg.PostOnly = true
+	m := setupMaker(cfg, testMarketInfo())
+	gw := &fakeGateway{}
+	m.client = gw
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+
+	bid := &types.UserOrder{TokenID: "yes-token", Price: 0.45, Size: 10, Side: types.BUY}
+	if err := m.reconcileOrders(context.Background(), &types.QuotePair{Bid: bid}); err != nil {
+		t.Fatalf("reconcileOrders: %v", err)
+	}
+	if len(gw.posted) != 1 {
+		t.Fatalf("posted %d orders, want 1", len(gw.posted))
+	}
+	first := gw.posted[0]
+	wantExp := now.Add(types.GTDSecurityWindow + defaultGTDLifetimeCycles*cfg.RefreshInterval).Unix()
+	if first.OrderType != types.OrderTypeGTD || !first.PostOnly || first.Expiration != wantExp {
+		t.Fatalf("quote = %+v, want post-only GTD expiring at %d", first, wantExp)
+	}
+
+	// One cycle later the quote still has enough life left
+	now = now.Add(cfg.RefreshInterval)
+	m.reconcileOrders(context.Background(), &types.QuotePair{Bid: bid})
+	if len(gw.posted) != 1 {
+		t.Fatalf("quote replaced too early, posted %d", len(gw.posted))
+	}
+
+	// Two cycles in, it would lapse before the next: replace it
+	now = now.Add(cfg.RefreshInterval)
+	m.reconcileOrders(context.Background(), &types.QuotePair{Bid: bid})
+	if len(gw.posted) != 2 || gw.posted[1].Expiration <= first.Expiration {
+		t.Fatalf("quote not renewed: %+v", gw.posted)
+	}
+	if len(m.activeOrders) != 1 {
+		t.Errorf("active orders = %d, want 1", len(m.activeOrders))
+	}
+}
+
+func TestFlattenSellsHoldingsWithoutResting(t *testing.T) {
+	t.Parallel()
+	m := setupMaker(testStrategyConfig(), testMarketInfo())
+	gw := &fakeGateway{}
+	m.client = gw
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "yes-token",
+		Buys:    []types.PriceLevel{{Price: "0.48", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.52", Size: "100"}},
+	})
+	m.inventory.SetPosition(Position{YesQty: 12.345, NoQty: 0.5, AvgEntryYes: 0.40})
+
+	if err := m.Flatten(context.Background()); err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	if len(gw.posted) != 1 {
+		t.Fatalf("posted = %+v, want only the YES leg", gw.posted)
+	}
+	o := gw.posted[0]
+	if o.Side != types.SELL || o.Price != 0.48 || o.Size != 12.34 || o.OrderType != types.OrderTypeFAK || o.PostOnly {
+		t.Errorf("flatten order = %+v", o)
+	}
+	if len(m.activeOrders) != 0 {
+		t.Error("flatten orders must not be tracked as resting quotes")
+	}
+
+	gw.reject = "order couldn't be fully filled, FOK orders are fully filled or killed"
+	if err := m.Flatten(context.Background()); err == nil {
+		t.Error("expected an error when the flatten order is rejected")
+	}
+}
+
+func TestFlattenSellsWhatPartialFillsLeave(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.FlattenOrderType = string(types.OrderTypeFOK)
+	info := testMarketInfo()
+	info.YesFeeRateBps, info.NoFeeRateBps = 100, 200
+	m := setupMaker(cfg, info)
+	gw := &fakeGateway{}
+	m.client = gw
+	journal := &memJournal{}
+	m.SetJournal(journal)
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "yes-token",
+		Buys:    []types.PriceLevel{{Price: "0.48", Size: "5"}},
+		Sells:   []types.PriceLevel{{Price: "0.52", Size: "100"}},
+	})
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "no-token",
+		Buys:    []types.PriceLevel{{Price: "0.47", Size: "100"}},
+		Sells:   []types.PriceLevel{{Price: "0.53", Size: "100"}},
+	})
+	m.inventory.SetPosition(Position{YesQty: 12, NoQty: 6, AvgEntryYes: 0.40, AvgEntryNo: 0.45})
+
+	// Both legs go out as FOK sells at their own best bid
+	if err := m.Flatten(context.Background()); err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	if len(gw.posted) != 2 {
+		t.Fatalf("posted = %+v, want both legs", gw.posted)
+	}
+	for _, want := range []struct {
+		token string
+		price float64
+		size  float64
+		fee   int
+	}{{"yes-token", 0.48, 12, 100}, {"no-token", 0.47, 6, 200}} {
+		var found bool
+		for _, o := range gw.posted {
+			if o.TokenID != want.token {
+				continue
+			}
+			found = true
+			if o.Side != types.SELL || o.OrderType != types.OrderTypeFOK || o.Price != want.price || o.Size != want.size ||
+				o.FeeRateBps != want.fee || o.TickSize != info.TickSize || o.Expiration != 0 || o.PostOnly {
+				t.Errorf("%s flatten order = %+v", want.token, o)
+			}
+		}
+		if !found {
+			t.Errorf("no flatten order for %s", want.token)
+		}
+	}
+	for _, e := range journal.entries {
+		if e.Reason != "flatten" {
+			t.Errorf("journal entry %+v not tagged as a flatten", e)
+		}
+	}
+
+	// Only part of the YES leg filled and the NO leg filled in full: the
+	// next flatten sells what is left, as a FAK once configured so
+	m.cfg.FlattenOrderType = ""
+	m.inventory.OnFill(Fill{TokenID: "yes-token", Side: types.SELL, Price: 0.48, Size: 5})
+	m.inventory.OnFill(Fill{TokenID: "no-token", Side: types.SELL, Price: 0.47, Size: 6})
+	gw.posted = nil
+	if err := m.Flatten(context.Background()); err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	if len(gw.posted) != 1 || gw.posted[0].TokenID != "yes-token" || gw.posted[0].Size != 7 || gw.posted[0].OrderType != types.OrderTypeFAK {
+		t.Errorf("posted = %+v, want a FAK for the 7 YES left", gw.posted)
+	}
+
+	// A remainder under the minimum order size can't be sold
+	m.inventory.OnFill(Fill{TokenID: "yes-token", Side: types.SELL, Price: 0.48, Size: 6.5})
+	gw.posted = nil
+	if err := m.Flatten(context.Background()); err != nil || len(gw.posted) != 0 {
+		t.Errorf("flatten of 0.5 YES posted %+v, %v; want nothing", gw.posted, err)
+	}
+}
@@ -0,0 +1,99 @@
+package strategy
+
+import (
+	"math"
+
+	"polymarket-mm/pkg/types"
+)
+
+// Resolution proximity management.
+//
+// Binary markets converge to 0 or 1 at EndDate, so the A-S horizon is the
+// time left until then (capped at cfg.T) and quoting tapers in stages as
+// resolution approaches:
+//
+//	widen:       spread × ResolutionSpreadMultiplier, size × ResolutionSizeFactor
+//	reduce-only: also quote only the side that shrinks net inventory
+//	stopped:     cancel everything and stop quoting
+//
+// A zero window disables its stage. Markets without an EndDate always use
+// cfg.T and never taper.
+
+// resolutionStage is how close a market is to resolution.
+type resolutionStage int
+
+const (
+	stageNormal resolutionStage = iota
+	stageWiden
+	stageReduceOnly
+	stageStopped
+)
+
+func (s resolutionStage) String() string {
+	switch s {
+	case stageWiden:
+		return "widen"
+	case stageReduceOnly:
+		return "reduce_only"
+	case stageStopped:
+		return "stopped"
+	default:
+		return "normal"
+	}
+}
+
+// horizon returns the A-S time horizon in years and the current stage.
+func (m *Maker) horizon() (float64, resolutionStage) {
+	if m.marketInfo.EndDate.IsZero() {
+		return m.cfg.T, stageNormal
+	}
+
+	remaining := m.marketInfo.EndDate.Sub(m.now())
+	T := math.Max(remaining.Seconds(), 0) / secondsPerYear
+	if m.cfg.T > 0 && T > m.cfg.T {
+		T = m.cfg.T
+	}
+
+	switch {
+	case m.cfg.ResolutionStopWindow > 0 && remaining <= m.cfg.ResolutionStopWindow:
+		return T, stageStopped
+	case m.cfg.ResolutionReduceOnlyWindow > 0 && remaining <= m.cfg.ResolutionReduceOnlyWindow:
+		return T, stageReduceOnly
+	case m.cfg.ResolutionWidenWindow > 0 && remaining <= m.cfg.ResolutionWidenWindow:
+		return T, stageWiden
+	}
+	return T, stageNormal
+}
+
+// taperMultipliers returns the spread and size multipliers for a stage.
+// Later stages keep the widening of earlier ones.
+func (m *Maker) taperMultipliers(stage resolutionStage) (spread, size float64) {
+	if stage < stageWiden {
+		return 1, 1
+	}
+	spread, size = 1, 1
+	if m.cfg.ResolutionSpreadMultiplier > 0 {
+		spread = m.cfg.ResolutionSpreadMultiplier
+	}
+	if m.cfg.ResolutionSizeFactor > 0 {
+		size = m.cfg.ResolutionSizeFactor
+	}
+	return spread, size
+}
+
+// reduceOnly keeps only the quote side that shrinks net YES exposure, sized
+// to at most that exposure. A flat position quotes nothing.
+func (m *Maker) reduceOnly(bid, ask *types.UserOrder) (*types.UserOrder, *types.UserOrder) {
+	pos := m.inventory.Snapshot()
+	net := pos.YesQty - pos.NoQty
+
+	switch {
+	case net >= m.marketInfo.MinOrderSize && ask != nil:
+		ask.Size = math.Min(ask.Size, net)
+		return nil, ask
+	case -net >= m.marketInfo.MinOrderSize && bid != nil:
+		bid.Size = math.Min(bid.Size, -net)
+		return bid, nil
+	}
+	return nil, nil
+}
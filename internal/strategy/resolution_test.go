@@ -0,0 +1,117 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func setupResolutionMaker(t *testing.T, untilEnd time.Duration) *Maker {
+	t.Helper()
+	cfg := testStrategyConfig()
+	cfg.T = 1.0 / 365
+	cfg.ResolutionWidenWindow = 60 * time.Minute
+	cfg.ResolutionSpreadMultiplier = 2.0
+	cfg.ResolutionSizeFactor = 0.5
+	cfg.ResolutionReduceOnlyWindow = 15 * time.Minute
+	cfg.ResolutionStopWindow = 5 * time.Minute
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	info := testMarketInfo()
+	info.EndDate = now.Add(untilEnd)
+
+	m := setupMaker(cfg, info)
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func TestHorizonStages(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		untilEnd time.Duration
+		want     resolutionStage
+	}{
+		{48 * time.Hour, stageNormal},
+		{30 * time.Minute, stageWiden},
+		{10 * time.Minute, stageReduceOnly},
+		{2 * time.Minute, stageStopped},
+		{-time.Minute, stageStopped},
+	}
+	for _, tt := range tests {
+		m := setupResolutionMaker(t, tt.untilEnd)
+		if _, got := m.horizon(); got != tt.want {
+			t.Errorf("%v before end: stage = %v, want %v", tt.untilEnd, got, tt.want)
+		}
+	}
+}
+
+func TestHorizonUsesTimeToEndDate(t *testing.T) {
+	t.Parallel()
+
+	far := setupResolutionMaker(t, 30*24*time.Hour)
+	if T, _ := far.horizon(); T != far.cfg.T {
+		t.Errorf("far from end: T = %v, want cap %v", T, far.cfg.T)
+	}
+
+	near := setupResolutionMaker(t, 6*time.Hour)
+	if T, _ := near.horizon(); math.Abs(T-0.25/365) > 1e-12 {
+		t.Errorf("6h from end: T = %v, want %v", T, 0.25/365)
+	}
+
+	noEnd := setupMaker(testStrategyConfig(), testMarketInfo())
+	if T, stage := noEnd.horizon(); T != noEnd.cfg.T || stage != stageNormal {
+		t.Errorf("no end date: T = %v stage = %v, want cfg.T and normal", T, stage)
+	}
+}
+
+func TestComputeQuotesWidensNearResolution(t *testing.T) {
+	t.Parallel()
+	normal := setupResolutionMaker(t, 48*time.Hour)
+	widen := setupResolutionMaker(t, 30*time.Minute)
+
+	nq, err := normal.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	wq, err := widen.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+
+	// The shorter horizon narrows the A-S spread a little; the taper dominates
+	if wq.Ask.Price-wq.Bid.Price <= nq.Ask.Price-nq.Bid.Price {
+		t.Errorf("spread near resolution %v should exceed normal %v",
+			wq.Ask.Price-wq.Bid.Price, nq.Ask.Price-nq.Bid.Price)
+	}
+	if math.Abs(wq.Bid.Size-nq.Bid.Size*0.5) > 1e-9 {
+		t.Errorf("bid size %v, want half of %v", wq.Bid.Size, nq.Bid.Size)
+	}
+}
+
+func TestComputeQuotesReduceOnly(t *testing.T) {
+	t.Parallel()
+
+	flat := setupResolutionMaker(t, 10*time.Minute)
+	q, err := flat.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	if q.Bid != nil || q.Ask != nil {
+		t.Errorf("flat position should not quote in reduce-only, got bid=%v ask=%v", q.Bid, q.Ask)
+	}
+
+	long := setupResolutionMaker(t, 10*time.Minute)
+	long.inventory.OnFill(Fill{Side: types.BUY, TokenID: "yes-token", Price: 0.50, Size: 3})
+	q, err = long.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	if q.Bid != nil {
+		t.Errorf("long position should not bid in reduce-only, got %+v", q.Bid)
+	}
+	if q.Ask == nil || q.Ask.Size != 3 {
+		t.Errorf("ask = %+v, want sell of the 3 held", q.Ask)
+	}
+}
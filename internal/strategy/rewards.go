@@ -0,0 +1,174 @@
+package strategy
+
+import (
+	"math"
+	"strconv"
+
+	"polymarket-mm/pkg/types"
+)
+
+// Polymarket scores each resting order inside the rewards max spread v at
+// distance s from the mid as ((v - s) / v)^2 × size, and pays the daily pool
+// pro rata to score. Between these mid prices a one-sided book still earns a
+// third of its score; outside them only two-sided liquidity counts.
+const (
+	rewardsTwoSidedLow      = 0.10
+	rewardsTwoSidedHigh     = 0.90
+	rewardsSingleSideFactor = 3.0
+)
+
+// RewardEstimate is the Maker's latest view of its liquidity-rewards
+// standing on the YES book.
+type RewardEstimate struct {
+	OurScore      float64 // score of the quotes we just sent
+	BookScore     float64 // score of everyone else visible on the book
+	Share         float64 // OurScore / (OurScore + BookScore)
+	ExpectedDaily float64 // Share × the market's daily pool, in USDC
+}
+
+// rewardScore is one order's reward score. maxSpread is in price units;
+// orders below minSize or not strictly inside maxSpread score zero.
+func rewardScore(maxSpread, minSize, mid, price, size float64) float64 {
+	dist := math.Abs(mid - price)
+	if maxSpread <= 0 || size < minSize || dist >= maxSpread {
+		return 0
+	}
+	r := (maxSpread - dist) / maxSpread
+	return r * r * size
+}
+
+// combinedRewardScore merges bid- and ask-side scores the way the rewards
+// program does, penalising one-sided liquidity.
+func combinedRewardScore(bidScore, askScore, mid float64) float64 {
+	if mid >= rewardsTwoSidedLow && mid <= rewardsTwoSidedHigh {
+		return math.Max(math.Min(bidScore, askScore), math.Max(bidScore, askScore)/rewardsSingleSideFactor)
+	}
+	return math.Min(bidScore, askScore)
+}
+
+// rewardsMaxSpread returns the market's rewards max spread in price units,
+// or 0 if it has no rewards program.
+func (m *Maker) rewardsMaxSpread() float64 {
+	return m.marketInfo.RewardsMaxSpread / 100
+}
+
+// applyRewards pulls quotes inside the rewards band and up to the rewards
+// min size. It only ever tightens price and grows size within the remaining
+// risk budget; sides the strategy dropped stay dropped. It is skipped while
+// flow is toxic, the trade tape is defending a side, or the market is
+// reduce-only.
+func (m *Maker) applyRewards(quotes *types.QuotePair, mid, remainingBudget float64) {
+	maxSpread := m.rewardsMaxSpread()
+	if !m.cfg.RewardsMode || maxSpread <= 0 {
+		return
+	}
+	if _, stage := m.horizon(); stage >= stageReduceOnly {
+		return
+	}
+	if m.flowTracker.GetSpreadMultiplier() > 1 {
+		return
+	}
+	if m.tape.Threatened(types.BUY) || m.tape.Threatened(types.SELL) {
+		return
+	}
+
+	tickDec := m.marketInfo.TickSize.Decimals()
+	tick := math.Pow(10, -float64(tickDec))
+
+	// Innermost prices that still score: strictly inside the band
+	minBid := roundUpToTick(mid-maxSpread-1e-9, tickDec)
+	if mid-minBid >= maxSpread-1e-9 {
+		minBid += tick
+	}
+	maxAsk := roundDownToTick(mid+maxSpread+1e-9, tickDec)
+	if maxAsk-mid >= maxSpread-1e-9 {
+		maxAsk -= tick
+	}
+
+	if bid := quotes.Bid; bid != nil && bid.Price < minBid && minBid < mid {
+		bid.Price = minBid
+	}
+	if ask := quotes.Ask; ask != nil && ask.Price > maxAsk && maxAsk > mid {
+		ask.Price = maxAsk
+	}
+	if quotes.Bid != nil && quotes.Ask != nil && quotes.Bid.Price >= quotes.Ask.Price {
+		quotes.Bid.Price = quotes.Ask.Price - tick
+	}
+
+	// Grow undersized quotes if the combined notional still fits the budget
+	notional := func() float64 {
+		var n float64
+		for _, q := range []*types.UserOrder{quotes.Bid, quotes.Ask} {
+			if q != nil {
+				n += q.Price * q.Size
+			}
+		}
+		return n
+	}
+	for _, q := range []*types.UserOrder{quotes.Bid, quotes.Ask} {
+		if q == nil || q.Size >= m.marketInfo.RewardsMinSize {
+			continue
+		}
+		extra := (m.marketInfo.RewardsMinSize - q.Size) * q.Price
+		if notional()+extra <= remainingBudget {
+			q.Size = m.marketInfo.RewardsMinSize
+		}
+	}
+}
+
+// estimateRewards scores our quotes against the visible YES book. Our own
+// resting orders are part of the book, so their score is taken out of the
+// competition before computing our share.
+func (m *Maker) estimateRewards(quotes *types.QuotePair, mid float64) RewardEstimate {
+	maxSpread := m.rewardsMaxSpread()
+	if maxSpread <= 0 {
+		return RewardEstimate{}
+	}
+	minSize := m.marketInfo.RewardsMinSize
+	yes := m.marketInfo.YesTokenID
+
+	levelScore := func(levels []types.PriceLevel) float64 {
+		var total float64
+		for _, lvl := range levels {
+			price, _ := strconv.ParseFloat(lvl.Price, 64)
+			size, _ := strconv.ParseFloat(lvl.Size, 64)
+			total += rewardScore(maxSpread, minSize, mid, price, size)
+		}
+		return total
+	}
+	bids, asks := m.book.Ladder(yes)
+	bookBid, bookAsk := levelScore(bids), levelScore(asks)
+
+	for _, o := range m.activeOrders {
+		if o.AssetID != yes {
+			continue
+		}
+		price, _ := strconv.ParseFloat(o.Price, 64)
+		orig, _ := strconv.ParseFloat(o.OriginalSize, 64)
+		matched, _ := strconv.ParseFloat(o.SizeMatched, 64)
+		score := rewardScore(maxSpread, minSize, mid, price, orig-matched)
+		if o.Side == string(types.BUY) {
+			bookBid = math.Max(bookBid-score, 0)
+		} else {
+			bookAsk = math.Max(bookAsk-score, 0)
+		}
+	}
+
+	var ourBid, ourAsk float64
+	if q := quotes.Bid; q != nil && q.TokenID == yes {
+		ourBid = rewardScore(maxSpread, minSize, mid, q.Price, q.Size)
+	}
+	if q := quotes.Ask; q != nil && q.TokenID == yes {
+		ourAsk = rewardScore(maxSpread, minSize, mid, q.Price, q.Size)
+	}
+
+	est := RewardEstimate{
+		OurScore:  combinedRewardScore(ourBid, ourAsk, mid),
+		BookScore: combinedRewardScore(bookBid, bookAsk, mid),
+	}
+	if total := est.OurScore + est.BookScore; total > 0 {
+		est.Share = est.OurScore / total
+	}
+	est.ExpectedDaily = est.Share * m.marketInfo.RewardsDailyRate
+	return est
+}
@@ -0,0 +1,98 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func rewardsMarketInfo() types.MarketInfo {
+	info := testMarketInfo()
+	info.RewardsMaxSpread = 3 // cents
+	info.RewardsMinSize = 50
+	info.RewardsDailyRate = 10
+	return info
+}
+
+func TestRewardScore(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		price, size, want float64
+	}{
+		{0.50, 100, 100}, // at mid: full score
+		{0.485, 100, 25}, // halfway out: ((0.03-0.015)/0.03)^2
+		{0.47, 100, 0},   // on the band edge
+		{0.49, 10, 0},    // below min size
+	}
+	for _, tt := range tests {
+		if got := rewardScore(0.03, 50, 0.50, tt.price, tt.size); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("rewardScore(%v, %v) = %v, want %v", tt.price, tt.size, got, tt.want)
+		}
+	}
+
+	// One-sided liquidity earns a third near the middle, nothing at extremes
+	if got := combinedRewardScore(90, 0, 0.50); got != 30 {
+		t.Errorf("one-sided mid-range score = %v, want 30", got)
+	}
+	if got := combinedRewardScore(90, 0, 0.95); got != 0 {
+		t.Errorf("one-sided extreme score = %v, want 0", got)
+	}
+}
+
+func TestApplyRewardsPullsQuotesIntoBand(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.RewardsMode = true
+	m := setupMaker(cfg, rewardsMarketInfo())
+
+	quotes := &types.QuotePair{
+		Bid: &types.UserOrder{TokenID: "yes-token", Price: 0.40, Size: 20, Side: types.BUY},
+		Ask: &types.UserOrder{TokenID: "yes-token", Price: 0.60, Size: 20, Side: types.SELL},
+	}
+	m.applyRewards(quotes, 0.50, 1000)
+	if quotes.Bid.Price != 0.48 || quotes.Ask.Price != 0.52 {
+		t.Errorf("prices = %v / %v, want 0.48 / 0.52 inside the 3c band", quotes.Bid.Price, quotes.Ask.Price)
+	}
+	if quotes.Bid.Size != 50 || quotes.Ask.Size != 50 {
+		t.Errorf("sizes = %v / %v, want the 50 rewards minimum", quotes.Bid.Size, quotes.Ask.Size)
+	}
+
+	// Without budget for min size, size is left alone
+	small := &types.QuotePair{Bid: &types.UserOrder{TokenID: "yes-token", Price: 0.40, Size: 20, Side: types.BUY}}
+	m.applyRewards(small, 0.50, 15)
+	if small.Bid.Size != 20 || small.Ask != nil {
+		t.Errorf("over-budget quotes = %+v", small)
+	}
+
+	// Toxic flow: quotes are not tightened
+	m.flowTracker.lastToxicTime = time.Now()
+	toxic := &types.QuotePair{Bid: &types.UserOrder{TokenID: "yes-token", Price: 0.40, Size: 20, Side: types.BUY}}
+	m.applyRewards(toxic, 0.50, 1000)
+	if toxic.Bid.Price != 0.40 {
+		t.Errorf("toxic bid moved to %v", toxic.Bid.Price)
+	}
+}
+
+func TestEstimateRewardsExcludesOurRestingOrders(t *testing.T) {
+	t.Parallel()
+	m := setupMaker(testStrategyConfig(), rewardsMarketInfo())
+	m.book.ApplyBookEvent(types.WSBookEvent{
+		AssetID: "yes-token",
+		Buys:    []types.PriceLevel{{Price: "0.49", Size: "150"}},
+		Sells:   []types.PriceLevel{{Price: "0.51", Size: "150"}},
+	})
+	// 50 of the 150 at each touch is ours
+	m.activeOrders["b"] = types.OpenOrder{ID: "b", AssetID: "yes-token", Side: "BUY", Price: "0.49", OriginalSize: "50", SizeMatched: "0"}
+	m.activeOrders["a"] = types.OpenOrder{ID: "a", AssetID: "yes-token", Side: "SELL", Price: "0.51", OriginalSize: "50", SizeMatched: "0"}
+
+	quotes := &types.QuotePair{
+		Bid: &types.UserOrder{TokenID: "yes-token", Price: 0.49, Size: 50, Side: types.BUY},
+		Ask: &types.UserOrder{TokenID: "yes-token", Price: 0.51, Size: 50, Side: types.SELL},
+	}
+	est := m.estimateRewards(quotes, 0.50)
+	if math.Abs(est.Share-1.0/3) > 1e-9 || math.Abs(est.ExpectedDaily-10.0/3) > 1e-9 {
+		t.Errorf("estimate = %+v, want a third of the pool", est)
+	}
+}
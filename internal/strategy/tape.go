@@ -0,0 +1,253 @@
+package strategy
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/pkg/types"
+)
+
+// Trade tape detectors (Phase 2).
+//
+// Our own fills only show flow once it has hit us. The public tape shows it
+// while it is still on its way: a burst of prints on one side (a cluster),
+// prints walking through several price levels in a moment (a sweep), or one
+// print big enough to clear a level on its own. Each is a taker moving the
+// price, and the side of our quotes in its path is defended for TapeHold:
+// widened by TapeSpreadMultiplier, or pulled with TapePull. Taker buys lift
+// asks, so they threaten our ask; taker sells threaten our bid.
+
+// Defaults for unset StrategyConfig tape fields.
+const (
+	defaultTapeClusterPrints    = 5
+	defaultTapeSweepLevels      = 3
+	defaultTapeHold             = 30 * time.Second
+	defaultTapeSpreadMultiplier = 2.0
+)
+
+// TapeAlert describes one detection.
+type TapeAlert struct {
+	Kind     string     // "cluster", "sweep" or "large_print"
+	Side     types.Side // side of our quotes threatened: BUY = bids
+	Prints   int        // prints by the takers in the detection window
+	Levels   int        // distinct prices they traded at
+	Notional float64    // USD traded by those prints
+}
+
+// tapePrint is one public trade in YES terms.
+type tapePrint struct {
+	at    time.Time
+	taker types.Side
+	price float64
+	size  float64
+}
+
+// TapeTracker runs the detectors over one market's public trades. Safe for
+// concurrent use: prints arrive on the engine's goroutine, quotes are
+// computed on the Maker's.
+type TapeTracker struct {
+	mu            sync.Mutex
+	clusterWindow time.Duration
+	clusterPrints int
+	sweepWindow   time.Duration
+	sweepLevels   int
+	largePrintUSD float64
+	hold          time.Duration
+
+	prints      []tapePrint
+	defendUntil map[types.Side]time.Time // our side -> end of its defense
+
+	now func() time.Time // clock (replaced by the backtester)
+}
+
+// NewTapeTracker creates the detectors configured in cfg.
+func NewTapeTracker(cfg config.StrategyConfig) *TapeTracker {
+	t := &TapeTracker{
+		clusterWindow: cfg.TapeClusterWindow,
+		clusterPrints: cfg.TapeClusterPrints,
+		sweepWindow:   cfg.TapeSweepWindow,
+		sweepLevels:   cfg.TapeSweepLevels,
+		largePrintUSD: cfg.TapeLargePrintUSD,
+		hold:          cfg.TapeHold,
+		defendUntil:   make(map[types.Side]time.Time),
+		now:           time.Now,
+	}
+	if t.clusterPrints <= 0 {
+		t.clusterPrints = defaultTapeClusterPrints
+	}
+	if t.sweepLevels <= 0 {
+		t.sweepLevels = defaultTapeSweepLevels
+	}
+	if t.hold <= 0 {
+		t.hold = defaultTapeHold
+	}
+	return t
+}
+
+// SetClock replaces the time source used for windows and holds.
+func (t *TapeTracker) SetClock(now func() time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.now = now
+}
+
+// Enabled reports whether any detector is configured.
+func (t *TapeTracker) Enabled() bool {
+	return t.clusterWindow > 0 || t.sweepWindow > 0 || t.largePrintUSD > 0
+}
+
+// AddPrint records a public trade, in YES terms with the taker's side, and
+// runs the detectors. Every detection extends the defense of the side it
+// threatens; alerts are returned only for sides not already defended.
+func (t *TapeTracker) AddPrint(taker types.Side, price, size float64) []TapeAlert {
+	if !t.Enabled() {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.prints = append(t.prints, tapePrint{at: now, taker: taker, price: price, size: size})
+	t.evictLocked(now)
+
+	var alerts []TapeAlert
+	if t.largePrintUSD > 0 && price*size >= t.largePrintUSD {
+		alerts = append(alerts, TapeAlert{
+			Kind:     "large_print",
+			Side:     threatenedSide(taker),
+			Prints:   1,
+			Levels:   1,
+			Notional: price * size,
+		})
+	}
+	if t.sweepWindow > 0 {
+		if a := t.alertLocked("sweep", taker, now, t.sweepWindow); a.Levels >= t.sweepLevels {
+			alerts = append(alerts, a)
+		}
+	}
+	if t.clusterWindow > 0 {
+		if a := t.alertLocked("cluster", taker, now, t.clusterWindow); a.Prints >= t.clusterPrints {
+			alerts = append(alerts, a)
+		}
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	side := threatenedSide(taker)
+	fresh := !now.Before(t.defendUntil[side])
+	t.defendUntil[side] = now.Add(t.hold)
+	if !fresh {
+		return nil
+	}
+	return alerts
+}
+
+// Threatened reports whether our quotes on side are being defended.
+func (t *TapeTracker) Threatened(side types.Side) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.now().Before(t.defendUntil[side])
+}
+
+// alertLocked summarises the taker's prints within window of now.
+func (t *TapeTracker) alertLocked(kind string, taker types.Side, now time.Time, window time.Duration) TapeAlert {
+	a := TapeAlert{Kind: kind, Side: threatenedSide(taker)}
+	levels := make(map[float64]bool)
+	cutoff := now.Add(-window)
+	for i := len(t.prints) - 1; i >= 0; i-- {
+		p := t.prints[i]
+		if p.at.Before(cutoff) {
+			break
+		}
+		if p.taker != taker {
+			continue
+		}
+		a.Prints++
+		a.Notional += p.price * p.size
+		levels[p.price] = true
+	}
+	a.Levels = len(levels)
+	return a
+}
+
+// evictLocked drops prints older than both detection windows.
+func (t *TapeTracker) evictLocked(now time.Time) {
+	cutoff := now.Add(-max(t.clusterWindow, t.sweepWindow))
+	i := 0
+	for i < len(t.prints) && t.prints[i].at.Before(cutoff) {
+		i++
+	}
+	t.prints = t.prints[i:]
+}
+
+// threatenedSide is the side of our quotes a taker trades against.
+func threatenedSide(taker types.Side) types.Side {
+	if taker == types.BUY {
+		return types.SELL
+	}
+	return types.BUY
+}
+
+// ObserveTrade feeds a public trade print into the tape detectors. The
+// engine and backtester call it for every last_trade_price event; it only
+// touches the mutex-guarded tracker, so it is safe to call outside Run.
+//
+// A fresh alert asks Run to requote at once rather than on its next tick,
+// since a sweep reaches our resting quotes well within a refresh interval.
+// It reports whether one fired, for the backtester to step the Maker.
+func (m *Maker) ObserveTrade(evt types.WSLastTradePriceEvent) bool {
+	price, _ := strconv.ParseFloat(evt.Price, 64)
+	size, _ := strconv.ParseFloat(evt.Size, 64)
+	if price <= 0 || size <= 0 {
+		return false
+	}
+	// A NO print converts to YES terms like one of our fills would
+	p := m.economicFill(Fill{TokenID: evt.AssetID, Side: types.Side(evt.Side), Price: price, Size: size})
+
+	alerts := m.tape.AddPrint(p.Side, p.Price, p.Size)
+	for _, alert := range alerts {
+		m.logger.Info("trade tape alert, defending quotes",
+			"kind", alert.Kind,
+			"side", alert.Side,
+			"prints", alert.Prints,
+			"levels", alert.Levels,
+			"notional", alert.Notional,
+		)
+	}
+	if len(alerts) == 0 {
+		return false
+	}
+
+	// Non-blocking: a requote already pending covers this alert too
+	select {
+	case m.requoteCh <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// TapeDefended reports which sides of our quotes the trade tape is
+// defending.
+func (m *Maker) TapeDefended() (bid, ask bool) {
+	return m.tape.Threatened(types.BUY), m.tape.Threatened(types.SELL)
+}
+
+// tapeMultipliers returns the tape's spread multiplier for each side: 1
+// unless the side is defended.
+func (m *Maker) tapeMultipliers() (bid, ask float64) {
+	mult := m.cfg.TapeSpreadMultiplier
+	if mult <= 0 {
+		mult = defaultTapeSpreadMultiplier
+	}
+	bid, ask = 1, 1
+	if m.tape.Threatened(types.BUY) {
+		bid = mult
+	}
+	if m.tape.Threatened(types.SELL) {
+		ask = mult
+	}
+	return bid, ask
+}
@@ -0,0 +1,164 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"polymarket-mm/internal/config"
+	"polymarket-mm/internal/risk"
+	"polymarket-mm/pkg/types"
+)
+
+func TestTapeTrackerDetectors(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	tt := NewTapeTracker(config.StrategyConfig{
+		TapeClusterWindow: 10 * time.Second,
+		TapeSweepWindow:   2 * time.Second,
+		TapeLargePrintUSD: 500,
+		TapeHold:          30 * time.Second,
+	})
+	tt.SetClock(func() time.Time { return now })
+
+	// Taker buys walking up three levels within the sweep window threaten
+	// our ask; the first two don't trigger anything
+	for i, price := range []float64{0.50, 0.51} {
+		if alerts := tt.AddPrint(types.BUY, price, 10); alerts != nil {
+			t.Fatalf("print %d raised %+v", i, alerts)
+		}
+		now = now.Add(500 * time.Millisecond)
+	}
+	alerts := tt.AddPrint(types.BUY, 0.52, 10)
+	if len(alerts) != 1 || alerts[0].Kind != "sweep" || alerts[0].Side != types.SELL || alerts[0].Levels != 3 {
+		t.Fatalf("alerts = %+v, want one sweep of our ask", alerts)
+	}
+	if !tt.Threatened(types.SELL) || tt.Threatened(types.BUY) {
+		t.Error("only the ask should be defended")
+	}
+
+	// Further detections extend the hold without repeating the alert
+	now = now.Add(20 * time.Second)
+	if alerts := tt.AddPrint(types.BUY, 0.60, 1000); alerts != nil {
+		t.Errorf("already defended side raised %+v", alerts)
+	}
+	now = now.Add(20 * time.Second)
+	if !tt.Threatened(types.SELL) {
+		t.Error("large print should have extended the ask's defense")
+	}
+	now = now.Add(11 * time.Second)
+	if tt.Threatened(types.SELL) {
+		t.Error("ask defense should have expired")
+	}
+
+	// Five small taker sells at one price are a cluster, not a sweep
+	for i := 0; i < 4; i++ {
+		if alerts := tt.AddPrint(types.SELL, 0.48, 5); alerts != nil {
+			t.Fatalf("sell %d raised %+v", i, alerts)
+		}
+		now = now.Add(time.Second)
+	}
+	alerts = tt.AddPrint(types.SELL, 0.48, 5)
+	if len(alerts) != 1 || alerts[0].Kind != "cluster" || alerts[0].Side != types.BUY || alerts[0].Prints != 5 {
+		t.Fatalf("alerts = %+v, want one cluster against our bid", alerts)
+	}
+}
+
+func TestTapeDefendsQuotesBeforeWeAreHit(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.TapeLargePrintUSD = 100
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+	})
+	plain, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+
+	// A large taker buy of NO is a sell in YES terms: it threatens our bid
+	m.ObserveTrade(types.WSLastTradePriceEvent{AssetID: info.NoTokenID, Side: "BUY", Price: "0.50", Size: "400"})
+	if bid, ask := m.TapeDefended(); !bid || ask {
+		t.Fatalf("defended bid=%v ask=%v, want the bid only", bid, ask)
+	}
+
+	defended, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	if defended.Bid.Price >= plain.Bid.Price {
+		t.Errorf("bid %v should back off from %v", defended.Bid.Price, plain.Bid.Price)
+	}
+	if defended.Ask.Price != plain.Ask.Price {
+		t.Errorf("ask moved from %v to %v", plain.Ask.Price, defended.Ask.Price)
+	}
+
+	m.cfg.TapePull = true
+	pulled, err := m.computeQuotes(0.50, 1000)
+	if err != nil {
+		t.Fatalf("computeQuotes: %v", err)
+	}
+	if pulled.Bid != nil || pulled.Ask == nil {
+		t.Errorf("quotes = %+v / %+v, want the bid pulled and the ask kept", pulled.Bid, pulled.Ask)
+	}
+}
+
+// postNotifier reports each batch its gateway posts, so tests can watch
+// a running Maker without racing on the gateway's fields.
+type postNotifier struct {
+	fakeGateway
+	posts chan []types.UserOrder
+}
+
+func (g *postNotifier) PostOrders(ctx context.Context, orders []types.UserOrder, postOnly bool) ([]types.OrderResponse, error) {
+	out, err := g.fakeGateway.PostOrders(ctx, orders, postOnly)
+	g.posts <- orders
+	return out, err
+}
+
+func TestTapeAlertRequotesWithoutWaitingForATick(t *testing.T) {
+	t.Parallel()
+	cfg := testStrategyConfig()
+	cfg.RefreshInterval = time.Hour
+	cfg.TapeLargePrintUSD = 100
+	cfg.TapePull = true
+	info := testMarketInfo()
+	m := setupMaker(cfg, info)
+	gw := &postNotifier{posts: make(chan []types.UserOrder, 4)}
+	m.client = gw
+	m.riskMgr = risk.NewManager(config.RiskConfig{MaxPositionPerMarket: 1000, MaxGlobalExposure: 1000}, m.logger)
+	m.book.ApplyBookResponse(&types.BookResponse{
+		AssetID: info.YesTokenID,
+		Bids:    []types.PriceLevel{{Price: "0.49", Size: "100"}},
+		Asks:    []types.PriceLevel{{Price: "0.51", Size: "100"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx, nil, nil)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// A large taker sell threatens our bid: the requote posts only the ask
+	if !m.ObserveTrade(types.WSLastTradePriceEvent{AssetID: info.YesTokenID, Side: "SELL", Price: "0.49", Size: "400"}) {
+		t.Fatal("large print raised no alert")
+	}
+	select {
+	case orders := <-gw.posts:
+		if len(orders) != 1 || orders[0].Side != types.SELL {
+			t.Errorf("posted %+v, want the ask alone", orders)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("alert did not requote before the next tick")
+	}
+}
@@ -0,0 +1,202 @@
+package strategy
+
+import (
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+// Trade settlement statuses reported by the CLOB. A trade is MATCHED
+// off-chain, then MINED and CONFIRMED on-chain; a failed settlement goes
+// through RETRYING and may end FAILED, in which case it never happened.
+const (
+	TradeMatched   = "MATCHED"
+	TradeMined     = "MINED"
+	TradeConfirmed = "CONFIRMED"
+	TradeRetrying  = "RETRYING"
+	TradeFailed    = "FAILED"
+)
+
+// tradeRetention is how long settled trades stay in the ledger. Redelivery
+// happens within minutes; a week leaves room for long outages.
+const tradeRetention = 7 * 24 * time.Hour
+
+// maxTradeAge drops trades that never reached a final status, e.g. because
+// their last WS update was missed. Settlement takes minutes, so a trade
+// still MATCHED after a month is not going to fail; keeping it would only
+// grow the ledger re-saved on every fill.
+const maxTradeAge = 30 * 24 * time.Hour
+
+// TradeFills is our side of one exchange trade: usually a single fill,
+// several when one taker order matched more than one of our orders.
+type TradeFills struct {
+	TradeID string
+	Status  string
+	Fills   []Fill
+}
+
+// BookedTrade is a trade already applied to the position. It is kept so a
+// re-sent trade message isn't booked twice and a failed settlement can be
+// undone exactly.
+type BookedTrade struct {
+	Status   string       `json:"status"`
+	BookedAt time.Time    `json:"booked_at"`
+	Fills    []BookedFill `json:"fills,omitempty"`
+}
+
+// BookedFill is one applied fill with what it changed: the average entry of
+// its token before the fill and the PnL it realized.
+type BookedFill struct {
+	Fill
+	EntryBefore float64 `json:"entry_before"`
+	Realized    float64 `json:"realized"`
+}
+
+// TradeAction says what ApplyTrade did with a trade message.
+type TradeAction int
+
+const (
+	TradeBooked    TradeAction = iota // first sighting; fills applied
+	TradeUpdated                      // already booked; status advanced
+	TradeDuplicate                    // already booked with this status
+	TradeReversed                     // settlement failed; fills undone
+	TradeSkipped                      // failed, never booked, or already reversed
+)
+
+// InventoryState is everything Inventory persists: the position and the
+// ledger of trades booked into it.
+type InventoryState struct {
+	Position
+	Trades map[string]BookedTrade `json:"trades,omitempty"`
+}
+
+// ApplyTrade books a trade exactly once, whatever order and how many times
+// its status messages arrive. Trades without an ID can't be deduplicated
+// and are always booked.
+func (inv *Inventory) ApplyTrade(t TradeFills) TradeAction {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if t.TradeID == "" {
+		for _, f := range t.Fills {
+			inv.onFillLocked(f)
+		}
+		return TradeBooked
+	}
+
+	now := inv.now()
+	booked, seen := inv.trades[t.TradeID]
+	failed := t.Status == TradeFailed
+
+	switch {
+	case !seen && failed:
+		inv.trades[t.TradeID] = BookedTrade{Status: TradeFailed, BookedAt: now}
+		return TradeSkipped
+
+	case !seen:
+		booked = BookedTrade{Status: t.Status, BookedAt: now}
+		for _, f := range t.Fills {
+			booked.Fills = append(booked.Fills, inv.bookFillLocked(f))
+		}
+		inv.trades[t.TradeID] = booked
+		inv.pruneTradesLocked(now)
+		return TradeBooked
+
+	case booked.Status == TradeFailed:
+		return TradeSkipped
+
+	case failed:
+		for i := len(booked.Fills) - 1; i >= 0; i-- {
+			inv.reverseFillLocked(booked.Fills[i])
+		}
+		booked.Status = TradeFailed
+		inv.trades[t.TradeID] = booked
+		return TradeReversed
+
+	case t.Status == "" || t.Status == booked.Status:
+		return TradeDuplicate
+
+	default:
+		booked.Status = t.Status
+		inv.trades[t.TradeID] = booked
+		return TradeUpdated
+	}
+}
+
+// State returns a copy of the position and trade ledger for persistence.
+func (inv *Inventory) State() InventoryState {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	trades := make(map[string]BookedTrade, len(inv.trades))
+	for id, t := range inv.trades {
+		trades[id] = t
+	}
+	return InventoryState{Position: inv.pos, Trades: trades}
+}
+
+// Restore replaces the position and trade ledger (used on restart).
+func (inv *Inventory) Restore(state InventoryState) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	inv.pos = state.Position
+	inv.trades = make(map[string]BookedTrade, len(state.Trades))
+	for id, t := range state.Trades {
+		inv.trades[id] = t
+	}
+}
+
+// bookFillLocked applies a fill and records what it changed. Must be called
+// with lock held.
+func (inv *Inventory) bookFillLocked(f Fill) BookedFill {
+	entry := inv.pos.AvgEntryNo
+	if f.TokenID == inv.yesToken {
+		entry = inv.pos.AvgEntryYes
+	}
+	realizedBefore := inv.pos.RealizedPnL
+	inv.onFillLocked(f)
+	return BookedFill{Fill: f, EntryBefore: entry, Realized: inv.pos.RealizedPnL - realizedBefore}
+}
+
+// reverseFillLocked undoes a booked fill. A buy is taken back out of the
+// position at its own price; a sell puts the tokens back at the entry they
+// were sold from. Either way the PnL it realized, fees included, is given
+// back. Must be called with lock held.
+func (inv *Inventory) reverseFillLocked(bf BookedFill) {
+	qty, avg := &inv.pos.NoQty, &inv.pos.AvgEntryNo
+	if bf.TokenID == inv.yesToken {
+		qty, avg = &inv.pos.YesQty, &inv.pos.AvgEntryYes
+	}
+
+	if bf.Side == types.BUY {
+		remaining := *qty - bf.Size
+		if remaining <= 0 {
+			*qty, *avg = 0, 0
+		} else {
+			*avg = max((*avg**qty-bf.Price*bf.Size)/remaining, 0)
+			*qty = remaining
+		}
+	} else {
+		*avg = (*avg**qty + bf.EntryBefore*bf.Size) / (*qty + bf.Size)
+		*qty += bf.Size
+	}
+	inv.pos.RealizedPnL -= bf.Realized
+	inv.pos.FeesPaid -= bf.Fee
+	inv.pos.Volume -= bf.Price * bf.Size
+	inv.pos.LastUpdated = inv.now()
+}
+
+// pruneTradesLocked drops settled trades older than tradeRetention, and any
+// trade older than maxTradeAge. Trades still settling are kept until then
+// so a late FAILED can reverse them. Must be called with lock held.
+func (inv *Inventory) pruneTradesLocked(now time.Time) {
+	settledCutoff := now.Add(-tradeRetention)
+	cutoff := now.Add(-maxTradeAge)
+	for id, t := range inv.trades {
+		settled := t.Status == TradeConfirmed || t.Status == TradeFailed
+		if t.BookedAt.Before(cutoff) || settled && t.BookedAt.Before(settledCutoff) {
+			delete(inv.trades, id)
+		}
+	}
+}
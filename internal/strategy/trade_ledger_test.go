@@ -0,0 +1,132 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"polymarket-mm/pkg/types"
+)
+
+func trade(id, status string, side types.Side, price, size float64) TradeFills {
+	return TradeFills{TradeID: id, Status: status, Fills: []Fill{
+		{TradeID: id, TokenID: yesToken, Side: side, Price: price, Size: size},
+	}}
+}
+
+func TestApplyTradeBooksOnceAcrossStatuses(t *testing.T) {
+	t.Parallel()
+	inv := newTestInventory()
+
+	steps := []struct {
+		status string
+		want   TradeAction
+	}{
+		{TradeMatched, TradeBooked},
+		{TradeMatched, TradeDuplicate},
+		{TradeMined, TradeUpdated},
+		{"", TradeDuplicate},
+		{TradeConfirmed, TradeUpdated},
+	}
+	for _, s := range steps {
+		if got := inv.ApplyTrade(trade("t1", s.status, types.BUY, 0.50, 10)); got != s.want {
+			t.Errorf("status %q: action = %v, want %v", s.status, got, s.want)
+		}
+	}
+	if pos := inv.Snapshot(); pos.YesQty != 10 {
+		t.Errorf("YesQty = %v, want 10", pos.YesQty)
+	}
+
+	// Trades without an ID can't be deduplicated
+	inv.ApplyTrade(trade("", "", types.BUY, 0.50, 1))
+	inv.ApplyTrade(trade("", "", types.BUY, 0.50, 1))
+	if pos := inv.Snapshot(); pos.YesQty != 12 {
+		t.Errorf("YesQty = %v, want 12", pos.YesQty)
+	}
+}
+
+func TestApplyTradeReversesFailedSettlement(t *testing.T) {
+	t.Parallel()
+	inv := newTestInventory()
+
+	inv.ApplyTrade(trade("buy1", TradeConfirmed, types.BUY, 0.40, 10))
+	inv.ApplyTrade(trade("buy2", TradeMatched, types.BUY, 0.60, 10))
+	inv.ApplyTrade(trade("sell", TradeMatched, types.SELL, 0.70, 15))
+	before := inv.Snapshot()
+
+	// The sell fails: tokens come back at their entry, PnL is given back
+	if got := inv.ApplyTrade(trade("sell", TradeFailed, types.SELL, 0.70, 15)); got != TradeReversed {
+		t.Fatalf("action = %v, want TradeReversed", got)
+	}
+	pos := inv.Snapshot()
+	if pos.YesQty != 20 || math.Abs(pos.AvgEntryYes-0.50) > 1e-9 || math.Abs(pos.RealizedPnL) > 1e-9 {
+		t.Errorf("after sell reversal = %+v (before %+v), want 20 @ 0.50, no PnL", pos, before)
+	}
+
+	// Then the second buy fails: back to the first buy alone
+	inv.ApplyTrade(trade("buy2", TradeRetrying, types.BUY, 0.60, 10))
+	if got := inv.ApplyTrade(trade("buy2", TradeFailed, types.BUY, 0.60, 10)); got != TradeReversed {
+		t.Fatalf("action = %v, want TradeReversed", got)
+	}
+	pos = inv.Snapshot()
+	if pos.YesQty != 10 || math.Abs(pos.AvgEntryYes-0.40) > 1e-9 {
+		t.Errorf("after buy reversal = %+v, want 10 @ 0.40", pos)
+	}
+
+	// FAILED is terminal, and a trade first seen failed is never booked
+	if got := inv.ApplyTrade(trade("buy2", TradeFailed, types.BUY, 0.60, 10)); got != TradeSkipped {
+		t.Errorf("repeated failure: action = %v, want TradeSkipped", got)
+	}
+	if got := inv.ApplyTrade(trade("ghost", TradeFailed, types.BUY, 0.60, 10)); got != TradeSkipped {
+		t.Errorf("unseen failure: action = %v, want TradeSkipped", got)
+	}
+	if got := inv.ApplyTrade(trade("ghost", TradeMatched, types.BUY, 0.60, 10)); got != TradeSkipped {
+		t.Errorf("late match after failure: action = %v, want TradeSkipped", got)
+	}
+	if pos := inv.Snapshot(); pos.YesQty != 10 {
+		t.Errorf("YesQty = %v, want 10", pos.YesQty)
+	}
+}
+
+func TestPruneTradesDropsStaleUnsettledTrades(t *testing.T) {
+	t.Parallel()
+	inv := newTestInventory()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inv.SetClock(func() time.Time { return now })
+	inv.trades["settled"] = BookedTrade{Status: TradeConfirmed, BookedAt: now.Add(-8 * 24 * time.Hour)}
+	inv.trades["settling"] = BookedTrade{Status: TradeMatched, BookedAt: now.Add(-8 * 24 * time.Hour)}
+	inv.trades["stuck"] = BookedTrade{Status: TradeMatched, BookedAt: now.Add(-31 * 24 * time.Hour)}
+	inv.trades["mined"] = BookedTrade{Status: TradeMined, BookedAt: now.Add(-31 * 24 * time.Hour)}
+
+	// Booking a new trade prunes the ledger
+	inv.ApplyTrade(trade("t1", TradeMatched, types.BUY, 0.50, 10))
+
+	for id, want := range map[string]bool{"settled": false, "settling": true, "stuck": false, "mined": false, "t1": true} {
+		if _, ok := inv.trades[id]; ok != want {
+			t.Errorf("trade %s kept = %v, want %v", id, ok, want)
+		}
+	}
+}
+
+func TestInventoryStateRoundTrip(t *testing.T) {
+	t.Parallel()
+	inv := newTestInventory()
+	inv.ApplyTrade(trade("t1", TradeMatched, types.BUY, 0.50, 10))
+
+	restored := newTestInventory()
+	restored.Restore(inv.State())
+
+	// The restored ledger still dedups and can still reverse
+	if got := restored.ApplyTrade(trade("t1", TradeMatched, types.BUY, 0.50, 10)); got != TradeDuplicate {
+		t.Errorf("action = %v, want TradeDuplicate", got)
+	}
+	if got := restored.ApplyTrade(trade("t1", TradeFailed, types.BUY, 0.50, 10)); got != TradeReversed {
+		t.Errorf("action = %v, want TradeReversed", got)
+	}
+	if pos := restored.Snapshot(); pos.YesQty != 0 {
+		t.Errorf("YesQty = %v, want 0", pos.YesQty)
+	}
+	if pos := inv.Snapshot(); pos.YesQty != 10 {
+		t.Errorf("original inventory changed: YesQty = %v", pos.YesQty)
+	}
+}
@@ -0,0 +1,125 @@
+package strategy
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// secondsPerYear annualizes variance so the estimate is on the same scale as
+// cfg.Sigma and cfg.T (years).
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// VolEstimate is a point-in-time view of a VolEstimator.
+type VolEstimate struct {
+	Sigma    float64 // value the Maker quotes with (bounded, prior during warm-up)
+	Realized float64 // unbounded EWMA estimate (0 until the first return)
+	Samples  int     // returns observed so far
+	Warm     bool    // true once Samples reaches the warm-up count
+}
+
+// VolEstimator tracks realized volatility of the mid price as an EWMA of
+// squared returns, sampled from book updates.
+//
+// Mids are sampled at most once per sampleInterval so bid/ask flicker
+// between book updates doesn't inflate the estimate. Each return is
+// normalized by its elapsed time and annualized, then blended in with a
+// time-based decay: a sample dt after the previous one gets weight
+// 1 - 2^(-dt/halfLife). Until warmup returns have been seen, Sigma reports
+// the configured prior instead; the result is always clamped to [floor, cap].
+type VolEstimator struct {
+	mu sync.Mutex
+
+	// Config
+	prior          float64
+	halfLife       time.Duration
+	sampleInterval time.Duration
+	warmup         int
+	floor          float64 // 0 = no floor
+	cap            float64 // 0 = no cap
+
+	// State
+	lastMid  float64
+	lastTime time.Time
+	variance float64 // annualized variance of mid returns
+	samples  int
+}
+
+// NewVolEstimator creates an estimator that reports prior until warmed up.
+func NewVolEstimator(prior float64, halfLife, sampleInterval time.Duration, warmup int, floor, cap float64) *VolEstimator {
+	return &VolEstimator{
+		prior:          prior,
+		halfLife:       halfLife,
+		sampleInterval: sampleInterval,
+		warmup:         warmup,
+		floor:          floor,
+		cap:            cap,
+	}
+}
+
+// Observe records the mid price at time t. Samples closer than the sample
+// interval to the previous one, out-of-order samples, and prices outside
+// (0, 1) are ignored.
+func (v *VolEstimator) Observe(t time.Time, mid float64) {
+	if mid <= 0 || mid >= 1 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.lastTime.IsZero() {
+		v.lastMid, v.lastTime = mid, t
+		return
+	}
+	dt := t.Sub(v.lastTime)
+	if dt <= 0 || dt < v.sampleInterval {
+		return
+	}
+
+	ret := mid - v.lastMid
+	sample := ret * ret / (dt.Seconds() / secondsPerYear)
+
+	if v.samples == 0 {
+		v.variance = sample
+	} else {
+		alpha := 1.0 / float64(v.samples+1) // plain average without a half-life
+		if v.halfLife > 0 {
+			alpha = 1 - math.Exp2(-dt.Seconds()/v.halfLife.Seconds())
+		}
+		v.variance = alpha*sample + (1-alpha)*v.variance
+	}
+
+	v.samples++
+	v.lastMid, v.lastTime = mid, t
+}
+
+// Sigma returns the volatility the Maker should quote with.
+func (v *VolEstimator) Sigma() float64 {
+	return v.Estimate().Sigma
+}
+
+// Estimate returns the current estimate and warm-up state.
+func (v *VolEstimator) Estimate() VolEstimate {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	est := VolEstimate{
+		Realized: math.Sqrt(v.variance),
+		Samples:  v.samples,
+		Warm:     v.samples >= v.warmup && v.samples > 0,
+	}
+
+	sigma := v.prior
+	if est.Warm {
+		sigma = est.Realized
+	}
+	if v.floor > 0 && sigma < v.floor {
+		sigma = v.floor
+	}
+	if v.cap > 0 && sigma > v.cap {
+		sigma = v.cap
+	}
+	est.Sigma = sigma
+	return est
+}
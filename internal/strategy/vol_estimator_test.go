@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestVolEstimatorWarmUpUsesPrior(t *testing.T) {
+	t.Parallel()
+	v := NewVolEstimator(0.5, 10*time.Minute, 5*time.Second, 3, 0, 0)
+	start := time.Unix(1_700_000_000, 0)
+
+	v.Observe(start, 0.50)
+	v.Observe(start.Add(10*time.Second), 0.51)
+	v.Observe(start.Add(20*time.Second), 0.50)
+
+	est := v.Estimate()
+	if est.Warm || est.Samples != 2 {
+		t.Fatalf("estimate = %+v, want 2 samples and not warm", est)
+	}
+	if est.Sigma != 0.5 {
+		t.Errorf("Sigma = %v during warm-up, want prior 0.5", est.Sigma)
+	}
+
+	v.Observe(start.Add(30*time.Second), 0.51)
+	if est := v.Estimate(); !est.Warm || est.Sigma == 0.5 {
+		t.Errorf("estimate = %+v, want warm realized sigma", est)
+	}
+}
+
+func TestVolEstimatorAnnualizesConstantMoves(t *testing.T) {
+	t.Parallel()
+	v := NewVolEstimator(0.5, 10*time.Minute, 0, 1, 0, 0)
+	start := time.Unix(1_700_000_000, 0)
+
+	// A 1c move every 60s is a variance rate of 0.0001/60 per second
+	mid := 0.50
+	for i := 0; i <= 20; i++ {
+		v.Observe(start.Add(time.Duration(i)*time.Minute), mid)
+		if i%2 == 0 {
+			mid += 0.01
+		} else {
+			mid -= 0.01
+		}
+	}
+
+	want := math.Sqrt(0.0001 / 60 * secondsPerYear)
+	if got := v.Sigma(); math.Abs(got-want)/want > 1e-6 {
+		t.Errorf("Sigma = %v, want %v", got, want)
+	}
+}
+
+func TestVolEstimatorSamplingAndBounds(t *testing.T) {
+	t.Parallel()
+	v := NewVolEstimator(0.5, time.Minute, 5*time.Second, 1, 1.0, 2.0)
+	start := time.Unix(1_700_000_000, 0)
+
+	v.Observe(start, 0.50)
+	v.Observe(start.Add(time.Second), 0.90)  // inside the sample interval: ignored
+	v.Observe(start.Add(2*time.Second), 1.0) // invalid price: ignored
+	if est := v.Estimate(); est.Samples != 0 || est.Sigma != 1.0 {
+		t.Errorf("estimate = %+v, want no samples and prior raised to floor 1.0", est)
+	}
+
+	// A 20c jump in 10s is far above the cap
+	v.Observe(start.Add(10*time.Second), 0.70)
+	if est := v.Estimate(); est.Sigma != 2.0 || est.Realized <= 2.0 {
+		t.Errorf("estimate = %+v, want sigma capped at 2.0", est)
+	}
+}
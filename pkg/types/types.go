@@ -6,6 +6,7 @@
 package types
 
 import (
+	"errors"
 	"math/big"
 	"time"
 )
@@ -27,8 +28,24 @@ type OrderType string
 
 const (
 	OrderTypeGTC OrderType = "GTC" // Good-Til-Cancelled: stays on book until filled or cancelled
+	OrderTypeGTD OrderType = "GTD" // Good-Til-Date: like GTC, but expires at UserOrder.Expiration
+	OrderTypeFOK OrderType = "FOK" // Fill-Or-Kill: fills entirely on arrival or is rejected
+	OrderTypeFAK OrderType = "FAK" // Fill-And-Kill: fills what it can on arrival, rest is cancelled
 )
 
+// Rests reports whether unfilled size stays on the book.
+func (t OrderType) Rests() bool {
+	return t == "" || t == OrderTypeGTC || t == OrderTypeGTD
+}
+
+// GTDSecurityWindow is the CLOB's minimum lead time on GTD expirations: an
+// order with expiration E is live until E minus this window, so the
+// expiration must be at least this far in the future.
+const GTDSecurityWindow = time.Minute
+
+// MaxOrdersPerBatch is the most orders POST /orders accepts in one request.
+const MaxOrdersPerBatch = 15
+
 // SignatureType identifies the signing scheme for the CTF exchange contract.
 type SignatureType int
 
@@ -103,6 +120,9 @@ type MarketInfo struct {
 	MinOrderSize float64  // minimum order size in tokens
 	NegRisk      bool     // true if this is a neg-risk market (affects CTF exchange)
 
+	EventID   string // Gamma event ID, set for outcomes of a multi-outcome neg-risk event
+	EventSlug string // the event's slug
+
 	Active          bool      // market is live
 	Closed          bool      // market has been resolved
 	AcceptingOrders bool      // CLOB is accepting new orders
@@ -116,16 +136,48 @@ type MarketInfo struct {
 	LastTradePrice float64 // most recent trade price
 
 	RewardsMinSize   float64 // minimum size to qualify for liquidity rewards
-	RewardsMaxSpread float64 // maximum spread to qualify for liquidity rewards
+	RewardsMaxSpread float64 // max distance from mid to qualify for rewards, in cents
+	RewardsDailyRate float64 // USDC paid per day to the market's liquidity providers
+
+	YesFeeRateBps int // CLOB fee rate for the YES token, signed into orders
+	NoFeeRateBps  int // CLOB fee rate for the NO token
+}
+
+// EventInfo describes a multi-outcome neg-risk event: a set of binary
+// markets of which exactly one resolves YES, so their YES prices should sum
+// to about 1.
+type EventInfo struct {
+	ID       string
+	Slug     string
+	Title    string
+	Outcomes []EventOutcome
+}
+
+// EventOutcome is one market of an event with its YES reference price from
+// the latest scan.
+type EventOutcome struct {
+	ConditionID string
+	Question    string
+	YesPrice    float64
+}
+
+// FeeRateBps returns the fee rate of one of the market's tokens.
+func (m MarketInfo) FeeRateBps(tokenID string) int {
+	if tokenID == m.NoTokenID {
+		return m.NoFeeRateBps
+	}
+	return m.YesFeeRateBps
 }
 
 // MarketAllocation is emitted by the Scanner to tell the engine which markets
 // to trade and how much capital to allocate. Score is the opportunity ranking
 // used to prioritize when more markets pass filters than MaxMarketsActive.
 type MarketAllocation struct {
-	Market         MarketInfo
-	MaxPositionUSD float64 // per-market position cap (from risk config)
-	Score          float64 // composite opportunity score: spread × √volume × liquidity
+	Market          MarketInfo
+	MaxPositionUSD  float64            // per-market position cap (from risk config)
+	Score           float64            // composite score: sum of ScoreComponents
+	ScoreComponents map[string]float64 // weighted contribution of each scorer
+	RewardYield     float64            // expected daily liquidity rewards per USD quoted
 }
 
 // ————————————————————————————————————————————————————————————————————————
@@ -139,9 +191,10 @@ type UserOrder struct {
 	Price      float64   // limit price (0.0 to 1.0 for binary markets)
 	Size       float64   // quantity in tokens
 	Side       Side      // BUY or SELL
-	OrderType  OrderType // GTC
+	OrderType  OrderType // GTC (default), GTD, FOK or FAK
+	PostOnly   bool      // reject instead of crossing the book (GTC/GTD only)
 	TickSize   TickSize  // market's price granularity (for amount rounding)
-	Expiration int64     // unix timestamp, 0 = no expiry
+	Expiration int64     // unix timestamp, 0 = no expiry (required for GTD)
 	FeeRateBps int       // fee rate in basis points
 }
 
@@ -170,7 +223,7 @@ type SignedOrder struct {
 type OrderPayload struct {
 	Order     SignedOrder `json:"order"`
 	Owner     string      `json:"owner"`              // API key of the order owner
-	OrderType OrderType   `json:"orderType"`          // GTC
+	OrderType OrderType   `json:"orderType"`          // GTC, GTD, FOK or FAK
 	PostOnly  bool        `json:"postOnly,omitempty"` // if true, rejects if it would cross
 }
 
@@ -180,8 +233,48 @@ type OrderResponse struct {
 	ErrorMsg string `json:"errorMsg"`
 	OrderID  string `json:"orderID"`
 	Status   string `json:"status"` // e.g. "live", "matched"
+
+	// Err classifies a rejection (see RejectionError); nil on success.
+	// Filled in by the gateway, not sent by the API.
+	Err error `json:"-"`
+}
+
+// Order rejection reasons. Gateways map the exchange's errorMsg onto these
+// so callers can branch with errors.Is.
+var (
+	ErrOrderRejected       = errors.New("order rejected")
+	ErrWouldCross          = errors.New("post-only order would cross the book")
+	ErrOrderExpired        = errors.New("order expiration invalid or passed")
+	ErrInsufficientBalance = errors.New("not enough balance or allowance")
+	ErrNotFilled           = errors.New("order could not be filled")
+	ErrMarketClosed        = errors.New("market closed or not accepting orders")
+)
+
+// Exchange request failures. Gateways wrap failed requests in errors that
+// match one of these (or ErrInsufficientBalance / ErrMarketClosed) with
+// errors.Is.
+var (
+	ErrRateLimited = errors.New("rate limited")
+	ErrAuthExpired = errors.New("api credentials rejected or expired")
+	ErrValidation  = errors.New("request rejected as invalid")
+)
+
+// RejectionError is an order rejection: one of the Err* reasons above plus
+// the exchange's own message.
+type RejectionError struct {
+	Reason error
+	Msg    string
+}
+
+func (e *RejectionError) Error() string {
+	if e.Msg == "" {
+		return e.Reason.Error()
+	}
+	return e.Reason.Error() + ": " + e.Msg
 }
 
+func (e *RejectionError) Unwrap() error { return e.Reason }
+
 // OpenOrder represents a live resting order on the CLOB.
 type OpenOrder struct {
 	ID           string `json:"id"`
@@ -192,6 +285,39 @@ type OpenOrder struct {
 	OriginalSize string `json:"original_size"` // initial size
 	SizeMatched  string `json:"size_matched"`  // how much has filled
 	Price        string `json:"price"`         // limit price
+	Expiration   string `json:"expiration"`    // unix timestamp, "0" for none
+}
+
+// Trade is one matched trade from GET /data/trades. The top-level side, size
+// and price are the taker's; when we were a maker our side of the match is
+// in MakerOrders (see TraderSide).
+type Trade struct {
+	ID           string       `json:"id"`
+	TakerOrderID string       `json:"taker_order_id"`
+	Market       string       `json:"market"`   // condition ID
+	AssetID      string       `json:"asset_id"` // taker's token ID
+	Side         string       `json:"side"`     // taker's side
+	Size         string       `json:"size"`
+	Price        string       `json:"price"`
+	FeeRateBps   string       `json:"fee_rate_bps"`
+	Status       string       `json:"status"`     // "MATCHED", "MINED", "CONFIRMED", "RETRYING", "FAILED"
+	MatchTime    string       `json:"match_time"` // unix seconds as string
+	Outcome      string       `json:"outcome"`
+	MakerAddress string       `json:"maker_address"`
+	TraderSide   string       `json:"trader_side"` // "TAKER" or "MAKER": our role in this trade
+	MakerOrders  []MakerOrder `json:"maker_orders"`
+}
+
+// MakerOrder is one resting order matched in a Trade.
+type MakerOrder struct {
+	OrderID       string `json:"order_id"`
+	MakerAddress  string `json:"maker_address"`
+	MatchedAmount string `json:"matched_amount"`
+	Price         string `json:"price"`
+	AssetID       string `json:"asset_id"`
+	Side          string `json:"side"`
+	Outcome       string `json:"outcome"`
+	FeeRateBps    string `json:"fee_rate_bps"`
 }
 
 // CancelResponse is returned by DELETE /orders, /cancel-all, /cancel-market-orders.
@@ -201,17 +327,72 @@ type CancelResponse struct {
 
 // QuotePair represents the desired bid and ask the strategy wants active
 // for a single market. Nil Bid or Ask means the strategy wants that side
-// pulled (no order). The engine compares this to current live orders and
-// issues the minimal cancel+place to converge.
+// pulled (no order). When quoting a ladder, BidLevels and AskLevels hold the
+// levels behind Bid and Ask, nearest the touch first. The engine compares
+// this to current live orders and issues the minimal cancel+place to
+// converge.
 type QuotePair struct {
 	MarketID    string
 	YesTokenID  string
 	NoTokenID   string
-	Bid         *UserOrder // buy YES at this price/size, nil = no bid
-	Ask         *UserOrder // sell YES at this price/size, nil = no ask
+	Bid         *UserOrder   // economic buy: buy YES (or sell NO), nil = no bid
+	Ask         *UserOrder   // economic sell: sell YES (or buy NO), nil = no ask
+	BidLevels   []*UserOrder // deeper bids on the same token and side as Bid
+	AskLevels   []*UserOrder // deeper asks on the same token and side as Ask
 	GeneratedAt time.Time
 }
 
+// Orders returns every desired order level by level from the touch
+// outwards, the bid before the ask within a level.
+func (q *QuotePair) Orders() []*UserOrder {
+	var orders []*UserOrder
+	if q.Bid != nil {
+		orders = append(orders, q.Bid)
+	}
+	if q.Ask != nil {
+		orders = append(orders, q.Ask)
+	}
+	for i := 0; i < max(len(q.BidLevels), len(q.AskLevels)); i++ {
+		if i < len(q.BidLevels) {
+			orders = append(orders, q.BidLevels[i])
+		}
+		if i < len(q.AskLevels) {
+			orders = append(orders, q.AskLevels[i])
+		}
+	}
+	return orders
+}
+
+// JournalEventType is the kind of order lifecycle record in the journal.
+type JournalEventType string
+
+const (
+	JournalPlacement   JournalEventType = "placement"    // order submitted to the exchange
+	JournalAck         JournalEventType = "ack"          // exchange accepted the order
+	JournalRejection   JournalEventType = "rejection"    // exchange (or transport) refused the order
+	JournalCancel      JournalEventType = "cancel"       // order removed from the book
+	JournalPartialFill JournalEventType = "partial_fill" // order update with more size matched
+	JournalFill        JournalEventType = "fill"         // order update that completed the order
+	JournalTrade       JournalEventType = "trade"        // our side of a matched trade
+)
+
+// JournalEntry is one append-only order lifecycle record. Fields that don't
+// apply to an event type are left empty (e.g. OrderID on a placement).
+type JournalEntry struct {
+	Time        time.Time        `json:"time"`
+	Type        JournalEventType `json:"type"`
+	Market      string           `json:"market,omitempty"` // condition ID; empty for account-wide cancels
+	OrderID     string           `json:"order_id,omitempty"`
+	TradeID     string           `json:"trade_id,omitempty"`
+	TokenID     string           `json:"token_id,omitempty"`
+	Side        Side             `json:"side,omitempty"`
+	Price       float64          `json:"price,omitempty"`
+	Size        float64          `json:"size,omitempty"`         // order size, or traded size for trades
+	SizeMatched float64          `json:"size_matched,omitempty"` // cumulative, for fill updates
+	Status      string           `json:"status,omitempty"`       // exchange status string
+	Reason      string           `json:"reason,omitempty"`       // rejection error or cancel cause
+}
+
 // ————————————————————————————————————————————————————————————————————————
 // Order book
 // ————————————————————————————————————————————————————————————————————————
@@ -251,7 +432,8 @@ type BookResponse struct {
 // WebSocket events
 // ————————————————————————————————————————————————————————————————————————
 // These structs map 1:1 to the JSON messages sent over the Polymarket WebSocket.
-// Market channel events: "book" (full snapshot), "price_change" (delta).
+// Market channel events: "book" (full snapshot), "price_change" (delta),
+// "last_trade_price" (public trade print).
 // User channel events: "trade" (fill), "order" (placement/cancel lifecycle).
 
 // WSBookEvent is a full order book snapshot from the market WS channel.
@@ -286,18 +468,45 @@ type WSPriceChangeEvent struct {
 	PriceChanges []WSPriceChange `json:"price_changes"`
 }
 
+// WSLastTradePriceEvent is a public trade print from the market WS channel.
+// Emitted whenever a maker and taker order match, regardless of owner.
+type WSLastTradePriceEvent struct {
+	EventType  string `json:"event_type"` // always "last_trade_price"
+	AssetID    string `json:"asset_id"`
+	Market     string `json:"market"`       // condition ID
+	Price      string `json:"price"`        // execution price
+	Size       string `json:"size"`         // executed quantity
+	Side       string `json:"side"`         // taker side: "BUY" lifts asks, "SELL" hits bids
+	FeeRateBps string `json:"fee_rate_bps"` // fee charged on the trade
+	Timestamp  string `json:"timestamp"`    // unix millis as string
+}
+
+// WSMarketResolvedEvent is sent on the market WS channel when a market
+// resolves. Only delivered when the subscription sets custom_feature_enabled.
+type WSMarketResolvedEvent struct {
+	EventType      string   `json:"event_type"` // always "market_resolved"
+	ID             string   `json:"id"`
+	Market         string   `json:"market"` // condition ID
+	AssetIDs       []string `json:"assets_ids"`
+	WinningAssetID string   `json:"winning_asset_id"`
+	WinningOutcome string   `json:"winning_outcome"` // e.g. "Yes"
+	Timestamp      string   `json:"timestamp"`       // unix millis as string
+}
+
 // WSTradeEvent is a fill notification from the user WS channel.
 // Received when one of our orders gets matched against a taker.
 type WSTradeEvent struct {
-	EventType string `json:"event_type"` // always "trade"
-	ID        string `json:"id"`         // trade ID
-	Market    string `json:"market"`     // condition ID
-	AssetID   string `json:"asset_id"`   // token ID that was traded
-	Side      string `json:"side"`       // our side: "BUY" or "SELL"
-	Size      string `json:"size"`       // filled quantity
-	Price     string `json:"price"`      // fill price
-	Outcome   string `json:"outcome"`    // "Yes" or "No"
-	Timestamp string `json:"timestamp"`
+	EventType  string `json:"event_type"`   // always "trade"
+	ID         string `json:"id"`           // trade ID
+	Market     string `json:"market"`       // condition ID
+	AssetID    string `json:"asset_id"`     // token ID that was traded
+	Side       string `json:"side"`         // our side: "BUY" or "SELL"
+	Size       string `json:"size"`         // filled quantity
+	Price      string `json:"price"`        // fill price
+	Outcome    string `json:"outcome"`      // "Yes" or "No"
+	Status     string `json:"status"`       // settlement status, e.g. "MATCHED", "CONFIRMED"
+	FeeRateBps string `json:"fee_rate_bps"` // fee charged on our fill
+	Timestamp  string `json:"timestamp"`
 }
 
 // WSOrderEvent is an order lifecycle notification from the user WS channel.
@@ -325,6 +534,10 @@ type WSSubscribeMsg struct {
 	Type     string   `json:"type"`                 // "market" or "user"
 	Markets  []string `json:"markets,omitempty"`    // condition IDs (user channel)
 	AssetIDs []string `json:"assets_ids,omitempty"` // token IDs (market channel)
+
+	// CustomFeatureEnabled opts the market channel into extra events
+	// such as market_resolved.
+	CustomFeatureEnabled bool `json:"custom_feature_enabled,omitempty"`
 }
 
 // WSAuth contains the L2 API credentials for authenticating the user WS channel.
@@ -340,4 +553,6 @@ type WSUpdateMsg struct {
 	AssetIDs  []string `json:"assets_ids,omitempty"` // token IDs (market channel)
 	Markets   []string `json:"markets,omitempty"`    // condition IDs (user channel)
 	Operation string   `json:"operation"`            // "subscribe" or "unsubscribe"
+
+	CustomFeatureEnabled bool `json:"custom_feature_enabled,omitempty"` // see WSSubscribeMsg
 }